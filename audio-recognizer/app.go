@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,17 +23,26 @@ import (
 type App struct {
 	ctx         context.Context
 	recognitionService recognition.RecognitionService
+	engineName  string                                   // 默认识别引擎名称
+	engineServices map[string]recognition.RecognitionService // 已创建的识别引擎实例（按名称缓存）
 	config      *models.RecognitionConfig
 	isRecognizing bool
+	cancelRecognition context.CancelFunc // 取消当前识别任务
 	mu          sync.RWMutex
+	recognizerMu sync.RWMutex // 识别锁：交互式识别独占，批量识别队列的任务共享
 	thirdPartyFS embed.FS
 	configManager *config.ConfigManager
 	modelService  *services.ModelService
 	audioService  *services.AudioService
 	exportService *services.ExportService
+	vocabularyService *services.VocabularyService // 项目词汇表服务
+	uploadService     *services.UploadService     // 分块上传服务
+	resultStore       *services.ResultStore       // 最近的识别结果（重新识别段落和撤销使用）
+	queueService      *services.QueueService      // 批量识别队列
 	pathManager   *path.PathManager // 新增路径管理器
 	appStatusService *services.AppStatusService // 新增应用状态服务
 	versionService  *services.VersionService    // 新增版本信息服务
+	eventSink       func(eventType string, data interface{}) // 事件接收器，设置后替代Wails运行时（用于集成测试）
 }
 
 // NewApp creates a new App application struct
@@ -48,16 +58,31 @@ func NewApp(thirdParty embed.FS) *App {
 		FS: thirdParty,
 	})
 
-	// 创建导出服务
-	exportService := services.NewExportService()
+	// 创建导出服务（自定义文稿模板保存在配置目录下）
+	exportService := services.NewExportService(filepath.Join(configManager.GetConfigDirectory(), "export_templates"))
 
-	return &App{
+	// 创建词汇表服务（词汇表保存在配置目录下）
+	vocabularyService := services.NewVocabularyService(filepath.Join(configManager.GetConfigDirectory(), "vocabularies"))
+
+	// 创建分块上传服务，并应用配置中的文件大小上限
+	uploadService := services.NewUploadService(filepath.Join(os.TempDir(), "audio-recognizer", "uploads"))
+	utils.SetMaxFileSize(int64(config.MaxFileSizeMB) << 20)
+
+	app := &App{
 		config:       config,
 		thirdPartyFS: thirdParty,
 		configManager: configManager,
 		pathManager:  pathManager,
 		exportService: exportService,
+		vocabularyService: vocabularyService,
+		uploadService:     uploadService,
+		resultStore:       services.NewResultStore(),
 	}
+
+	// 创建批量识别队列，任务事件与识别事件使用同一通道发送
+	app.queueService = services.NewQueueService(app.runQueueJob, app.sendProgressEvent)
+	app.queueService.SetConcurrency(config.QueueConcurrency)
+	return app
 }
 
 // startup is called when the app starts. The context is saved
@@ -124,8 +149,12 @@ func (a *App) startup(ctx context.Context) {
 
 // initializeVoskService 初始化语音识别服务
 func (a *App) initializeVoskService() error {
-	// 尝试使用Whisper服务
-	service, err := recognition.NewWhisperService(a.config)
+	// 按配置选择识别引擎，未配置时按优先级自动选择（whisper-server优先于whisper-cli）
+	service, engineName, err := recognition.NewEngine(a.config.Engine, a.config)
+	if err != nil && a.config.Engine != "" {
+		fmt.Printf("识别引擎 %s 初始化失败，改为自动选择: %v\n", a.config.Engine, err)
+		service, engineName, err = recognition.NewEngine("", a.config)
+	}
 	if err != nil {
 		fmt.Printf("Whisper服务初始化失败: %v\n", err)
 
@@ -133,6 +162,14 @@ func (a *App) initializeVoskService() error {
 	}
 
 	a.recognitionService = service
+	a.engineName = engineName
+	a.engineServices = map[string]recognition.RecognitionService{engineName: service}
+	utils.LogInfo("使用识别引擎: %s", engineName)
+
+	// 预加载模型，常驻进程类引擎（如whisper-server）首次识别无需等待模型加载
+	if err := service.LoadModel(a.config.Language, a.config.ModelPath); err != nil {
+		utils.LogWarn("预加载模型失败: %v", err)
+	}
 
 	// 现在初始化应用状态服务
 	a.appStatusService = services.NewAppStatusServiceWithConfig(a.modelService, a.recognitionService, a.config)
@@ -141,6 +178,61 @@ func (a *App) initializeVoskService() error {
 	return nil
 }
 
+// shutdown 应用退出时关闭识别服务（终止whisper-server等后台进程）
+func (a *App) shutdown(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cancelRecognition != nil {
+		a.cancelRecognition()
+	}
+	a.queueService.Close()
+	for name, service := range a.engineServices {
+		if err := service.Close(); err != nil {
+			utils.LogError("关闭识别引擎 %s 失败: %v", name, err)
+		}
+	}
+	a.uploadService.Cleanup()
+	a.resultStore.Cleanup()
+	utils.LogInfo("=== 听声辨字应用程序退出 ===")
+}
+
+// engineServiceLocked 获取识别引擎实例，首次使用时创建并缓存（调用方需持有a.mu）
+// 优先使用请求指定的引擎，其次为配置中的引擎，都未指定时使用默认引擎
+func (a *App) engineServiceLocked(requestEngine string) (recognition.RecognitionService, string, error) {
+	name := requestEngine
+	if name == "" {
+		name = a.config.Engine
+	}
+	if name == "" || name == a.engineName {
+		if a.recognitionService == nil {
+			return nil, "", fmt.Errorf("语音识别服务未初始化")
+		}
+		return a.recognitionService, a.engineName, nil
+	}
+
+	if service, ok := a.engineServices[name]; ok {
+		return service, name, nil
+	}
+
+	service, _, err := recognition.NewEngine(name, a.config)
+	if err != nil {
+		// 配置中的引擎不可用时回退到默认引擎，请求中显式指定的引擎则直接报错
+		if requestEngine == "" && a.recognitionService != nil {
+			fmt.Printf("⚠️ 配置的识别引擎不可用，使用默认引擎 %s: %v\n", a.engineName, err)
+			return a.recognitionService, a.engineName, nil
+		}
+		return nil, "", err
+	}
+
+	if a.engineServices == nil {
+		a.engineServices = make(map[string]recognition.RecognitionService)
+	}
+	a.engineServices[name] = service
+	utils.LogInfo("创建识别引擎: %s", name)
+	return service, name, nil
+}
+
 // GetAppRootDirectory 获取应用根目录（委托给路径管理器）
 func (a *App) GetAppRootDirectory() string {
 	return a.pathManager.GetAppRootDirectory()
@@ -152,8 +244,14 @@ type RecognitionRequest struct {
 	FilePath          string                 `json:"filePath"`
 	FileData          string                 `json:"fileData,omitempty"`          // Base64编码的文件数据（拖拽功能使用）
 	Language          string                 `json:"language"`
-	Options           map[string]interface{} `json:"options"`
+	Options           map[string]interface{} `json:"options"`                     // 识别选项，与DecodingOptions同名的字段覆盖配置中的解码参数
 	SpecificModelFile string                 `json:"specificModelFile,omitempty"` // 用户指定的具体模型文件
+	Engine            string                 `json:"engine,omitempty"`            // 识别引擎名称（可选，默认使用配置中的引擎）
+	Task              string                 `json:"task,omitempty"`              // 识别任务: transcribe | translate | bilingual（默认transcribe）
+	Vocabulary        string                 `json:"vocabulary,omitempty"`        // 项目词汇表名称（可选，默认使用配置中的词汇表）
+	Diarization       *models.DiarizationConfig `json:"diarization,omitempty"`    // 说话人分离参数（可选，默认使用配置）
+	AudioTrack        *int                   `json:"audioTrack,omitempty"`        // 多音轨视频使用的音轨序号（可选，默认按语言和默认标记自动选择）
+	TimeRanges        []models.TimeRange     `json:"timeRanges,omitempty"`        // 只识别的时间范围（可选，默认识别整个文件），结果时间戳对应原始文件
 }
 
 // RecognitionResponse 识别响应
@@ -186,25 +284,142 @@ func (a *App) StartRecognition(request RecognitionRequest) RecognitionResponse {
 		}
 	}
 
-	if a.recognitionService == nil {
+	prepared, recognitionErr := a.prepareRecognitionLocked(request)
+	if recognitionErr != nil {
 		return RecognitionResponse{
 			Success: false,
-			Error: models.NewRecognitionError(
-				models.ErrorCodeRecognitionFailed,
-				"语音识别服务未初始化",
-				"",
-			),
+			Error:   recognitionErr,
 		}
 	}
 
+	a.isRecognizing = true
+
+	// 创建可取消的识别上下文，StopRecognition通过它终止外部进程
+	parentCtx := a.ctx
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+	recognitionCtx, cancel := context.WithCancel(parentCtx)
+	a.cancelRecognition = cancel
+
+	// 启动异步识别
+	go a.performRecognition(recognitionCtx, prepared.service, prepared.engineName, request, prepared.language, prepared.options)
+
+	return RecognitionResponse{
+		Success: true,
+	}
+}
+
+// preparedRecognition 校验后的识别任务参数
+type preparedRecognition struct {
+	service    recognition.RecognitionService
+	engineName string
+	language   string
+	options    models.RecognitionOptions
+}
+
+// prepareRecognitionLocked 重新加载配置，选择识别引擎，校验识别请求并确保模型已加载（调用方需持有a.mu）
+// 重新加载的配置只用于本次识别，不替换a.config（队列中的其他任务可能正在使用）
+func (a *App) prepareRecognitionLocked(request RecognitionRequest) (*preparedRecognition, *models.RecognitionError) {
 	// 🔧 重新加载最新配置（确保每次识别都使用最新设置）
 	fmt.Printf("🔄 重新加载配置文件以获取最新设置...\n")
 	latestConfig := a.configManager.LoadDefaultConfig()
+	utils.SetMaxFileSize(int64(latestConfig.MaxFileSizeMB) << 20)
+
+	// 选择识别引擎
+	service, engineName, err := a.engineServiceLocked(request.Engine)
+	if err != nil {
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeRecognitionFailed,
+			"识别引擎不可用",
+			err.Error(),
+		)
+	}
+	fmt.Printf("🔧 使用识别引擎: %s\n", engineName)
+
+	// 校验识别任务，翻译任务需要引擎支持
+	task, err := recognition.NormalizeTask(request.Task)
+	if err != nil {
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeInvalidConfig,
+			"无效的识别任务",
+			err.Error(),
+		)
+	}
+	if task != models.TaskTranscribe {
+		if descriptor, ok := recognition.GetEngine(engineName); ok && !descriptor.Capabilities.Translation {
+			return nil, models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"识别引擎不支持翻译",
+				fmt.Sprintf("引擎 %s 不支持 %s 任务", engineName, task),
+			)
+		}
+	}
+
+	// 合并本次识别的解码参数
+	decoding, err := recognition.MergeDecodingOverrides(latestConfig.Decoding, request.Options)
+	if err == nil {
+		decoding, err = recognition.ResolveDecodingOptions(decoding)
+	}
+	if err != nil {
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeInvalidConfig,
+			"无效的解码参数",
+			err.Error(),
+		)
+	}
+	options := models.RecognitionOptions{
+		Task:              task,
+		SpecificModelFile: request.SpecificModelFile,
+		Decoding:          &decoding,
+	}
+
+	// 校验说话人分离参数，需要引擎支持
+	diarizationConfig := latestConfig.Diarization
+	if request.Diarization != nil {
+		diarizationConfig = *request.Diarization
+	}
+	diarization, err := recognition.ResolveDiarizationConfig(diarizationConfig)
+	if err != nil {
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeInvalidConfig,
+			"无效的说话人分离参数",
+			err.Error(),
+		)
+	}
+	if diarization.Mode != recognition.DiarizationOff {
+		if descriptor, ok := recognition.GetEngine(engineName); ok && !descriptor.Capabilities.Diarization {
+			return nil, models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"识别引擎不支持说话人分离",
+				fmt.Sprintf("引擎 %s 不支持说话人分离", engineName),
+			)
+		}
+	}
+	options.Diarization = &diarization
+	options.AudioTrack = request.AudioTrack
+	options.TimeRanges = request.TimeRanges
+
+	// 加载项目词汇表
+	vocabularyName := request.Vocabulary
+	if vocabularyName == "" {
+		vocabularyName = latestConfig.Vocabulary
+	}
+	if vocabularyName != "" {
+		vocabulary, err := a.vocabularyService.Get(vocabularyName)
+		if err != nil {
+			return nil, models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"加载词汇表失败",
+				err.Error(),
+			)
+		}
+		options.Vocabulary = vocabulary
+		fmt.Printf("📚 使用词汇表: %s (%d 个术语)\n", vocabulary.Name, len(vocabulary.Terms))
+	}
 
-	// 更新内存中的配置
-	a.config = latestConfig
 	// 更新识别服务的配置
-	a.recognitionService.UpdateConfig(latestConfig)
+	service.UpdateConfig(latestConfig)
 	fmt.Printf("✅ 已重新加载配置: 语言=%s, 模型路径=%s, 特定模型=%s\n",
 		latestConfig.Language, latestConfig.ModelPath, latestConfig.SpecificModelFile)
 
@@ -212,27 +427,24 @@ func (a *App) StartRecognition(request RecognitionRequest) RecognitionResponse {
 	if request.FileData == "" {
 		// 只有在没有Base64数据时才检查文件路径
 		if _, err := os.Stat(request.FilePath); os.IsNotExist(err) {
-			return RecognitionResponse{
-				Success: false,
-				Error: models.NewRecognitionError(
-					models.ErrorCodeAudioFileNotFound,
-					"音频文件未找到",
-					request.FilePath,
-				),
-			}
+			return nil, models.NewRecognitionError(
+				models.ErrorCodeAudioFileNotFound,
+				"音频文件未找到",
+				request.FilePath,
+			)
 		}
 	}
 
 	// 设置识别语言
 	language := request.Language
 	if language == "" {
-		language = a.config.Language
+		language = latestConfig.Language
 	}
 
 	// 确保模型已加载
-	if !a.recognitionService.IsModelLoaded(language) {
+	if !service.IsModelLoaded(language) {
 		// 确定模型路径：优先使用用户指定的模型文件所在目录
-		modelPath := a.config.ModelPath
+		modelPath := latestConfig.ModelPath
 		if request.SpecificModelFile != "" {
 			// 从用户指定的模型文件路径中提取目录
 			modelDir := filepath.Dir(request.SpecificModelFile)
@@ -250,87 +462,143 @@ func (a *App) StartRecognition(request RecognitionRequest) RecognitionResponse {
 		fmt.Printf("🔄 最终使用的模型路径: %s\n", modelPath)
 		fmt.Printf("🔄 识别语言: %s\n", language)
 
-		if err := a.recognitionService.LoadModel(language, modelPath); err != nil {
-			return RecognitionResponse{
-				Success: false,
-				Error: models.NewRecognitionError(
-					models.ErrorCodeModelLoadFailed,
-					"语音模型加载失败",
-					err.Error(),
-				),
-			}
+		if err := service.LoadModel(language, modelPath); err != nil {
+			return nil, models.NewRecognitionError(
+				models.ErrorCodeModelLoadFailed,
+				"语音模型加载失败",
+				err.Error(),
+			)
 		}
 	}
 
-	a.isRecognizing = true
-
-	// 启动异步识别
-	go a.performRecognition(request, language)
-
-	return RecognitionResponse{
-		Success: true,
-	}
+	return &preparedRecognition{
+		service:    service,
+		engineName: engineName,
+		language:   language,
+		options:    options,
+	}, nil
 }
 
 // performRecognition 执行语音识别
-func (a *App) performRecognition(request RecognitionRequest, language string) {
-	defer func() {
-		a.mu.Lock()
-		a.isRecognizing = false
-		a.mu.Unlock()
-	}()
+func (a *App) performRecognition(ctx context.Context, service recognition.RecognitionService, engineName string, request RecognitionRequest, language string, options models.RecognitionOptions) {
+	defer a.finishRecognition()
+
+	unlock, err := a.lockRecognizer(ctx, true, a.sendProgressEventWithCallback())
+	if err != nil {
+		a.handleRecognitionCancelled()
+		return
+	}
+	defer unlock()
 
 	a.sendProgressEvent("recognition_progress", &models.RecognitionProgress{
 		Status:     "正在准备音频文件...",
 		Percentage: 0,
 	})
 
-	result, err := a.executeRecognition(request, language)
+	result, sourcePath, err := a.executeRecognition(ctx, service, request, language, options)
+
+	// 已取消的识别不再发送结果
+	if ctx.Err() != nil || isCancelledError(err) {
+		a.handleRecognitionCancelled()
+		return
+	}
 
 	if err != nil {
 		a.handleRecognitionError(err)
 		return
 	}
 
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Metadata["engine"] = engineName
+
+	// 保存结果，之后可以重新识别其中的段落
+	a.resultStore.Put(result, services.ResultSource{
+		Path:      sourcePath,
+		Temporary: request.FileData != "",
+		Language:  language,
+		Engine:    engineName,
+		Options:   options,
+	})
+
 	a.handleRecognitionSuccess(result)
 }
 
-// executeRecognition 执行识别的核心逻辑
-func (a *App) executeRecognition(request RecognitionRequest, language string) (*models.RecognitionResult, error) {
+// lockRecognizer 获取识别锁，exclusive为true时独占（交互式识别），否则与其他队列任务共享
+// 需要等待时通过progress通知，等待期间ctx被取消时返回取消错误；返回的函数用于释放锁
+func (a *App) lockRecognizer(ctx context.Context, exclusive bool, progress func(*models.RecognitionProgress)) (func(), error) {
+	lock, tryLock, unlock := a.recognizerMu.RLock, a.recognizerMu.TryRLock, a.recognizerMu.RUnlock
+	status := "正在等待当前识别完成..."
+	if exclusive {
+		lock, tryLock, unlock = a.recognizerMu.Lock, a.recognizerMu.TryLock, a.recognizerMu.Unlock
+		status = "正在等待批量识别队列中的任务完成..."
+	}
+	if tryLock() {
+		return unlock, nil
+	}
+	if progress != nil {
+		progress(&models.RecognitionProgress{Status: status})
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		lock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		return unlock, nil
+	case <-ctx.Done():
+		// 锁稍后获取到时立即释放
+		go func() {
+			<-acquired
+			unlock()
+		}()
+		return nil, models.NewRecognitionError(models.ErrorCodeRecognitionCancelled, "语音识别已取消", ctx.Err().Error())
+	}
+}
+
+// finishRecognition 识别任务结束后重置识别状态
+func (a *App) finishRecognition() {
+	a.mu.Lock()
+	a.isRecognizing = false
+	if a.cancelRecognition != nil {
+		a.cancelRecognition()
+		a.cancelRecognition = nil
+	}
+	a.mu.Unlock()
+}
+
+// executeRecognition 执行识别的核心逻辑，返回识别结果和实际识别的文件路径
+func (a *App) executeRecognition(ctx context.Context, service recognition.RecognitionService, request RecognitionRequest, language string, options models.RecognitionOptions) (*models.RecognitionResult, string, error) {
 	var filePath string
-	var cleanup func()
 
 	// 处理拖拽文件（Base64数据）
 	if request.FileData != "" {
 		tempFile, err := a.handleDragDropFile(request.FileData)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		filePath = tempFile
-		cleanup = func() { os.Remove(tempFile) }
 	} else {
 		filePath = request.FilePath
 	}
 
-	if cleanup != nil {
-		defer cleanup()
-	}
-
 	// 执行识别
-	if request.SpecificModelFile != "" {
-		return a.recognitionService.RecognizeFileWithModel(
-			filePath,
-			language,
-			request.SpecificModelFile,
-			a.sendProgressEventWithCallback(),
-		)
-	}
-
-	return a.recognitionService.RecognizeFile(
+	result, err := service.RecognizeFileWithOptions(
+		ctx,
 		filePath,
 		language,
+		options,
 		a.sendProgressEventWithCallback(),
 	)
+
+	// 识别成功时拖拽生成的临时文件交给结果存储管理（重新识别段落时还要使用），否则立即删除
+	if request.FileData != "" && (err != nil || ctx.Err() != nil) {
+		os.Remove(filePath)
+	}
+	return result, filePath, err
 }
 
 // handleDragDropFile 处理拖拽文件
@@ -342,7 +610,7 @@ func (a *App) handleDragDropFile(base64Data string) (string, error) {
 
 	tempFile, err := a.createTempFileFromBase64(base64Data)
 	if err != nil {
-		return "", fmt.Errorf("拖拽文件处理失败: %v", err)
+		return "", fmt.Errorf("拖拽文件处理失败: %w", err)
 	}
 
 	a.sendProgressEvent("recognition_progress", &models.RecognitionProgress{
@@ -356,6 +624,13 @@ func (a *App) handleDragDropFile(base64Data string) (string, error) {
 // handleRecognitionError 处理识别错误
 func (a *App) handleRecognitionError(err error) {
 	errorMsg := models.NewRecognitionError(models.ErrorCodeRecognitionFailed, "语音识别失败", err.Error())
+	// 磁盘空间不足时使用专门的错误代码，便于前端提示清理空间
+	var recognitionErr *models.RecognitionError
+	if errors.As(err, &recognitionErr) && recognitionErr.Code == models.ErrorCodeDiskSpaceFull {
+		errorMsg = recognitionErr
+	} else if errors.Is(err, models.ErrDiskSpaceFull) {
+		errorMsg = models.NewRecognitionError(models.ErrorCodeDiskSpaceFull, "磁盘空间不足", err.Error())
+	}
 	a.sendProgressEvent("recognition_error", errorMsg)
 	a.sendProgressEvent("recognition_complete", RecognitionResponse{
 		Success: false,
@@ -363,6 +638,31 @@ func (a *App) handleRecognitionError(err error) {
 	})
 }
 
+// handleRecognitionCancelled 处理识别取消
+func (a *App) handleRecognitionCancelled() {
+	utils.LogInfo("语音识别已取消")
+	a.sendProgressEvent("recognition_cancelled", RecognitionResponse{
+		Success: false,
+		Error: models.NewRecognitionError(
+			models.ErrorCodeRecognitionCancelled,
+			"语音识别已取消",
+			"",
+		),
+	})
+}
+
+// isCancelledError 判断错误是否由取消识别引起
+func isCancelledError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return true
+	}
+	var recognitionErr *models.RecognitionError
+	return errors.As(err, &recognitionErr) && recognitionErr.Code == models.ErrorCodeRecognitionCancelled
+}
+
 // handleRecognitionSuccess 处理识别成功
 func (a *App) handleRecognitionSuccess(result *models.RecognitionResult) {
 	// 发送结果事件
@@ -396,6 +696,11 @@ func (a *App) debugRecognitionResult(result *models.RecognitionResult) {
 func (a *App) sendProgressEventWithCallback() func(*models.RecognitionProgress) {
 	return func(progress *models.RecognitionProgress) {
 		a.sendProgressEvent("recognition_progress", progress)
+
+		// 实时解码出的段落单独发送，便于前端边识别边显示
+		if progress != nil && progress.Partial != nil {
+			a.sendProgressEvent("recognition_partial", progress.Partial)
+		}
 	}
 }
 
@@ -415,8 +720,11 @@ func (a *App) StopRecognition() RecognitionResponse {
 		}
 	}
 
-	// 注意：Vosk API没有直接停止识别的方法，这里只是标记状态
-	a.isRecognizing = false
+	// 取消识别上下文，终止正在运行的FFmpeg/whisper-cli进程
+	// isRecognizing 由识别协程在清理完临时文件后复位，避免新任务与旧进程并存
+	if a.cancelRecognition != nil {
+		a.cancelRecognition()
+	}
 
 	a.sendProgressEvent("stopped", nil)
 
@@ -439,6 +747,10 @@ func (a *App) GetRecognitionStatus() map[string]interface{} {
 			}
 			return []string{}
 		}(),
+		"languageOptions": recognition.GetLanguageTable(),
+		"currentEngine":   a.engineName,
+		"engines":         recognition.ListEngines(),
+		"decodingPresets": recognition.GetDecodingPresets(),
 	}
 }
 
@@ -484,6 +796,54 @@ func (a *App) UpdateConfig(configJSON string) RecognitionResponse {
 	fmt.Printf("✅ 配置解析成功: 语言=%s, 模型路径=%s, 特定模型=%s\n",
 		config.Language, config.ModelPath, config.SpecificModelFile)
 
+	// 校验解码参数
+	if _, err := recognition.ResolveDecodingOptions(config.Decoding); err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"无效的解码参数",
+				err.Error(),
+			),
+		}
+	}
+
+	// 校验说话人分离参数
+	if _, err := recognition.ResolveDiarizationConfig(config.Diarization); err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"无效的说话人分离参数",
+				err.Error(),
+			),
+		}
+	}
+
+	if config.MaxFileSizeMB < 0 {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"无效的文件大小上限",
+				fmt.Sprintf("maxFileSizeMB: %d", config.MaxFileSizeMB),
+			),
+		}
+	}
+	utils.SetMaxFileSize(int64(config.MaxFileSizeMB) << 20)
+
+	if config.QueueConcurrency < 0 || config.QueueConcurrency > services.MaxQueueConcurrency {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"无效的队列并发数",
+				fmt.Sprintf("queueConcurrency: %d（0-%d）", config.QueueConcurrency, services.MaxQueueConcurrency),
+			),
+		}
+	}
+	a.queueService.SetConcurrency(config.QueueConcurrency)
+
 	// 验证并修复模型路径
 	a.configManager.ValidateAndFixModelPath(&config)
 
@@ -500,10 +860,12 @@ func (a *App) UpdateConfig(configJSON string) RecognitionResponse {
 	a.config = &config
 	a.mu.Unlock()
 
-	// 更新识别服务配置
-	if a.recognitionService != nil {
-		a.recognitionService.UpdateConfig(&config)
+	// 更新所有已创建的识别引擎配置
+	a.mu.RLock()
+	for _, service := range a.engineServices {
+		service.UpdateConfig(&config)
 	}
+	a.mu.RUnlock()
 
 	fmt.Printf("✅ 配置已更新并保存\n")
 
@@ -607,8 +969,86 @@ func (a *App) GetAudioDuration(filePath string) map[string]interface{} {
 	return a.audioService.GetAudioDuration(filePath)
 }
 
+// BeginUpload 开始分块上传（前端无法提供文件路径时使用），返回上传ID和建议的分块大小
+func (a *App) BeginUpload(name string, size int64) map[string]interface{} {
+	uploadID, err := a.uploadService.Begin(name, size)
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+			"code":    uploadErrorCode(err),
+		}
+	}
+
+	return map[string]interface{}{
+		"success":     true,
+		"uploadId":    uploadID,
+		"chunkSize":   services.UploadChunkSize,
+		"maxFileSize": utils.GetMaxFileSize(),
+	}
+}
+
+// AppendUpload 写入一个分块，chunkBase64为Base64编码的分块数据，checksum为分块的SHA-256（可选）
+func (a *App) AppendUpload(uploadID string, offset int64, chunkBase64 string, checksum string) map[string]interface{} {
+	received, err := a.uploadService.Append(uploadID, offset, chunkBase64, checksum)
+	if err != nil {
+		return map[string]interface{}{
+			"success":  false,
+			"error":    err.Error(),
+			"received": received,
+		}
+	}
+
+	return map[string]interface{}{
+		"success":  true,
+		"received": received,
+	}
+}
+
+// CommitUpload 完成分块上传，返回的文件路径可直接作为识别请求的filePath
+func (a *App) CommitUpload(uploadID string, checksum string) map[string]interface{} {
+	file, err := a.uploadService.Commit(uploadID, checksum)
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"file":    file,
+	}
+}
+
+// CancelUpload 取消分块上传，或删除已上传完成的临时文件
+func (a *App) CancelUpload(uploadID string) map[string]interface{} {
+	if err := a.uploadService.Cancel(uploadID); err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+	return map[string]interface{}{
+		"success": true,
+	}
+}
+
+// uploadErrorCode 上传错误对应的错误代码
+func uploadErrorCode(err error) string {
+	if errors.Is(err, models.ErrDiskSpaceFull) {
+		return models.ErrorCodeDiskSpaceFull
+	}
+	return models.ErrorCodeFileValidationFailed
+}
+
 // ExportResult 导出识别结果
 func (a *App) ExportResult(resultJSON, format, outputPath string) RecognitionResponse {
+	return a.ExportResultWithOptions(resultJSON, format, outputPath, models.ExportOptions{})
+}
+
+// ExportResultWithOptions 按导出选项导出识别结果（字幕行长度、阅读速度、时长限制、说话人和译文等），未设置的选项使用默认值
+func (a *App) ExportResultWithOptions(resultJSON, format, outputPath string, options models.ExportOptions) RecognitionResponse {
 	if a.exportService == nil {
 		return RecognitionResponse{
 			Success: false,
@@ -620,7 +1060,7 @@ func (a *App) ExportResult(resultJSON, format, outputPath string) RecognitionRes
 		}
 	}
 
-	err := a.exportService.ExportResult(resultJSON, format, outputPath)
+	err := a.exportService.ExportResultWithOptions(resultJSON, format, outputPath, options)
 	if err != nil {
 		return RecognitionResponse{
 			Success: false,
@@ -633,8 +1073,530 @@ func (a *App) ExportResult(resultJSON, format, outputPath string) RecognitionRes
 	}
 }
 
+// InstallExportTemplates 将内置的Markdown和HTML文稿模板复制到模板目录供用户修改（已存在的模板不覆盖）
+// 模板目录中放置 transcript.docx 时，导出DOCX使用其中的样式
+func (a *App) InstallExportTemplates() map[string]interface{} {
+	if a.exportService == nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   "导出服务未初始化",
+		}
+	}
+
+	installed, err := a.exportService.InstallTemplates()
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+	return map[string]interface{}{
+		"success":   true,
+		"directory": a.exportService.TemplateDirectory(),
+		"installed": installed,
+	}
+}
+
+// RenameSpeaker 重命名识别结果中的说话人（如将"Speaker 1"改为参会人姓名），新名称已存在时两个说话人合并
+func (a *App) RenameSpeaker(resultJSON, speaker, name string) map[string]interface{} {
+	var result models.RecognitionResult
+	if err := json.Unmarshal([]byte(resultJSON), &result); err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("识别结果格式错误: %v", err),
+		}
+	}
+
+	renamed, err := recognition.RenameSpeaker(&result, speaker, name)
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+
+	utils.LogInfo("说话人已重命名: %s -> %s (%d 个段落)", speaker, name, renamed)
+	return map[string]interface{}{
+		"success":  true,
+		"result":   result,
+		"renamed":  renamed,
+		"speakers": result.Metadata["speakers"],
+	}
+}
+
+// RerecognizeSegments 用指定模型重新识别结果中的部分段落，识别完成后替换回保存的结果
+// 进度和结果通过与StartRecognition相同的事件通知，替换前的版本可用 UndoRerecognition 恢复
+// options中与解码参数同名的字段覆盖原识别的解码参数，padding为段落前后扩展的时长(秒)
+func (a *App) RerecognizeSegments(resultID string, segmentIndexes []int, modelFile string, options map[string]interface{}) RecognitionResponse {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.isRecognizing {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				"RECOGNITION_IN_PROGRESS",
+				"语音识别正在进行中",
+				"",
+			),
+		}
+	}
+
+	result, source, err := a.resultStore.Get(resultID)
+	if err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error:   models.NewRecognitionError(models.ErrorCodeResultNotFound, "识别结果不存在", err.Error()),
+		}
+	}
+	if _, err := os.Stat(source.Path); err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error:   models.NewRecognitionError(models.ErrorCodeAudioFileNotFound, "音频文件未找到", source.Path),
+		}
+	}
+
+	padding := recognition.DefaultRerecognizePadding
+	if value, ok := options["padding"].(float64); ok {
+		padding = value
+	}
+	plan, err := recognition.PlanRerecognition(result, segmentIndexes, padding)
+	if err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error:   models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的重新识别段落", err.Error()),
+		}
+	}
+
+	// 沿用原识别的任务、词汇表、音轨和解码参数，只替换模型和截取的时间范围
+	rerunOptions := source.Options
+	decoding := a.config.Decoding
+	if rerunOptions.Decoding != nil {
+		decoding = *rerunOptions.Decoding
+	}
+	decoding, err = recognition.MergeDecodingOverrides(decoding, options)
+	if err == nil {
+		decoding, err = recognition.ResolveDecodingOptions(decoding)
+	}
+	if err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error:   models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的解码参数", err.Error()),
+		}
+	}
+	rerunOptions.Decoding = &decoding
+	rerunOptions.TimeRanges = plan.TimeRanges
+	// 只识别少量段落无法重新聚类说话人，新段落沿用原段落的说话人
+	rerunOptions.Diarization = &models.DiarizationConfig{Mode: recognition.DiarizationOff}
+	if modelFile != "" {
+		rerunOptions.SpecificModelFile = modelFile
+	}
+	usedModel := rerunOptions.SpecificModelFile
+	if usedModel == "" {
+		usedModel, _ = result.Metadata["model_file"].(string)
+	}
+
+	service, engineName, err := a.engineServiceLocked(source.Engine)
+	if err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error:   models.NewRecognitionError(models.ErrorCodeRecognitionFailed, "识别引擎不可用", err.Error()),
+		}
+	}
+	if !service.IsModelLoaded(source.Language) {
+		modelPath := a.config.ModelPath
+		if rerunOptions.SpecificModelFile != "" {
+			modelPath = filepath.Dir(rerunOptions.SpecificModelFile)
+		}
+		if err := service.LoadModel(source.Language, modelPath); err != nil {
+			return RecognitionResponse{
+				Success: false,
+				Error:   models.NewRecognitionError(models.ErrorCodeModelLoadFailed, "语音模型加载失败", err.Error()),
+			}
+		}
+	}
+	fmt.Printf("🔁 重新识别 %d 个段落（%d 个时间范围），引擎: %s，模型: %s\n",
+		len(segmentIndexes), len(plan.TimeRanges), engineName, usedModel)
+
+	a.isRecognizing = true
+	parentCtx := a.ctx
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+	recognitionCtx, cancel := context.WithCancel(parentCtx)
+	a.cancelRecognition = cancel
+
+	go a.performRerecognition(recognitionCtx, service, resultID, result, plan, source, rerunOptions, usedModel)
+
+	return RecognitionResponse{
+		Success: true,
+	}
+}
+
+// performRerecognition 执行段落重新识别并替换回保存的结果
+func (a *App) performRerecognition(ctx context.Context, service recognition.RecognitionService, resultID string, original *models.RecognitionResult, plan *recognition.RerecognizePlan, source services.ResultSource, options models.RecognitionOptions, modelFile string) {
+	defer a.finishRecognition()
+
+	unlock, err := a.lockRecognizer(ctx, true, a.sendProgressEventWithCallback())
+	if err != nil {
+		a.handleRecognitionCancelled()
+		return
+	}
+	defer unlock()
+
+	a.sendProgressEvent("recognition_progress", &models.RecognitionProgress{
+		Status:     "正在重新识别段落...",
+		Percentage: 0,
+	})
+
+	replacement, err := service.RecognizeFileWithOptions(ctx, source.Path, source.Language, options, a.sendProgressEventWithCallback())
+	if ctx.Err() != nil || isCancelledError(err) {
+		a.handleRecognitionCancelled()
+		return
+	}
+	if err != nil {
+		a.handleRecognitionError(err)
+		return
+	}
+
+	// 识别期间结果被撤销时，段落序号已经对不上
+	current, _, err := a.resultStore.Get(resultID)
+	if err == nil && current != original {
+		err = fmt.Errorf("识别结果在重新识别期间已被修改，请重新选择段落")
+	}
+	if err != nil {
+		a.handleRecognitionError(err)
+		return
+	}
+
+	updated, record, err := recognition.SpliceRerecognition(current, plan, replacement, modelFile)
+	if err != nil {
+		a.handleRecognitionError(err)
+		return
+	}
+	if err := a.resultStore.Update(resultID, updated); err != nil {
+		a.handleRecognitionError(err)
+		return
+	}
+
+	utils.LogInfo("段落重新识别完成: 替换 %d 组，保留 %d 组", len(record.Revisions), len(record.Unchanged))
+	a.handleRecognitionSuccess(updated)
+}
+
+// UndoRerecognition 撤销识别结果最近一次的段落重新识别
+func (a *App) UndoRerecognition(resultID string) map[string]interface{} {
+	result, remaining, err := a.resultStore.Undo(resultID)
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+
+	utils.LogInfo("已撤销段落重新识别: %s (剩余 %d 步)", resultID, remaining)
+	return map[string]interface{}{
+		"success":   true,
+		"result":    result,
+		"remaining": remaining,
+	}
+}
+
+// EnqueueFiles 将多个文件加入批量识别队列，request为每个文件使用的识别参数（filePath和fileData被忽略）
+func (a *App) EnqueueFiles(filePaths []string, request RecognitionRequest) map[string]interface{} {
+	return a.enqueuePaths(filePaths, request)
+}
+
+// EnqueueFolder 将文件夹中的音频和视频文件加入批量识别队列，directory为空时弹出文件夹选择对话框
+func (a *App) EnqueueFolder(directory string, recursive bool, request RecognitionRequest) map[string]interface{} {
+	if directory == "" {
+		if a.audioService == nil {
+			return map[string]interface{}{
+				"success": false,
+				"error":   "音频服务未初始化",
+			}
+		}
+		selected, err := a.audioService.SelectAudioFolder()
+		if err != nil {
+			return map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
+			}
+		}
+		if selected == "" {
+			return map[string]interface{}{
+				"success": false,
+				"error":   "未选择文件夹",
+			}
+		}
+		directory = selected
+	}
+
+	files, err := utils.FindMediaFiles(directory, recursive)
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+	fmt.Printf("📂 文件夹 %s 中找到 %d 个音视频文件\n", directory, len(files))
+	return a.enqueuePaths(files, request)
+}
+
+// EnqueueGlob 将匹配通配符（如 /data/2025-01-*/*.m4a）的音频和视频文件加入批量识别队列
+func (a *App) EnqueueGlob(pattern string, request RecognitionRequest) map[string]interface{} {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("无效的通配符: %v", err),
+		}
+	}
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if utils.IsMediaFile(match) {
+			files = append(files, match)
+		}
+	}
+	return a.enqueuePaths(files, request)
+}
+
+// enqueuePaths 验证文件并逐个加入队列，不支持的文件在rejected中返回
+func (a *App) enqueuePaths(filePaths []string, request RecognitionRequest) map[string]interface{} {
+	if _, err := recognition.NormalizeTask(request.Task); err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+	request.FilePath = ""
+	request.FileData = ""
+
+	jobs := make([]models.QueueJob, 0, len(filePaths))
+	rejected := make([]map[string]interface{}, 0)
+	for _, filePath := range filePaths {
+		validation := utils.ValidateAudioFile(filePath)
+		if !validation.IsValid {
+			rejected = append(rejected, map[string]interface{}{
+				"file":  filePath,
+				"error": validation.ErrorMsg,
+			})
+			continue
+		}
+		jobs = append(jobs, a.queueService.Enqueue(filePath, validation.FileInfo.Size(), request))
+	}
+
+	if len(jobs) == 0 {
+		return map[string]interface{}{
+			"success":  false,
+			"error":    "没有可识别的音频或视频文件",
+			"rejected": rejected,
+		}
+	}
+	utils.LogInfo("批量识别队列新增 %d 个文件（%d 个不支持）", len(jobs), len(rejected))
+	return map[string]interface{}{
+		"success":  true,
+		"jobs":     jobs,
+		"rejected": rejected,
+	}
+}
+
+// runQueueJob 识别一个队列任务，与StartRecognition使用相同的参数校验，结果保存后可重新识别段落
+// 队列任务之间可以并行，交互式识别（StartRecognition、RerecognizeSegments）进行时等待其结束
+func (a *App) runQueueJob(ctx context.Context, job models.QueueJob, payload interface{}, progress func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	request, _ := payload.(RecognitionRequest)
+	request.FilePath = job.FilePath
+
+	unlock, err := a.lockRecognizer(ctx, false, progress)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	a.mu.Lock()
+	prepared, recognitionErr := a.prepareRecognitionLocked(request)
+	a.mu.Unlock()
+	if recognitionErr != nil {
+		return nil, recognitionErr
+	}
+
+	result, err := prepared.service.RecognizeFileWithOptions(ctx, job.FilePath, prepared.language, prepared.options, progress)
+	if err != nil {
+		return nil, err
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Metadata["engine"] = prepared.engineName
+	result.Metadata["queue_job_id"] = job.ID
+
+	a.resultStore.Put(result, services.ResultSource{
+		Path:     job.FilePath,
+		Language: prepared.language,
+		Engine:   prepared.engineName,
+		Options:  prepared.options,
+	})
+	return result, nil
+}
+
+// GetQueue 获取批量识别队列的全部任务
+func (a *App) GetQueue() map[string]interface{} {
+	return map[string]interface{}{
+		"success":     true,
+		"jobs":        a.queueService.Jobs(),
+		"concurrency": a.queueService.Concurrency(),
+	}
+}
+
+// GetQueueJobResult 获取已完成的队列任务的识别结果
+func (a *App) GetQueueJobResult(jobID string) map[string]interface{} {
+	job, err := a.queueService.Job(jobID)
+	if err == nil && job.ResultID == "" {
+		err = fmt.Errorf("任务尚未完成: %s", job.Status)
+	}
+	var result *models.RecognitionResult
+	if err == nil {
+		result, _, err = a.resultStore.Get(job.ResultID)
+	}
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+	return map[string]interface{}{
+		"success": true,
+		"job":     job,
+		"result":  result,
+	}
+}
+
+// PauseQueueJob 暂停队列任务，识别中的任务立即停止，恢复后从头识别
+func (a *App) PauseQueueJob(jobID string) map[string]interface{} {
+	return queueActionResponse(a.queueService.Pause(jobID))
+}
+
+// ResumeQueueJob 恢复暂停的队列任务，失败或已取消的任务重新识别
+func (a *App) ResumeQueueJob(jobID string) map[string]interface{} {
+	return queueActionResponse(a.queueService.Resume(jobID))
+}
+
+// CancelQueueJob 取消队列任务
+func (a *App) CancelQueueJob(jobID string) map[string]interface{} {
+	return queueActionResponse(a.queueService.Cancel(jobID))
+}
+
+// MoveQueueJob 调整队列任务的位置（从0开始），靠前的任务先识别
+func (a *App) MoveQueueJob(jobID string, position int) map[string]interface{} {
+	return queueActionResponse(a.queueService.Move(jobID, position))
+}
+
+// RemoveQueueJob 从队列中移除任务（识别中的任务需要先取消）
+func (a *App) RemoveQueueJob(jobID string) map[string]interface{} {
+	return queueActionResponse(a.queueService.Remove(jobID))
+}
+
+// ClearFinishedQueueJobs 移除队列中全部已结束的任务
+func (a *App) ClearFinishedQueueJobs() map[string]interface{} {
+	return map[string]interface{}{
+		"success": true,
+		"removed": a.queueService.ClearFinished(),
+	}
+}
+
+// queueActionResponse 生成队列操作的响应
+func queueActionResponse(err error) map[string]interface{} {
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+	return map[string]interface{}{
+		"success": true,
+	}
+}
+
+// GetVocabularies 获取所有项目词汇表的概要
+func (a *App) GetVocabularies() map[string]interface{} {
+	vocabularies, err := a.vocabularyService.List()
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success":      true,
+		"vocabularies": vocabularies,
+	}
+}
 
+// GetVocabulary 获取项目词汇表
+func (a *App) GetVocabulary(name string) map[string]interface{} {
+	vocabulary, err := a.vocabularyService.Get(name)
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
 
+	return map[string]interface{}{
+		"success":    true,
+		"vocabulary": vocabulary,
+	}
+}
+
+// SaveVocabulary 保存项目词汇表（同名词汇表会被覆盖）
+func (a *App) SaveVocabulary(vocabularyJSON string) RecognitionResponse {
+	var vocabulary models.Vocabulary
+	if err := json.Unmarshal([]byte(vocabularyJSON), &vocabulary); err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"词汇表格式错误",
+				err.Error(),
+			),
+		}
+	}
+
+	if err := a.vocabularyService.Save(&vocabulary); err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"保存词汇表失败",
+				err.Error(),
+			),
+		}
+	}
+
+	utils.LogInfo("词汇表已保存: %s (%d 个术语)", vocabulary.Name, len(vocabulary.Terms))
+	return RecognitionResponse{
+		Success: true,
+	}
+}
+
+// DeleteVocabulary 删除项目词汇表
+func (a *App) DeleteVocabulary(name string) RecognitionResponse {
+	if err := a.vocabularyService.Delete(name); err != nil {
+		return RecognitionResponse{
+			Success: false,
+			Error: models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"删除词汇表失败",
+				err.Error(),
+			),
+		}
+	}
+
+	utils.LogInfo("词汇表已删除: %s", name)
+	return RecognitionResponse{
+		Success: true,
+	}
+}
 
 // GetAITemplates 获取所有可用的AI提示词模板
 func (a *App) GetAITemplates() map[string]interface{} {
@@ -685,6 +1647,10 @@ func (a *App) GetTemplateManagerInfo() map[string]interface{} {
 
 // sendProgressEvent 发送进度事件
 func (a *App) sendProgressEvent(eventType string, data interface{}) {
+	if a.eventSink != nil {
+		a.eventSink(eventType, data)
+		return
+	}
 	if a.ctx != nil {
 		runtime.EventsEmit(a.ctx, eventType, data)
 	}
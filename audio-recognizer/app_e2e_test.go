@@ -0,0 +1,196 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/recognition"
+	"tingshengbianzi/tests/fakeengine"
+)
+
+// testEvent App发送的一个事件
+type testEvent struct {
+	eventType string
+	data      interface{}
+}
+
+// testEventRecorder 收集App发送的事件，识别完成时通知等待方
+type testEventRecorder struct {
+	mu       sync.Mutex
+	events   []testEvent
+	complete chan RecognitionResponse
+}
+
+// record 记录事件，作为App.eventSink使用
+func (r *testEventRecorder) record(eventType string, data interface{}) {
+	r.mu.Lock()
+	r.events = append(r.events, testEvent{eventType, data})
+	r.mu.Unlock()
+	if response, ok := data.(RecognitionResponse); ok && eventType == "recognition_complete" {
+		r.complete <- response
+	}
+}
+
+// byType 获取指定类型的事件数据
+func (r *testEventRecorder) byType(eventType string) []interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var data []interface{}
+	for _, event := range r.events {
+		if event.eventType == eventType {
+			data = append(data, event.data)
+		}
+	}
+	return data
+}
+
+// newTestApp 创建不依赖Wails运行时的App，配置文件写在临时目录中
+func newTestApp(t *testing.T) (*App, *testEventRecorder) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	recorder := &testEventRecorder{complete: make(chan RecognitionResponse, 8)}
+	app := NewApp(embed.FS{})
+	app.eventSink = recorder.record
+	return app, recorder
+}
+
+// writeTestAudio 写入一个假的音频文件（脚本化引擎不读取内容）
+func writeTestAudio(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("fake audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// waitRecognitionComplete 等待recognition_complete事件
+func waitRecognitionComplete(t *testing.T, recorder *testEventRecorder) RecognitionResponse {
+	t.Helper()
+	select {
+	case response := <-recorder.complete:
+		return response
+	case <-time.After(10 * time.Second):
+		t.Fatal("等待识别完成超时")
+		return RecognitionResponse{}
+	}
+}
+
+// TestStartRecognitionToExport 脚本化引擎识别 → 进度、实时段落和结果事件 → 导出SRT和TXT
+func TestStartRecognitionToExport(t *testing.T) {
+	script := fakeengine.Script{
+		Segments: []models.RecognitionResultSegment{
+			{Start: 0, End: 1.5, Text: "大家好，欢迎收听。", Confidence: 0.9},
+			{Start: 1.5, End: 3.2, Text: "今天聊一聊语音识别。", Confidence: 0.8},
+		},
+		ProgressSteps: []int{30, 60, 90},
+		StepDelayMs:   10,
+		EmitPartials:  true,
+	}
+	recognition.RegisterEngine(fakeengine.Descriptor("fake-e2e", script))
+	app, recorder := newTestApp(t)
+
+	audioPath := writeTestAudio(t, "podcast.mp3")
+	if response := app.StartRecognition(RecognitionRequest{FilePath: audioPath, Language: "zh-CN", Engine: "fake-e2e"}); !response.Success {
+		t.Fatalf("StartRecognition失败: %+v", response.Error)
+	}
+	response := waitRecognitionComplete(t, recorder)
+	if !response.Success || response.Result == nil {
+		t.Fatalf("识别失败: %+v", response.Error)
+	}
+
+	lastPercentage := -1
+	for _, data := range recorder.byType("recognition_progress") {
+		progress := data.(*models.RecognitionProgress)
+		if progress.Percentage < lastPercentage {
+			t.Errorf("进度从 %d%% 回退到 %d%%", lastPercentage, progress.Percentage)
+		}
+		lastPercentage = progress.Percentage
+	}
+	if lastPercentage != 100 {
+		t.Errorf("最后的进度为 %d%%，期望100%%", lastPercentage)
+	}
+	if partials := recorder.byType("recognition_partial"); len(partials) != len(script.Segments) {
+		t.Errorf("收到 %d 个实时段落，期望 %d 个", len(partials), len(script.Segments))
+	}
+	if results := recorder.byType("recognition_result"); len(results) != 1 {
+		t.Errorf("收到 %d 个识别结果事件，期望1个", len(results))
+	}
+
+	result := response.Result
+	if len(result.Segments) != len(script.Segments) {
+		t.Fatalf("识别结果有 %d 个段落，期望 %d 个", len(result.Segments), len(script.Segments))
+	}
+	for i, segment := range script.Segments {
+		if result.Segments[i].Text != segment.Text {
+			t.Errorf("第 %d 段为 %q，期望 %q", i+1, result.Segments[i].Text, segment.Text)
+		}
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputDir := t.TempDir()
+	exports := map[string][]string{
+		"srt": {"1\n00:00:00,000 --> ", "大家好，欢迎收听。", "2\n00:00:01,500 --> 00:00:03,200", "今天聊一聊语音识别。"},
+		"txt": {"大家好，欢迎收听。", "今天聊一聊语音识别。"},
+	}
+	for format, want := range exports {
+		outputPath := filepath.Join(outputDir, "podcast."+format)
+		if response := app.ExportResult(string(resultJSON), format, outputPath); !response.Success {
+			t.Fatalf("导出%s失败: %+v", format, response.Error)
+		}
+		content, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, text := range want {
+			if !strings.Contains(string(content), text) {
+				t.Errorf("导出的%s不包含 %q:\n%s", format, text, content)
+			}
+		}
+	}
+}
+
+// TestStartRecognitionErrorEvent 脚本化引擎识别失败时发送recognition_error和失败的recognition_complete事件，识别状态复位
+func TestStartRecognitionErrorEvent(t *testing.T) {
+	recognition.RegisterEngine(fakeengine.Descriptor("fake-e2e-error", fakeengine.Script{
+		ProgressSteps: []int{50},
+		ErrorCode:     models.ErrorCodeModelLoadFailed,
+		ErrorMessage:  "模型加载失败",
+	}))
+	app, recorder := newTestApp(t)
+
+	audioPath := writeTestAudio(t, "broken.mp3")
+	if response := app.StartRecognition(RecognitionRequest{FilePath: audioPath, Language: "zh-CN", Engine: "fake-e2e-error"}); !response.Success {
+		t.Fatalf("StartRecognition失败: %+v", response.Error)
+	}
+	response := waitRecognitionComplete(t, recorder)
+	// 引擎的错误代码保留在错误详情中
+	if response.Success || response.Error == nil || response.Error.Code != models.ErrorCodeRecognitionFailed ||
+		!strings.Contains(response.Error.Details, models.ErrorCodeModelLoadFailed) {
+		t.Fatalf("期望识别失败并在详情中包含 %s，实际为 %+v", models.ErrorCodeModelLoadFailed, response)
+	}
+	if errors := recorder.byType("recognition_error"); len(errors) != 1 {
+		t.Errorf("收到 %d 个识别错误事件，期望1个", len(errors))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for app.GetRecognitionStatus()["isRecognizing"].(bool) {
+		if time.Now().After(deadline) {
+			t.Fatal("识别失败后识别状态没有复位")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/recognition"
+	"tingshengbianzi/tests/fakeengine"
+)
+
+// TestQueueJobsWithInteractiveRecognition 两个队列任务并行识别，同时发起交互式识别和配置更新
+// 需要用 go test -race 运行才能发现共享状态上的数据竞争
+func TestQueueJobsWithInteractiveRecognition(t *testing.T) {
+	recognition.RegisterEngine(fakeengine.Descriptor("fake-queue", fakeengine.Script{
+		Segments: []models.RecognitionResultSegment{
+			{Start: 0, End: 1.5, Text: "你好"},
+			{Start: 1.5, End: 3, Text: "世界"},
+		},
+		ProgressSteps: []int{25, 50, 75},
+		StepDelayMs:   20,
+		EmitPartials:  true,
+	}))
+	app, recorder := newTestApp(t)
+	app.queueService.SetConcurrency(2)
+
+	files := []string{writeTestAudio(t, "a.mp3"), writeTestAudio(t, "b.mp3"), writeTestAudio(t, "c.mp3")}
+	request := RecognitionRequest{Language: "zh-CN", Engine: "fake-queue"}
+	if response := app.EnqueueFiles(files[:2], request); response["success"] != true {
+		t.Fatalf("EnqueueFiles失败: %v", response)
+	}
+
+	interactive := request
+	interactive.FilePath = files[2]
+	if response := app.StartRecognition(interactive); !response.Success {
+		t.Fatalf("StartRecognition失败: %+v", response.Error)
+	}
+	if response := app.UpdateConfig(app.GetConfig()); !response.Success {
+		t.Fatalf("UpdateConfig失败: %+v", response.Error)
+	}
+	if response := waitRecognitionComplete(t, recorder); !response.Success {
+		t.Fatalf("交互式识别失败: %+v", response.Error)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		finished := true
+		for _, job := range app.queueService.Jobs() {
+			finished = finished && job.Finished()
+		}
+		if finished && !app.GetRecognitionStatus()["isRecognizing"].(bool) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("等待识别完成超时: jobs=%+v", app.queueService.Jobs())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, job := range app.queueService.Jobs() {
+		if job.Status != models.QueueJobCompleted {
+			t.Errorf("任务 %s 状态为 %s: %+v", job.Name, job.Status, job.Error)
+		}
+		if result := app.GetQueueJobResult(job.ID); result["success"] != true {
+			t.Errorf("获取任务 %s 的结果失败: %v", job.Name, result)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"tingshengbianzi/backend/models"
+)
+
+// 分块默认参数
+const (
+	DefaultChunkDuration = 600.0 // 目标分块时长(秒)
+	defaultSearchWindow  = 60.0  // 在目标切分点前后寻找静音的范围(秒)
+	defaultMinSilence    = 0.3   // 最短静音时长(秒)
+	defaultChunkOverlap  = 1.0   // 分块之间的重叠(秒)，避免切分点附近的词被截断
+	vadFrameDuration     = 0.03  // VAD帧长(秒)
+	silenceAboveFloorDB  = 10.0  // 自适应阈值：噪声基底以上多少dB视为语音
+)
+
+// ChunkOptions 静音切分参数，数值为0时使用默认值
+type ChunkOptions struct {
+	TargetDuration     float64 // 目标分块时长(秒)
+	SearchWindow       float64 // 在目标切分点前后寻找静音的范围(秒)
+	MinSilence         float64 // 最短静音时长(秒)
+	SilenceThresholdDB float64 // 静音能量阈值(dBFS)，0为根据噪声基底自适应
+	Overlap            float64 // 分块之间的重叠(秒)
+}
+
+// AudioChunk 切分出的音频分块
+type AudioChunk struct {
+	Index     int     // 分块序号（从0开始）
+	Path      string  // 分块WAV文件路径
+	Start     float64 // 分块音频在原音频中的起始时间(秒，含重叠)
+	End       float64 // 分块音频在原音频中的结束时间(秒，含重叠)
+	KeepStart float64 // 该分块负责的时间范围起点（拼接时保留落在该范围内的段落）
+	KeepEnd   float64 // 该分块负责的时间范围终点
+}
+
+// Duration 分块时长(秒)
+func (c AudioChunk) Duration() float64 {
+	return c.End - c.Start
+}
+
+// wavLayout WAV文件的格式和数据区位置
+type wavLayout struct {
+	channels      int
+	sampleRate    int
+	bitsPerSample int
+	blockAlign    int
+	dataOffset    int64
+	dataSize      int64
+}
+
+// duration 音频时长(秒)
+func (l wavLayout) duration() float64 {
+	return float64(l.dataSize/int64(l.blockAlign)) / float64(l.sampleRate)
+}
+
+// SplitWAVAtSilence 在静音处将WAV文件切分为多个分块
+// 音频不超过目标时长的1.25倍时不切分，返回nil；分块文件由调用方负责删除
+func (p *Processor) SplitWAVAtSilence(ctx context.Context, wavPath string, options ChunkOptions) ([]AudioChunk, error) {
+	options = options.withDefaults()
+
+	file, err := os.Open(wavPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开WAV文件失败: %w", err)
+	}
+	defer file.Close()
+
+	layout, err := readWAVLayout(file)
+	if err != nil {
+		return nil, err
+	}
+
+	totalDuration := layout.duration()
+	if totalDuration <= options.TargetDuration*1.25 {
+		return nil, nil
+	}
+
+	energies, err := readFrameEnergies(ctx, file, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	cuts := findSilenceCuts(energies, totalDuration, options)
+	if len(cuts) == 0 {
+		return nil, nil
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(wavPath), filepath.Ext(wavPath))
+	boundaries := append(append([]float64{0}, cuts...), totalDuration)
+	chunks := make([]AudioChunk, 0, len(boundaries)-1)
+	for i := 0; i+1 < len(boundaries); i++ {
+		chunk := AudioChunk{
+			Index:     i,
+			KeepStart: boundaries[i],
+			KeepEnd:   boundaries[i+1],
+			Start:     math.Max(0, boundaries[i]-options.Overlap),
+			End:       math.Min(totalDuration, boundaries[i+1]+options.Overlap),
+		}
+		chunk.Path = filepath.Join(p.tempDir, fmt.Sprintf("%s_chunk%03d.wav", baseName, i))
+
+		if err := writeWAVSection(file, layout, chunk.Path, chunk.Start, chunk.End); err != nil {
+			RemoveChunks(chunks)
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	fmt.Printf("✂️ 音频在静音处切分为 %d 块 (总时长 %.1f 秒)\n", len(chunks), totalDuration)
+	return chunks, nil
+}
+
+// RemoveChunks 删除分块文件
+func RemoveChunks(chunks []AudioChunk) {
+	for _, chunk := range chunks {
+		if chunk.Path != "" {
+			os.Remove(chunk.Path)
+		}
+	}
+}
+
+// withDefaults 补全默认参数
+func (o ChunkOptions) withDefaults() ChunkOptions {
+	if o.TargetDuration <= 0 {
+		o.TargetDuration = DefaultChunkDuration
+	}
+	if o.SearchWindow <= 0 {
+		o.SearchWindow = math.Min(defaultSearchWindow, o.TargetDuration/4)
+	}
+	if o.MinSilence <= 0 {
+		o.MinSilence = defaultMinSilence
+	}
+	if o.Overlap <= 0 {
+		o.Overlap = defaultChunkOverlap
+	}
+	return o
+}
+
+// readWAVLayout 解析RIFF头，定位fmt和data块（FFmpeg输出的WAV可能带LIST块，数据不一定从44字节开始）
+func readWAVLayout(file *os.File) (wavLayout, error) {
+	var layout wavLayout
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return layout, fmt.Errorf("读取WAV文件头失败: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return layout, models.NewRecognitionError(
+			models.ErrorCodeInvalidAudioFormat,
+			"无效的WAV文件格式",
+			"",
+		)
+	}
+
+	offset := int64(12)
+	chunkHeader := make([]byte, 8)
+	for {
+		if _, err := file.ReadAt(chunkHeader, offset); err != nil {
+			return layout, fmt.Errorf("WAV文件缺少data块: %w", err)
+		}
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunkHeader[4:8]))
+		offset += 8
+
+		switch chunkID {
+		case "fmt ":
+			format := make([]byte, 16)
+			if _, err := file.ReadAt(format, offset); err != nil {
+				return layout, fmt.Errorf("读取WAV格式失败: %w", err)
+			}
+			layout.channels = int(binary.LittleEndian.Uint16(format[2:4]))
+			layout.sampleRate = int(binary.LittleEndian.Uint32(format[4:8]))
+			layout.blockAlign = int(binary.LittleEndian.Uint16(format[12:14]))
+			layout.bitsPerSample = int(binary.LittleEndian.Uint16(format[14:16]))
+		case "data":
+			layout.dataOffset = offset
+			layout.dataSize = chunkSize
+			if info, err := file.Stat(); err == nil && offset+chunkSize > info.Size() {
+				layout.dataSize = info.Size() - offset // FFmpeg流式输出时data大小可能未回填
+			}
+			if layout.bitsPerSample != 16 || layout.sampleRate <= 0 || layout.blockAlign <= 0 {
+				return layout, models.NewRecognitionError(
+					models.ErrorCodeInvalidAudioFormat,
+					"仅支持16位PCM格式的WAV文件",
+					fmt.Sprintf("位深=%d, 采样率=%d", layout.bitsPerSample, layout.sampleRate),
+				)
+			}
+			return layout, nil
+		}
+		offset += chunkSize + chunkSize%2 // RIFF块按2字节对齐
+	}
+}
+
+// readFrameEnergies 逐帧计算音频能量(dBFS)，流式读取避免将长音频全部载入内存
+// 多声道时取所有声道的平均能量（立体声通话录音中只在一侧声道说话的部分不会被当作静音）
+func readFrameEnergies(ctx context.Context, file *os.File, layout wavLayout) ([]float64, error) {
+	frameSamples := int(float64(layout.sampleRate) * vadFrameDuration)
+	frameBytes := frameSamples * layout.blockAlign
+	reader := bufio.NewReaderSize(io.NewSectionReader(file, layout.dataOffset, layout.dataSize), 1<<20)
+
+	energies := make([]float64, 0, layout.dataSize/int64(frameBytes)+1)
+	buffer := make([]byte, frameBytes)
+	for {
+		if len(energies)%10000 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		n, err := io.ReadFull(reader, buffer)
+		if n >= layout.blockAlign {
+			var sum float64
+			count := 0
+			for i := 0; i+1 < n; i += 2 {
+				sample := float64(int16(binary.LittleEndian.Uint16(buffer[i:i+2]))) / 32768.0
+				sum += sample * sample
+				count++
+			}
+			energies = append(energies, 10*math.Log10(sum/float64(count)+1e-10))
+		}
+		if err != nil {
+			break
+		}
+	}
+	return energies, nil
+}
+
+// findSilenceCuts 在每个目标切分点附近寻找最长的静音段，以静音中点作为切分点
+// 附近没有足够长的静音时，退而选择能量最低的帧
+func findSilenceCuts(energies []float64, totalDuration float64, options ChunkOptions) []float64 {
+	if len(energies) == 0 {
+		return nil
+	}
+
+	threshold := options.SilenceThresholdDB
+	if threshold == 0 {
+		threshold = noiseFloor(energies) + silenceAboveFloorDB
+	}
+	minSilenceFrames := int(math.Ceil(options.MinSilence / vadFrameDuration))
+
+	var cuts []float64
+	position := 0.0
+	for totalDuration-position > options.TargetDuration*1.25 {
+		target := position + options.TargetDuration
+		from := frameIndex(math.Max(position+options.TargetDuration/2, target-options.SearchWindow), len(energies))
+		to := frameIndex(math.Min(totalDuration, target+options.SearchWindow), len(energies))
+
+		cut := -1
+		bestLength, bestDistance := 0, math.MaxFloat64
+		for i := from; i < to; {
+			if energies[i] >= threshold {
+				i++
+				continue
+			}
+			runStart := i
+			for i < to && energies[i] < threshold {
+				i++
+			}
+			length := i - runStart
+			middle := runStart + length/2
+			distance := math.Abs(float64(middle)*vadFrameDuration - target)
+			// 优先选择更长的静音，长度相同时选择离目标更近的
+			if length >= minSilenceFrames && (length > bestLength || (length == bestLength && distance < bestDistance)) {
+				cut, bestLength, bestDistance = middle, length, distance
+			}
+		}
+
+		if cut < 0 && from < to {
+			cut = from
+			for i := from; i < to; i++ {
+				if energies[i] < energies[cut] {
+					cut = i
+				}
+			}
+			fmt.Printf("⚠️ %.1f秒附近没有足够长的静音，在能量最低处切分\n", target)
+		}
+
+		cutTime := float64(cut) * vadFrameDuration
+		if cut < 0 || cutTime <= position {
+			cutTime = target
+		}
+		cuts = append(cuts, cutTime)
+		position = cutTime
+	}
+	return cuts
+}
+
+// noiseFloor 估算噪声基底（能量的10%分位数）
+func noiseFloor(energies []float64) float64 {
+	sorted := make([]float64, len(energies))
+	copy(sorted, energies)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/10]
+}
+
+// frameIndex 将时间换算为帧序号
+func frameIndex(seconds float64, frameCount int) int {
+	index := int(seconds / vadFrameDuration)
+	if index < 0 {
+		return 0
+	}
+	if index > frameCount {
+		return frameCount
+	}
+	return index
+}
+
+// writeWAVSection 将原WAV中[start, end)的数据写入新的WAV文件
+func writeWAVSection(file *os.File, layout wavLayout, outputPath string, start, end float64) error {
+	startByte := int64(start*float64(layout.sampleRate)) * int64(layout.blockAlign)
+	endByte := int64(end*float64(layout.sampleRate)) * int64(layout.blockAlign)
+	if endByte > layout.dataSize {
+		endByte = layout.dataSize
+	}
+	size := endByte - startByte
+
+	output, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("创建分块文件失败: %w", err)
+	}
+	defer output.Close()
+
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(36+size))
+	copy(header[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:24], uint16(layout.channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(layout.sampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(layout.sampleRate*layout.blockAlign))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(layout.blockAlign))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(layout.bitsPerSample))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(size))
+
+	if _, err := output.Write(header); err != nil {
+		return fmt.Errorf("写入分块文件失败: %w", err)
+	}
+	if _, err := io.Copy(output, io.NewSectionReader(file, layout.dataOffset+startByte, size)); err != nil {
+		return fmt.Errorf("写入分块文件失败: %w", err)
+	}
+	return nil
+}
+
+// EnergyProfile 音频的逐帧能量，用于判断某段时间是否接近静音
+type EnergyProfile struct {
+	energies []float64
+	floor    float64
+}
+
+// AnalyzeEnergy 计算WAV文件的逐帧能量
+func AnalyzeEnergy(ctx context.Context, wavPath string) (*EnergyProfile, error) {
+	file, err := os.Open(wavPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开WAV文件失败: %w", err)
+	}
+	defer file.Close()
+
+	layout, err := readWAVLayout(file)
+	if err != nil {
+		return nil, err
+	}
+	energies, err := readFrameEnergies(ctx, file, layout)
+	if err != nil {
+		return nil, err
+	}
+	if len(energies) == 0 {
+		return nil, fmt.Errorf("WAV文件没有音频数据")
+	}
+	return &EnergyProfile{energies: energies, floor: noiseFloor(energies)}, nil
+}
+
+// NoiseFloor 噪声基底(dBFS)
+func (e *EnergyProfile) NoiseFloor() float64 {
+	return e.floor
+}
+
+// PeakLevel 时间范围内的最大帧能量(dBFS)，范围超出音频时返回噪声基底
+func (e *EnergyProfile) PeakLevel(start, end float64) float64 {
+	from := frameIndex(start, len(e.energies))
+	to := frameIndex(end, len(e.energies))
+	if to <= from {
+		to = min(from+1, len(e.energies))
+	}
+	if from >= to {
+		return e.floor
+	}
+
+	peak := e.energies[from]
+	for _, energy := range e.energies[from+1 : to] {
+		peak = math.Max(peak, energy)
+	}
+	return peak
+}
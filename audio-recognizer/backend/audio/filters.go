@@ -0,0 +1,151 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"tingshengbianzi/backend/models"
+)
+
+// 预处理滤镜默认参数
+const (
+	DefaultLoudnessTarget       = -16.0 // EBU R128 语音常用目标响度(LUFS)
+	DefaultLoudnessRange        = 11.0  // 响度范围(LU)
+	DefaultTruePeak             = -1.5  // 真峰值上限(dBTP)
+	DefaultNoiseFloor           = -25.0 // afftdn噪声基底(dB)
+	DefaultNoiseReduction       = 12.0  // afftdn降噪量(dB)
+	DefaultCompressionThreshold = -20.0 // 压缩阈值(dB)
+	DefaultCompressionRatio     = 3.0   // 压缩比
+)
+
+// 降噪方式
+const (
+	NoiseReductionFFT     = "afftdn" // FFT降噪，无需模型
+	NoiseReductionRNNoise = "arnndn" // RNNoise神经网络降噪，需要.rnnn模型文件
+)
+
+// AudioFilter 单个FFmpeg音频滤镜
+type AudioFilter struct {
+	Name    string   // 滤镜名称
+	Options []string // key=value形式的参数
+}
+
+// String 生成FFmpeg滤镜描述
+func (f AudioFilter) String() string {
+	if len(f.Options) == 0 {
+		return f.Name
+	}
+	return f.Name + "=" + strings.Join(f.Options, ":")
+}
+
+// FilterChain 按顺序执行的滤镜链
+type FilterChain []AudioFilter
+
+// String 生成 -af 参数值，空链返回空字符串
+func (c FilterChain) String() string {
+	parts := make([]string, len(c))
+	for i, filter := range c {
+		parts[i] = filter.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Names 获取滤镜名称列表
+func (c FilterChain) Names() []string {
+	names := make([]string, len(c))
+	for i, filter := range c {
+		names[i] = filter.Name
+	}
+	return names
+}
+
+// BuildFilterChain 根据识别配置构建预处理滤镜链
+// 顺序: 高通滤波 → 降噪 → 动态范围压缩 → 响度归一化（归一化放在最后，保证输出响度稳定）
+func BuildFilterChain(config *models.RecognitionConfig) FilterChain {
+	if config == nil {
+		return nil
+	}
+	params := config.AudioFilters
+	var chain FilterChain
+
+	if params.HighPassFrequency > 0 {
+		chain = append(chain, AudioFilter{
+			Name:    "highpass",
+			Options: []string{"f=" + strconv.Itoa(params.HighPassFrequency)},
+		})
+	}
+
+	if config.EnableNoiseReduction {
+		chain = append(chain, buildNoiseReductionFilter(params))
+	}
+
+	if params.EnableCompression {
+		threshold := orDefault(params.CompressionThreshold, DefaultCompressionThreshold)
+		ratio := orDefault(params.CompressionRatio, DefaultCompressionRatio)
+		chain = append(chain, AudioFilter{
+			Name: "acompressor",
+			Options: []string{
+				"threshold=" + formatFilterNumber(threshold) + "dB",
+				"ratio=" + formatFilterNumber(ratio),
+				"attack=5",
+				"release=100",
+			},
+		})
+	}
+
+	if config.EnableNormalization {
+		chain = append(chain, AudioFilter{
+			Name: "loudnorm",
+			Options: []string{
+				"I=" + formatFilterNumber(orDefault(params.LoudnessTarget, DefaultLoudnessTarget)),
+				"LRA=" + formatFilterNumber(orDefault(params.LoudnessRange, DefaultLoudnessRange)),
+				"TP=" + formatFilterNumber(orDefault(params.TruePeak, DefaultTruePeak)),
+			},
+		})
+	}
+
+	return chain
+}
+
+// buildNoiseReductionFilter 构建降噪滤镜，arnndn缺少模型文件时回退到afftdn
+func buildNoiseReductionFilter(params models.AudioFilterConfig) AudioFilter {
+	if params.NoiseReductionMethod == NoiseReductionRNNoise {
+		if params.RNNoiseModel != "" {
+			if _, err := os.Stat(params.RNNoiseModel); err == nil {
+				return AudioFilter{
+					Name:    NoiseReductionRNNoise,
+					Options: []string{"m=" + escapeFilterValue(params.RNNoiseModel)},
+				}
+			}
+		}
+		fmt.Printf("⚠️ RNNoise模型文件不可用(%s)，降噪回退到afftdn\n", params.RNNoiseModel)
+	}
+
+	return AudioFilter{
+		Name: NoiseReductionFFT,
+		Options: []string{
+			"nf=" + formatFilterNumber(orDefault(params.NoiseFloor, DefaultNoiseFloor)),
+			"nr=" + formatFilterNumber(orDefault(params.NoiseReduction, DefaultNoiseReduction)),
+		},
+	}
+}
+
+// orDefault 数值未设置时返回默认值
+func orDefault(value *float64, defaultValue float64) float64 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+// formatFilterNumber 格式化滤镜数值参数，去掉多余的小数位
+func formatFilterNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// escapeFilterValue 用单引号包裹滤镜参数，避免路径中的冒号、逗号被当作分隔符
+func escapeFilterValue(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
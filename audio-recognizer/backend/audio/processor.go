@@ -1,6 +1,7 @@
 package audio
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -9,7 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 
 	"tingshengbianzi/backend/models"
 	"tingshengbianzi/backend/utils"
@@ -21,8 +22,10 @@ type Processor struct {
 	ffmpegPath          string
 	ffprobePath         string
 	ffmpegManager       *utils.EmbeddedFFmpegManager
+	settingsMu          sync.RWMutex // 保护以下转换参数，识别任务可能并发转换
 	sampleRate          int
 	channels            int
+	filterChain         FilterChain // 转换时应用的预处理滤镜
 }
 
 // NewProcessor 创建新的音频处理器
@@ -58,8 +61,30 @@ func NewProcessor() (*Processor, error) {
 	}, nil
 }
 
-// ConvertToWAV 将音频文件转换为WAV格式
-func (p *Processor) ConvertToWAV(inputPath string) (string, *models.AudioFile, error) {
+// ConvertOptions 单次转换的选项
+type ConvertOptions struct {
+	Channels   int    // 输出声道数，0时使用处理器设置（立体声说话人分离需要保留两个声道）
+	AudioTrack *int   // 指定音轨序号，nil时自动选择
+	Language   string // 识别语言的Whisper代码，用于自动选择语言标签一致的音轨
+	TimeRanges []models.TimeRange // 只转换这些时间范围（FFmpeg输入端 -ss/-t 定位），多个范围按顺序拼接，中间插入静音
+}
+
+// ConvertToWAV 将音频文件转换为WAV格式，ctx取消时终止FFmpeg进程并清理输出文件
+func (p *Processor) ConvertToWAV(ctx context.Context, inputPath string) (string, *models.AudioFile, error) {
+	return p.ConvertToWAVWithOptions(ctx, inputPath, ConvertOptions{})
+}
+
+// ConvertToWAVWithOptions 按选项将音频或视频文件转换为WAV格式，视频文件只提取选中的音轨
+// 返回的AudioFile描述原始文件，转换后WAV的采样率和声道数在OutputSampleRate、OutputChannels中
+func (p *Processor) ConvertToWAVWithOptions(ctx context.Context, inputPath string, options ConvertOptions) (string, *models.AudioFile, error) {
+	// 转换参数在开始时取一次，转换期间修改设置不影响本次转换
+	p.settingsMu.RLock()
+	sampleRate, channels, filterChain := p.sampleRate, p.channels, p.filterChain
+	p.settingsMu.RUnlock()
+	if options.Channels > 0 {
+		channels = options.Channels
+	}
+
 	// 检查输入文件是否存在
 	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
 		return "", nil, models.NewRecognitionError(
@@ -75,10 +100,15 @@ func (p *Processor) ConvertToWAV(inputPath string) (string, *models.AudioFile, e
 		return "", nil, fmt.Errorf("获取文件信息失败: %w", err)
 	}
 
-	// 生成临时输出文件名
+	// 生成临时输出文件名（并发识别同一文件或同名文件时不能重名，否则一个任务清理时会删掉另一个任务的WAV）
 	ext := filepath.Ext(inputPath)
 	baseName := strings.TrimSuffix(filepath.Base(inputPath), ext)
-	outputPath := filepath.Join(p.tempDir, fmt.Sprintf("%s_converted_%d.wav", baseName, time.Now().Unix()))
+	outputFile, err := os.CreateTemp(p.tempDir, baseName+"_converted_*.wav")
+	if err != nil {
+		return "", nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	outputFile.Close()
+	outputPath := outputFile.Name()
 
 	// 使用FFmpeg转换音频格式
 	fmt.Printf("开始转换音频文件: %s\n", inputPath)
@@ -110,38 +140,95 @@ func (p *Processor) ConvertToWAV(inputPath string) (string, *models.AudioFile, e
 		)
 	}
 
-	fmt.Printf("即将执行FFmpeg命令...\n")
-
-	cmd := exec.Command(p.ffmpegPath,
-		"-i", inputPath,           // 输入文件
-		"-ar", fmt.Sprintf("%d", p.sampleRate), // 设置采样率
-		"-ac", fmt.Sprintf("%d", p.channels),   // 设置声道数
-		"-f", "wav",               // 输出格式
-		"-acodec", "pcm_s16le",    // 音频编码
-		"-y",                      // 覆盖输出文件
-		outputPath,
-	)
-
-	// 打印完整命令用于调试
-	fmt.Printf("FFmpeg命令: %s\n", cmd.String())
+	// 先探测原始文件（编码、容器、音轨、视频流等），用于选择音轨；探测失败时由FFmpeg自动选择
+	audioInfo, probeErr := p.getAudioInfo(ctx, inputPath)
+	stream := -1
+	var ranges []models.TimeRange
+	if probeErr != nil && len(options.TimeRanges) > 0 {
+		// 截取时间范围需要知道文件时长，才能补全结束时间并把识别结果换算回原始时间
+		return "", nil, models.NewRecognitionError(
+			models.ErrorCodeAudioProcessFailed,
+			"获取音频时长失败，无法截取时间范围",
+			probeErr.Error(),
+		)
+	}
+	if probeErr == nil {
+		if audioInfo.Video != nil && len(audioInfo.AudioStreams) == 0 {
+			return "", nil, models.NewRecognitionError(
+				models.ErrorCodeInvalidAudioFormat,
+				"视频文件没有音轨",
+				inputPath,
+			)
+		}
+		stream, err = SelectAudioStream(audioInfo, options.AudioTrack, options.Language)
+		if err != nil {
+			return "", nil, models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"音轨选择失败",
+				err.Error(),
+			)
+		}
+		if len(audioInfo.AudioStreams) > 0 {
+			audioInfo.AudioStream = stream
+			selected := audioInfo.AudioStreams[stream]
+			audioInfo.Codec = selected.Codec
+			audioInfo.SampleRate = selected.SampleRate
+			audioInfo.Channels = selected.Channels
+			if len(audioInfo.AudioStreams) > 1 {
+				fmt.Printf("🎵 使用音轨 %d/%d (语言=%s, 编码=%s)\n", stream, len(audioInfo.AudioStreams), selected.Language, selected.Codec)
+			}
+		} else {
+			stream = -1
+		}
 
-	// 设置环境变量和工作目录
-	cmd.Dir = os.TempDir()
+		ranges, err = ResolveTimeRanges(options.TimeRanges, audioInfo.Duration)
+		if err != nil {
+			return "", nil, models.NewRecognitionError(
+				models.ErrorCodeInvalidConfig,
+				"无效的时间范围",
+				err.Error(),
+			)
+		}
+		audioInfo.TimeRanges = ranges
+		audioInfo.ConvertedDuration = audioInfo.Duration
+		if len(ranges) > 0 {
+			audioInfo.ConvertedDuration = TimeRangesDuration(ranges)
+			fmt.Printf("⏱️ 只转换 %d 个时间范围，共 %.1f 秒\n", len(ranges), audioInfo.ConvertedDuration)
+		}
 
-	// 在沙盒环境中可能需要设置环境变量
-	cmd.Env = append(os.Environ(),
-		"TMPDIR="+os.TempDir(),
-		"HOME="+os.TempDir(),
-	)
+		// 检查临时目录剩余空间是否足够写入转换后的WAV（16位PCM）
+		wavSize := int64(audioInfo.ConvertedDuration*float64(sampleRate)) * int64(channels) * 2
+		if err := utils.EnsureDiskSpace(p.tempDir, wavSize); err != nil {
+			return "", nil, models.NewRecognitionError(
+				models.ErrorCodeDiskSpaceFull,
+				"磁盘空间不足，无法转换音频",
+				err.Error(),
+			)
+		}
+	}
 
-	fmt.Printf("FFmpeg工作目录: %s\n", cmd.Dir)
+	fmt.Printf("即将执行FFmpeg命令...\n")
 
-	// 执行转换命令
-	output, err := cmd.CombinedOutput()
+	// 执行转换命令，预处理滤镜失败时（如FFmpeg版本不支持某个滤镜）去掉滤镜重试一次
+	output, err := p.runConversion(ctx, inputPath, outputPath, filterChain, sampleRate, channels, stream, ranges)
+	if err != nil && len(filterChain) > 0 && ctx.Err() == nil {
+		fmt.Printf("⚠️ 带预处理滤镜的转换失败，去掉滤镜重试: %v\n命令输出: %s\n", err, string(output))
+		filterChain = nil
+		output, err = p.runConversion(ctx, inputPath, outputPath, nil, sampleRate, channels, stream, ranges)
+	}
 	if err != nil {
 		// 清理临时文件
 		os.Remove(outputPath)
 
+		// 被取消时返回取消错误，而不是转换失败
+		if ctx.Err() != nil {
+			return "", nil, models.NewRecognitionError(
+				models.ErrorCodeRecognitionCancelled,
+				"音频转换已取消",
+				ctx.Err().Error(),
+			)
+		}
+
 		// 输出详细的错误信息
 		errorMsg := fmt.Sprintf("FFmpeg转换失败: %v\n命令输出: %s", err, string(output))
 		fmt.Printf("音频转换错误详情:\n%s\n", errorMsg)
@@ -153,90 +240,144 @@ func (p *Processor) ConvertToWAV(inputPath string) (string, *models.AudioFile, e
 		)
 	}
 
-	// 获取转换后的音频信息
-	audioInfo, err := p.getAudioInfo(outputPath)
-	if err != nil {
-		os.Remove(outputPath) // 清理临时文件
-		return "", nil, fmt.Errorf("获取音频信息失败: %w", err)
+	// 原始文件探测失败时只能从转换后的WAV得到时长，原始文件的编码、采样率等保持未知
+	if probeErr != nil {
+		fmt.Printf("⚠️ 探测原始文件失败，使用转换后文件的时长: %v\n", probeErr)
+		converted, err := p.getAudioInfo(ctx, outputPath)
+		if err != nil {
+			os.Remove(outputPath) // 清理临时文件
+			return "", nil, fmt.Errorf("获取音频信息失败: %w", err)
+		}
+		audioInfo = &models.AudioFile{
+			Format:            strings.TrimPrefix(strings.ToLower(filepath.Ext(inputPath)), "."),
+			Duration:          converted.Duration,
+			ConvertedDuration: converted.Duration,
+		}
 	}
 
 	audioInfo.Name = filepath.Base(inputPath)
 	audioInfo.Path = inputPath
 	audioInfo.Size = fileInfo.Size()
+	audioInfo.FilterChain = filterChain.String()
+	audioInfo.OutputSampleRate = sampleRate
+	audioInfo.OutputChannels = channels
 
 	return outputPath, audioInfo, nil
 }
 
-// getAudioInfo 获取音频文件信息
-func (p *Processor) getAudioInfo(filePath string) (*models.AudioFile, error) {
-	// 使用FFprobe获取音频信息
-	cmd := exec.Command(p.ffprobePath,
-		"-i", filePath,
-		"-show_format",
-		"-show_streams",
-		"-v", "quiet",
-		"-print_format", "json",
-	)
-
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("获取音频信息失败: %w", err)
+// runConversion 执行FFmpeg转换，filterChain非空时通过 -af 应用预处理滤镜
+// stream不小于0时只提取该音轨（0:a:N），否则由FFmpeg自动选择
+// ranges非空时每个时间范围作为一个输入（输入端定位只解码需要的部分），多个范围通过concat滤镜拼接
+func (p *Processor) runConversion(ctx context.Context, inputPath, outputPath string, filterChain FilterChain, sampleRate, channels int, stream int, ranges []models.TimeRange) ([]byte, error) {
+	var args []string
+	if len(ranges) == 0 {
+		args = append(args, "-i", inputPath) // 输入文件
+	}
+	for _, r := range ranges {
+		args = append(args,
+			"-ss", formatSeconds(r.Start), // 输入端定位到范围起点
+			"-t", formatSeconds(r.Duration()), // 只读取范围时长
+			"-i", inputPath,
+		)
 	}
 
-	// 解析音频信息（这里简化处理，实际应该解析JSON）
-	audioInfo := &models.AudioFile{
-		Format:     "wav",
-		SampleRate: p.sampleRate,
-		Channels:   p.channels,
-		Duration:   0, // 需要从FFmpeg输出中解析
-		BitRate:    0, // 需要从FFmpeg输出中解析
+	streamSpec := "a"
+	if stream >= 0 {
+		streamSpec = fmt.Sprintf("a:%d", stream)
+	}
+	if len(ranges) > 1 {
+		// 除最后一个范围外，每个范围末尾补一段静音再拼接；预处理滤镜接在拼接之后
+		var graph strings.Builder
+		for i := range ranges {
+			if i < len(ranges)-1 {
+				fmt.Fprintf(&graph, "[%d:%s]apad=pad_dur=%s[r%d];", i, streamSpec, formatSeconds(TimeRangeGap), i)
+			} else {
+				fmt.Fprintf(&graph, "[%d:%s]anull[r%d];", i, streamSpec, i)
+			}
+		}
+		for i := range ranges {
+			fmt.Fprintf(&graph, "[r%d]", i)
+		}
+		fmt.Fprintf(&graph, "concat=n=%d:v=0:a=1", len(ranges))
+		if len(filterChain) > 0 {
+			graph.WriteString("," + filterChain.String())
+		}
+		graph.WriteString("[out]")
+		args = append(args, "-filter_complex", graph.String(), "-map", "[out]")
+	} else {
+		if stream >= 0 {
+			args = append(args, "-map", "0:"+streamSpec) // 选中的音轨
+		}
+		if len(filterChain) > 0 {
+			args = append(args, "-af", filterChain.String()) // 预处理滤镜
+		}
 	}
+	args = append(args, "-vn", "-sn", "-dn") // 忽略视频、字幕和数据流
+	args = append(args,
+		"-ar", fmt.Sprintf("%d", sampleRate), // 设置采样率
+		"-ac", fmt.Sprintf("%d", channels),     // 设置声道数
+		"-f", "wav",                            // 输出格式
+		"-acodec", "pcm_s16le",                 // 音频编码
+		"-y",                                   // 覆盖输出文件
+		outputPath,
+	)
+	cmd := exec.CommandContext(ctx, p.ffmpegPath, args...)
+
+	// 打印完整命令用于调试
+	fmt.Printf("FFmpeg命令: %s\n", cmd.String())
 
-	// 获取音频时长（简化版本）
-	if duration, err := p.getAudioDuration(filePath); err == nil {
-		audioInfo.Duration = duration
+	// 设置环境变量和工作目录
+	cmd.Dir = os.TempDir()
+
+	// 在沙盒环境中可能需要设置环境变量
+	cmd.Env = append(os.Environ(),
+		"TMPDIR="+os.TempDir(),
+		"HOME="+os.TempDir(),
+	)
+
+	fmt.Printf("FFmpeg工作目录: %s\n", cmd.Dir)
+
+	return cmd.CombinedOutput()
+}
+
+// formatSeconds 格式化FFmpeg的时间参数（秒，毫秒精度）
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
+
+// getAudioInfo 使用FFprobe获取音频文件信息
+func (p *Processor) getAudioInfo(ctx context.Context, filePath string) (*models.AudioFile, error) {
+	audioInfo, err := utils.ProbeAudioFile(ctx, p.ffprobePath, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("获取音频信息失败: %w", err)
 	}
 
-	// 记录FFprobe输出用于调试
-	fmt.Printf("FFprobe输出: %s\n", string(output))
+	fmt.Printf("FFprobe: 编码=%s, 容器=%s, 采样率=%d, 声道=%d, 时长=%.2f秒\n",
+		audioInfo.Codec, audioInfo.Container, audioInfo.SampleRate, audioInfo.Channels, audioInfo.Duration)
 
 	return audioInfo, nil
 }
 
-// getAudioDuration 获取音频时长
-func (p *Processor) getAudioDuration(filePath string) (float64, error) {
-	// 使用FFprobe获取音频时长
-	cmd := exec.Command(p.ffprobePath,
-		"-i", filePath,
-		"-show_format",
-		"-v", "quiet",
-		"-select_streams", "a:0",
-		"-show_entries", "format=duration",
-		"-of", "csv=p=0",
-	)
+// ProbeAudio 获取音频文件信息（不转换格式）
+func (p *Processor) ProbeAudio(ctx context.Context, filePath string) (*models.AudioFile, error) {
+	return p.getAudioInfo(ctx, filePath)
+}
 
-	output, err := cmd.Output()
+// getAudioDuration 获取音频时长
+func (p *Processor) getAudioDuration(ctx context.Context, filePath string) (float64, error) {
+	audioInfo, err := p.getAudioInfo(ctx, filePath)
 	if err != nil {
 		return 0, err
 	}
-
-	// 解析时长（秒）
-	durationStr := strings.TrimSpace(string(output))
-	if durationStr == "" {
+	if audioInfo.Duration <= 0 {
 		return 0, fmt.Errorf("无法获取音频时长")
 	}
-
-	duration, err := strconv.ParseFloat(durationStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("解析时长失败: %w", err)
-	}
-
-	return duration, nil
+	return audioInfo.Duration, nil
 }
 
 // GetAudioDuration 公开的音频时长获取方法
 func (p *Processor) GetAudioDuration(filePath string) (float64, error) {
-	return p.getAudioDuration(filePath)
+	return p.getAudioDuration(context.Background(), filePath)
 }
 
 // ReadWAVData 读取WAV文件音频数据
@@ -331,10 +472,21 @@ func (p *Processor) Cleanup() error {
 
 // SetSampleRate 设置采样率
 func (p *Processor) SetSampleRate(sampleRate int) {
+	p.settingsMu.Lock()
+	defer p.settingsMu.Unlock()
 	p.sampleRate = sampleRate
 }
 
+// SetFilterChain 设置转换时应用的预处理滤镜链，可在转换进行时调用，已开始的转换仍使用原来的滤镜链
+func (p *Processor) SetFilterChain(chain FilterChain) {
+	p.settingsMu.Lock()
+	defer p.settingsMu.Unlock()
+	p.filterChain = chain
+}
+
 // SetChannels 设置声道数
 func (p *Processor) SetChannels(channels int) {
+	p.settingsMu.Lock()
+	defer p.settingsMu.Unlock()
 	p.channels = channels
 }
\ No newline at end of file
@@ -0,0 +1,65 @@
+package audio
+
+import (
+	"fmt"
+	"strings"
+
+	"tingshengbianzi/backend/models"
+)
+
+// iso6392Languages 视频容器常用的ISO 639-2语言标签（含B/T两种写法）对应的Whisper语言代码
+var iso6392Languages = map[string]string{
+	"chi": "zh", "zho": "zh", "cmn": "zh", "yue": "yue",
+	"eng": "en", "jpn": "ja", "kor": "ko",
+	"fre": "fr", "fra": "fr", "ger": "de", "deu": "de",
+	"spa": "es", "ita": "it", "por": "pt", "rus": "ru",
+	"ara": "ar", "hin": "hi", "tha": "th", "vie": "vi",
+	"ind": "id", "may": "ms", "msa": "ms", "tur": "tr",
+	"dut": "nl", "nld": "nl", "pol": "pl", "ukr": "uk",
+	"swe": "sv", "nor": "no", "dan": "da", "fin": "fi",
+	"gre": "el", "ell": "el", "heb": "he", "per": "fa",
+	"fas": "fa", "cze": "cs", "ces": "cs", "hun": "hu",
+	"rum": "ro", "ron": "ro", "tgl": "tl", "fil": "tl",
+}
+
+// StreamLanguage 将音轨语言标签转换为Whisper语言代码，无法识别时返回空字符串
+func StreamLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if code, ok := iso6392Languages[tag]; ok {
+		return code
+	}
+	// 部分封装工具写入的是ISO 639-1代码或BCP-47代码（如zh-CN）
+	primary := strings.SplitN(strings.ReplaceAll(tag, "_", "-"), "-", 2)[0]
+	if len(primary) == 2 {
+		return primary
+	}
+	return ""
+}
+
+// SelectAudioStream 选择识别使用的音轨
+// track非nil时使用指定音轨；否则优先选择语言标签与识别语言一致的音轨，再选择默认音轨
+func SelectAudioStream(audioInfo *models.AudioFile, track *int, language string) (int, error) {
+	if audioInfo == nil || len(audioInfo.AudioStreams) == 0 {
+		if track != nil && *track != 0 {
+			return 0, fmt.Errorf("音轨 %d 不存在", *track)
+		}
+		return 0, nil
+	}
+
+	streams := audioInfo.AudioStreams
+	if track != nil {
+		if *track < 0 || *track >= len(streams) {
+			return 0, fmt.Errorf("音轨 %d 不存在，文件共有 %d 条音轨", *track, len(streams))
+		}
+		return *track, nil
+	}
+
+	if language != "" && language != "auto" && len(streams) > 1 {
+		for _, stream := range streams {
+			if StreamLanguage(stream.Language) == language {
+				return stream.Index, nil
+			}
+		}
+	}
+	return audioInfo.AudioStream, nil
+}
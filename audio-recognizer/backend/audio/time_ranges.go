@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"fmt"
+	"sort"
+
+	"tingshengbianzi/backend/models"
+)
+
+// TimeRangeGap 截取多个时间范围时，各范围之间插入的静音时长(秒)，避免相邻范围的语音被识别为同一句
+const TimeRangeGap = 1.0
+
+// ValidateTimeRanges 在不知道文件时长时做基本校验：不能为负数，结束时间为0（到文件末尾）或大于开始时间
+func ValidateTimeRanges(ranges []models.TimeRange) error {
+	for _, r := range ranges {
+		if r.Start < 0 || r.End < 0 {
+			return fmt.Errorf("时间范围不能为负数: %.3f-%.3f", r.Start, r.End)
+		}
+		if r.End != 0 && r.End <= r.Start {
+			return fmt.Errorf("时间范围的结束时间必须大于开始时间: %.3f-%.3f", r.Start, r.End)
+		}
+	}
+	return nil
+}
+
+// ResolveTimeRanges 校验并整理时间范围：按开始时间排序，合并重叠的范围
+// 将结束时间为0的范围补全为文件末尾，并截掉超出文件时长的部分；duration未知（不大于0）时不能补全，拒绝这样的范围
+func ResolveTimeRanges(ranges []models.TimeRange, duration float64) ([]models.TimeRange, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+	if err := ValidateTimeRanges(ranges); err != nil {
+		return nil, err
+	}
+
+	resolved := make([]models.TimeRange, 0, len(ranges))
+	for _, r := range ranges {
+		if duration <= 0 {
+			if r.End == 0 {
+				return nil, fmt.Errorf("音频时长未知，无法确定时间范围的结束时间: %.3f-", r.Start)
+			}
+		} else {
+			if r.Start >= duration {
+				return nil, fmt.Errorf("时间范围超出音频时长 %.3f 秒: %.3f-%.3f", duration, r.Start, r.End)
+			}
+			if r.End == 0 || r.End > duration {
+				r.End = duration
+			}
+		}
+		resolved = append(resolved, r)
+	}
+
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].Start < resolved[j].Start })
+	merged := resolved[:1]
+	for _, r := range resolved[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			last.End = max(last.End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged, nil
+}
+
+// TimeRangesDuration 截取时间范围后的音频时长（含范围之间的静音）
+func TimeRangesDuration(ranges []models.TimeRange) float64 {
+	total := 0.0
+	for i, r := range ranges {
+		if i > 0 {
+			total += TimeRangeGap
+		}
+		total += r.Duration()
+	}
+	return total
+}
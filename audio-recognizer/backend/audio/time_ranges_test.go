@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"tingshengbianzi/backend/models"
+)
+
+// TestResolveTimeRanges 排序合并时间范围，文件时长已知时补全和截断结束时间，时长未知时拒绝到文件末尾的范围
+func TestResolveTimeRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		ranges   []models.TimeRange
+		duration float64
+		want     []models.TimeRange
+		wantErr  string
+	}{
+		{"排序并合并重叠的范围", []models.TimeRange{{Start: 30, End: 40}, {Start: 5, End: 10}, {Start: 8, End: 20}}, 60,
+			[]models.TimeRange{{Start: 5, End: 20}, {Start: 30, End: 40}}, ""},
+		{"结束时间为0补全为文件末尾", []models.TimeRange{{Start: 50, End: 0}, {Start: 10, End: 20}}, 60,
+			[]models.TimeRange{{Start: 10, End: 20}, {Start: 50, End: 60}}, ""},
+		{"截掉超出文件时长的部分", []models.TimeRange{{Start: 10, End: 90}}, 60,
+			[]models.TimeRange{{Start: 10, End: 60}}, ""},
+		{"时长未知时保留确定的范围", []models.TimeRange{{Start: 10, End: 20}}, 0,
+			[]models.TimeRange{{Start: 10, End: 20}}, ""},
+		{"时长未知时拒绝到文件末尾的范围", []models.TimeRange{{Start: 10, End: 20}, {Start: 30, End: 0}}, 0,
+			nil, "音频时长未知"},
+		{"开始时间超出文件时长", []models.TimeRange{{Start: 70, End: 80}}, 60, nil, "超出音频时长"},
+		{"结束时间不大于开始时间", []models.TimeRange{{Start: 20, End: 10}}, 60, nil, "结束时间必须大于开始时间"},
+		{"负数", []models.TimeRange{{Start: -1, End: 10}}, 60, nil, "不能为负数"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTimeRanges(tt.ranges, tt.duration)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("错误为 %v，期望包含 %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("整理时间范围失败: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("时间范围为 %+v，期望 %+v", got, tt.want)
+			}
+			for _, r := range got {
+				if r.Duration() <= 0 {
+					t.Errorf("时间范围 %+v 的时长不为正数，FFmpeg的 -t 参数无效", r)
+				}
+			}
+		})
+	}
+
+	// 请求参数校验时文件时长未知，到文件末尾的范围仍然有效
+	if err := ValidateTimeRanges([]models.TimeRange{{Start: 30, End: 0}}); err != nil {
+		t.Errorf("到文件末尾的范围应通过基本校验: %v", err)
+	}
+}
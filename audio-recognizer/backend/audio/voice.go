@@ -0,0 +1,151 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// voicedAboveFloorDB 帧能量高于噪声基底多少dB时视为语音帧（用于提取说话人特征）
+const voicedAboveFloorDB = 6.0
+
+// VoiceProfile 音频的逐帧声学特征（各声道功率、能量和过零率），用于不依赖声纹模型的说话人区分
+type VoiceProfile struct {
+	channelPower [][]float64 // 各声道逐帧的平均功率
+	levels       []float64   // 所有声道混合后的逐帧能量(dBFS)
+	zeroCrossing []float64   // 混合信号的逐帧过零率（过零次数/采样数）
+	floor        float64
+}
+
+// AnalyzeVoice 逐帧计算WAV文件的声学特征，流式读取避免将长音频全部载入内存
+func AnalyzeVoice(ctx context.Context, wavPath string) (*VoiceProfile, error) {
+	file, err := os.Open(wavPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开WAV文件失败: %w", err)
+	}
+	defer file.Close()
+
+	layout, err := readWAVLayout(file)
+	if err != nil {
+		return nil, err
+	}
+	if layout.channels <= 0 || layout.blockAlign != layout.channels*2 {
+		return nil, fmt.Errorf("不支持的WAV声道布局: 声道=%d, 块对齐=%d", layout.channels, layout.blockAlign)
+	}
+
+	frameSamples := int(float64(layout.sampleRate) * vadFrameDuration)
+	frameBytes := frameSamples * layout.blockAlign
+	reader := bufio.NewReaderSize(io.NewSectionReader(file, layout.dataOffset, layout.dataSize), 1<<20)
+
+	frameCount := int(layout.dataSize/int64(frameBytes)) + 1
+	profile := &VoiceProfile{
+		channelPower: make([][]float64, layout.channels),
+		levels:       make([]float64, 0, frameCount),
+		zeroCrossing: make([]float64, 0, frameCount),
+	}
+	for channel := range profile.channelPower {
+		profile.channelPower[channel] = make([]float64, 0, frameCount)
+	}
+
+	buffer := make([]byte, frameBytes)
+	power := make([]float64, layout.channels)
+	for {
+		if len(profile.levels)%10000 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		n, err := io.ReadFull(reader, buffer)
+		if samples := n / layout.blockAlign; samples > 0 {
+			for channel := range power {
+				power[channel] = 0
+			}
+			var mixedPower float64
+			crossings := 0
+			previous := 0.0
+			for i := 0; i < samples; i++ {
+				mixed := 0.0
+				for channel := 0; channel < layout.channels; channel++ {
+					offset := i*layout.blockAlign + channel*2
+					sample := float64(int16(binary.LittleEndian.Uint16(buffer[offset:offset+2]))) / 32768.0
+					power[channel] += sample * sample
+					mixed += sample
+				}
+				mixed /= float64(layout.channels)
+				mixedPower += mixed * mixed
+				if i > 0 && (mixed >= 0) != (previous >= 0) {
+					crossings++
+				}
+				previous = mixed
+			}
+
+			for channel := range power {
+				profile.channelPower[channel] = append(profile.channelPower[channel], power[channel]/float64(samples))
+			}
+			profile.levels = append(profile.levels, 10*math.Log10(mixedPower/float64(samples)+1e-10))
+			profile.zeroCrossing = append(profile.zeroCrossing, float64(crossings)/float64(samples))
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if len(profile.levels) == 0 {
+		return nil, fmt.Errorf("WAV文件没有音频数据")
+	}
+	profile.floor = noiseFloor(profile.levels)
+	return profile, nil
+}
+
+// Channels 声道数
+func (v *VoiceProfile) Channels() int {
+	return len(v.channelPower)
+}
+
+// ChannelPower 时间范围内各声道的平均功率
+func (v *VoiceProfile) ChannelPower(start, end float64) []float64 {
+	from, to := v.frameRange(start, end)
+	power := make([]float64, len(v.channelPower))
+	if from >= to {
+		return power
+	}
+	for channel, frames := range v.channelPower {
+		for _, value := range frames[from:to] {
+			power[channel] += value
+		}
+		power[channel] /= float64(to - from)
+	}
+	return power
+}
+
+// VoiceFeatures 时间范围内语音帧的平均能量(dBFS)、平均过零率和语音时长(秒)
+// 范围内没有语音帧时voiced为0
+func (v *VoiceProfile) VoiceFeatures(start, end float64) (level, zeroCrossing, voiced float64) {
+	from, to := v.frameRange(start, end)
+	count := 0
+	for i := from; i < to; i++ {
+		if v.levels[i] < v.floor+voicedAboveFloorDB {
+			continue
+		}
+		level += v.levels[i]
+		zeroCrossing += v.zeroCrossing[i]
+		count++
+	}
+	if count == 0 {
+		return 0, 0, 0
+	}
+	return level / float64(count), zeroCrossing / float64(count), float64(count) * vadFrameDuration
+}
+
+// frameRange 将时间范围换算为帧序号范围，范围过短时至少包含一帧
+func (v *VoiceProfile) frameRange(start, end float64) (int, int) {
+	from := frameIndex(start, len(v.levels))
+	to := frameIndex(end, len(v.levels))
+	if to <= from {
+		to = min(from+1, len(v.levels))
+	}
+	return from, to
+}
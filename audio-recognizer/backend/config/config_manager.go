@@ -88,6 +88,17 @@ func (cm *ConfigManager) LoadDefaultConfig() *models.RecognitionConfig {
 			defaultConfig.EnableWordTimestamp = userConfig.EnableWordTimestamp
 			defaultConfig.EnableNormalization = userConfig.EnableNormalization
 			defaultConfig.EnableNoiseReduction = userConfig.EnableNoiseReduction
+			defaultConfig.Engine = userConfig.Engine
+			defaultConfig.AudioFilters = userConfig.AudioFilters
+			defaultConfig.ChunkDuration = userConfig.ChunkDuration
+			defaultConfig.ChunkWorkers = userConfig.ChunkWorkers
+			defaultConfig.DisableChunking = userConfig.DisableChunking
+			defaultConfig.HallucinationFilter = userConfig.HallucinationFilter
+			defaultConfig.Decoding = userConfig.Decoding
+			defaultConfig.Vocabulary = userConfig.Vocabulary
+			defaultConfig.Diarization = userConfig.Diarization
+			defaultConfig.MaxFileSizeMB = userConfig.MaxFileSizeMB
+			defaultConfig.QueueConcurrency = userConfig.QueueConcurrency
 
 			fmt.Printf("✅ 已加载用户配置: 模型路径=%s, 模型文件=%s\n",
 				defaultConfig.ModelPath, defaultConfig.SpecificModelFile)
@@ -137,6 +148,15 @@ func (cm *ConfigManager) SaveConfigToFile(config *models.RecognitionConfig) erro
 	return nil
 }
 
+// GetConfigDirectory 获取配置文件所在目录（词汇表等用户数据也保存在这里）
+func (cm *ConfigManager) GetConfigDirectory() string {
+	userConfigDir, configSubDir := GetUserConfigDirectory()
+	if configSubDir == "" {
+		return userConfigDir
+	}
+	return filepath.Join(userConfigDir, configSubDir)
+}
+
 // ValidateAndFixModelPath 验证并修复模型路径
 func (cm *ConfigManager) ValidateAndFixModelPath(config *models.RecognitionConfig) {
 	validateAndFixModelPath(config)
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 队列任务状态
+const (
+	QueueJobPending   = "pending"   // 等待识别
+	QueueJobRunning   = "running"   // 正在识别
+	QueueJobPaused    = "paused"    // 已暂停，恢复后重新排队
+	QueueJobCompleted = "completed" // 识别完成
+	QueueJobFailed    = "failed"    // 识别失败
+	QueueJobCancelled = "cancelled" // 已取消
+)
+
+// QueueJob 批量识别队列中的一个文件
+type QueueJob struct {
+	ID         string               `json:"id"`                   // 任务ID
+	FilePath   string               `json:"filePath"`             // 音频或视频文件路径
+	Name       string               `json:"name"`                 // 文件名
+	Size       int64                `json:"size"`                 // 文件大小(字节)
+	Status     string               `json:"status"`               // 任务状态
+	Progress   *RecognitionProgress `json:"progress,omitempty"`   // 最近一次识别进度（识别中时存在）
+	ResultID   string               `json:"resultId,omitempty"`   // 识别结果ID（完成后存在）
+	Error      *RecognitionError    `json:"error,omitempty"`      // 失败原因
+	Attempts   int                  `json:"attempts"`             // 已开始识别的次数
+	CreatedAt  time.Time            `json:"createdAt"`            // 加入队列的时间
+	StartedAt  *time.Time           `json:"startedAt,omitempty"`  // 最近一次开始识别的时间
+	FinishedAt *time.Time           `json:"finishedAt,omitempty"` // 完成、失败或取消的时间
+}
+
+// Finished 任务是否已结束（完成、失败或取消）
+func (j *QueueJob) Finished() bool {
+	return j.Status == QueueJobCompleted || j.Status == QueueJobFailed || j.Status == QueueJobCancelled
+}
@@ -0,0 +1,311 @@
+package models
+
+import "time"
+
+// RecognitionResult 语音识别结果
+type RecognitionResult struct {
+	ID                string                `json:"id"`                    // 识别结果ID
+	Language          string                `json:"language"`              // 识别语言
+	Text              string                `json:"text"`                  // 识别文本
+	TimestampedText   string                `json:"timestampedText"`       // 带时间戳的识别文本
+	Segments          []RecognitionResultSegment `json:"segments"`        // 识别结果段落
+	Words             []Word                `json:"words"`                 // 词汇级结果
+	Duration          float64               `json:"duration"`              // 音频时长(秒)
+	Confidence        float64               `json:"confidence"`            // 整体置信度
+	ProcessedAt       time.Time             `json:"processedAt"`           // 处理时间
+	Metadata          map[string]interface{} `json:"metadata"`             // 元数据
+}
+
+// RecognitionResultSegment 识别结果段落
+type RecognitionResultSegment struct {
+	Start      float64                `json:"start"`      // 开始时间(秒)
+	End        float64                `json:"end"`        // 结束时间(秒)
+	Text       string                 `json:"text"`       // 文本内容
+	Confidence float64                `json:"confidence"` // 置信度
+	Words      []Word                 `json:"words"`      // 词汇信息
+	Metadata   map[string]interface{} `json:"metadata"`   // 元数据
+	Translation string                `json:"translation,omitempty"` // 英文译文（双语模式）
+	Speaker    string                 `json:"speaker,omitempty"`     // 说话人（启用说话人分离时）
+}
+
+// Word 词汇信息（符合设计文档规范）
+type Word struct {
+	Text       string  `json:"text"`       // 词汇内容
+	Start      float64 `json:"start"`      // 开始时间(秒)
+	End        float64 `json:"end"`        // 结束时间(秒)
+	Confidence float64 `json:"confidence"` // 置信度
+	Speaker    string  `json:"speaker,omitempty"` // 说话人
+}
+
+// SpecialMark 特殊标记类型
+type SpecialMark struct {
+	Type      string  `json:"type"`      // 标记类型：emphasis, pause, unclear, music, speaker, language
+	StartTime float64 `json:"startTime"` // 开始时间(秒)
+	EndTime   float64 `json:"endTime"`   // 结束时间(秒)
+	Content   string  `json:"content"`   // 标记内容
+	Metadata  map[string]interface{} `json:"metadata"` // 额外元数据
+}
+
+// WordResult 词汇级识别结果（向后兼容）
+type WordResult struct {
+	Word       string  `json:"word"`       // 词汇
+	StartTime  float64 `json:"startTime"`  // 开始时间(秒)
+	EndTime    float64 `json:"endTime"`    // 结束时间(秒)
+	Confidence float64 `json:"confidence"` // 置信度
+}
+
+// RecognitionProgress 识别进度
+type RecognitionProgress struct {
+	CurrentTime   float64 `json:"currentTime"`   // 当前处理时间(秒)
+	TotalTime     float64 `json:"totalTime"`     // 总时间(秒)
+	Percentage    int     `json:"percentage"`    // 完成百分比
+	Status        string  `json:"status"`        // 状态描述
+	WordsPerSec   float64 `json:"wordsPerSec"`   // 识别速度(词/秒)
+	Partial       *RecognitionResultSegment `json:"partial,omitempty"` // 刚解码出的段落（实时结果）
+	ChunkIndex    int     `json:"chunkIndex,omitempty"`    // 分块识别时当前分块序号（从1开始）
+	ChunkCount    int     `json:"chunkCount,omitempty"`    // 分块识别时的分块总数
+}
+
+// 识别任务类型
+const (
+	TaskTranscribe = "transcribe" // 按原语言转写
+	TaskTranslate  = "translate"  // 转写并翻译为英文
+	TaskBilingual  = "bilingual"  // 原文与英文译文双语输出
+)
+
+// RecognitionOptions 单次识别的选项
+type RecognitionOptions struct {
+	Task              string `json:"task"`                        // 识别任务，留空时为transcribe
+	SpecificModelFile string `json:"specificModelFile,omitempty"` // 指定的模型文件，留空时使用配置
+	Decoding          *DecodingOptions `json:"decoding,omitempty"`   // 本次识别的解码参数，nil时使用配置
+	Vocabulary        *Vocabulary      `json:"vocabulary,omitempty"` // 本次识别使用的词汇表，nil时不做词汇提示和纠正
+	Diarization       *DiarizationConfig `json:"diarization,omitempty"` // 本次识别的说话人分离参数，nil时使用配置
+	AudioTrack        *int               `json:"audioTrack,omitempty"`  // 多音轨文件使用的音轨序号(从0开始)，nil时按识别语言和默认标记自动选择
+	TimeRanges        []TimeRange        `json:"timeRanges,omitempty"`  // 只识别这些时间范围，为空时识别整个文件
+}
+
+// TimeRange 原始音频中的一段时间范围
+type TimeRange struct {
+	Start float64 `json:"start"` // 开始时间(秒)
+	End   float64 `json:"end"`   // 结束时间(秒)，0表示到文件末尾
+}
+
+// Duration 时间范围的时长(秒)
+func (r TimeRange) Duration() float64 {
+	return r.End - r.Start
+}
+
+// SegmentRevision 重新识别替换的一组连续段落
+type SegmentRevision struct {
+	Indexes  []int   `json:"indexes"`  // 被替换段落在替换前结果中的序号
+	Start    float64 `json:"start"`    // 原段落开始时间(秒)
+	End      float64 `json:"end"`      // 原段落结束时间(秒)
+	OldText  string  `json:"oldText"`  // 原识别文本
+	NewText  string  `json:"newText"`  // 重新识别的文本
+	NewCount int     `json:"newCount"` // 替换后的段落数
+}
+
+// RerecognitionRecord 一次重新识别的记录
+type RerecognitionRecord struct {
+	ModelFile string            `json:"modelFile"` // 重新识别使用的模型文件
+	Padding   float64           `json:"padding"`   // 截取音频时段落前后扩展的时长(秒)
+	At        time.Time         `json:"at"`        // 重新识别时间
+	Revisions []SegmentRevision `json:"revisions"` // 替换的段落
+	Unchanged [][]int           `json:"unchanged,omitempty"` // 没有识别出文本、保留原文的段落
+}
+
+// AudioFile 音频文件信息，格式、编码、采样率等描述原始文件，Output开头的字段描述转换后的WAV
+type AudioFile struct {
+	Path     string  `json:"path"`     // 文件路径
+	Name     string  `json:"name"`     // 文件名
+	Size     int64   `json:"size"`     // 文件大小(字节)
+	Duration float64 `json:"duration"` // 音频时长(秒)
+	Format   string  `json:"format"`   // 原始文件格式（扩展名）
+	SampleRate int  `json:"sampleRate"` // 原始采样率
+	Channels int    `json:"channels"`   // 原始声道数
+	BitRate  int    `json:"bitRate"`    // 原始比特率
+	Codec       string            `json:"codec,omitempty"`       // 原始音频编码（如mp3、aac）
+	Container   string            `json:"container,omitempty"`   // 容器格式（FFprobe的format_name）
+	Tags        map[string]string `json:"tags,omitempty"`        // 元数据标签（title/artist/album等，键为小写）
+	StreamCount int               `json:"streamCount,omitempty"` // 流数量
+	FilterChain string            `json:"filterChain,omitempty"` // 转换时实际应用的FFmpeg滤镜链
+	AudioStreams []AudioStreamInfo `json:"audioStreams,omitempty"` // 全部音轨
+	AudioStream  int               `json:"audioStream"`            // 识别使用的音轨序号（AudioStreams中的位置）
+	Video        *VideoInfo        `json:"video,omitempty"`        // 视频流信息，音频文件为nil
+	TimeRanges   []TimeRange       `json:"timeRanges,omitempty"`   // 转换时截取的时间范围（结束时间已确定），为空时转换了整个文件
+	ConvertedDuration float64      `json:"convertedDuration,omitempty"` // 转换后WAV的时长(秒)，截取时间范围时小于Duration
+	OutputSampleRate  int          `json:"outputSampleRate,omitempty"`  // 转换后WAV的采样率
+	OutputChannels    int          `json:"outputChannels,omitempty"`    // 转换后WAV的声道数
+}
+
+// AudioStreamInfo 音轨信息
+type AudioStreamInfo struct {
+	Index      int    `json:"index"`              // 音轨序号(从0开始，对应FFmpeg的 0:a:N)
+	Codec      string `json:"codec"`              // 音频编码
+	Language   string `json:"language,omitempty"` // 语言标签（通常为ISO 639-2，如chi、eng）
+	Title      string `json:"title,omitempty"`    // 音轨标题
+	SampleRate int    `json:"sampleRate"`         // 采样率
+	Channels   int    `json:"channels"`           // 声道数
+	Default    bool   `json:"default"`            // 是否为默认音轨
+}
+
+// VideoInfo 视频流信息，用于导出与视频对齐的字幕
+type VideoInfo struct {
+	Codec         string  `json:"codec"`                // 视频编码
+	Width         int     `json:"width"`                // 宽度(像素)
+	Height        int     `json:"height"`               // 高度(像素)
+	FrameRate     float64 `json:"frameRate"`            // 帧率
+	FrameRateText string  `json:"frameRateText"`        // FFprobe给出的帧率分数（如30000/1001）
+	Duration      float64 `json:"duration"`             // 视频时长(秒)
+	FrameCount    int     `json:"frameCount,omitempty"` // 总帧数
+}
+
+// RecognitionConfig 识别配置
+type RecognitionConfig struct {
+	Language              string  `json:"language"`              // 识别语言
+	ModelPath             string  `json:"modelPath"`             // 模型路径
+	SpecificModelFile     string  `json:"specificModelFile"`     // 具体指定的模型文件
+	SampleRate            int     `json:"sampleRate"`            // 采样率
+	BufferSize            int     `json:"bufferSize"`            // 缓冲区大小
+	ConfidenceThreshold   float64 `json:"confidenceThreshold"`   // 置信度阈值
+	MaxAlternatives       int     `json:"maxAlternatives"`       // 最大候选数
+	EnableWordTimestamp   bool    `json:"enableWordTimestamp"`   // 启用词汇时间戳
+	EnableNormalization   bool    `json:"enableNormalization"`   // 启用音频归一化
+	EnableNoiseReduction  bool    `json:"enableNoiseReduction"`  // 启用噪声抑制
+	Engine                string  `json:"engine,omitempty"`      // 识别引擎名称，留空时自动选择
+	AudioFilters          AudioFilterConfig `json:"audioFilters"`   // 预处理滤镜参数
+	ChunkDuration         int     `json:"chunkDuration"`         // 长音频分块的目标时长(秒)，0为默认600
+	ChunkWorkers          int     `json:"chunkWorkers"`          // 并行识别的分块数，0为默认2
+	DisableChunking       bool    `json:"disableChunking"`       // 关闭长音频分块识别
+	HallucinationFilter   HallucinationFilterConfig `json:"hallucinationFilter"` // 幻觉与重复检测
+	Decoding              DecodingOptions `json:"decoding"`        // Whisper解码参数
+	Vocabulary            string  `json:"vocabulary"`            // 默认使用的词汇表名称，留空时不使用
+	Diarization           DiarizationConfig `json:"diarization"`  // 说话人分离
+	MaxFileSizeMB         int     `json:"maxFileSizeMB"`         // 可识别文件的大小上限(MB)，0为默认2048
+	QueueConcurrency      int     `json:"queueConcurrency"`      // 批量识别队列同时识别的文件数，0为默认1
+}
+
+// DiarizationConfig 说话人分离参数，数值为0时使用默认值
+type DiarizationConfig struct {
+	Mode        string  `json:"mode"`        // 分离方式: off | auto | tinydiarize | stereo | pause，留空为off
+	Speakers    int     `json:"speakers"`    // 已知的说话人数（pause方式按该数聚类），0为自动估计
+	MaxSpeakers int     `json:"maxSpeakers"` // 自动估计时的最大说话人数，默认8
+	TurnGap     float64 `json:"turnGap"`     // 段落间停顿超过该值(秒)时视为可能换人，默认1
+}
+
+// HallucinationFilterConfig 识别结果的幻觉与重复检测参数，数值为0时使用默认值
+type HallucinationFilterConfig struct {
+	Disabled                  bool                `json:"disabled"`                  // 关闭检测
+	FlagOnly                  bool                `json:"flagOnly"`                  // 只在段落元数据中标记，不删除或合并
+	DropSilent                bool                `json:"dropSilent"`                // 删除静音段（默认只标记）
+	DropKnownPhrases          bool                `json:"dropKnownPhrases"`          // 删除只有幻觉短语的段落（默认只标记）
+	Phrases                   map[string][]string `json:"phrases"`                   // 按Whisper语言代码补充的幻觉短语
+	CompressionRatioThreshold float64             `json:"compressionRatioThreshold"` // 文本压缩比上限，默认2.4
+	SilenceMarginDB           float64             `json:"silenceMarginDB"`           // 段落峰值能量低于噪声基底加该值(dB)视为静音，默认6
+}
+
+// DecodingOptions Whisper解码参数，数值为0或空时使用预设或whisper-cli默认值
+type DecodingOptions struct {
+	Preset               string  `json:"preset"`               // 预设: fast | balanced | accurate
+	InitialPrompt        string  `json:"initialPrompt"`        // 初始提示词（--prompt），可提供专有名词和标点风格
+	BeamSize             int     `json:"beamSize"`             // 束搜索宽度（-bs）
+	BestOf               int     `json:"bestOf"`               // 采样候选数（-bo）
+	Temperature          float64 `json:"temperature"`          // 采样温度（-tp）
+	TemperatureIncrement float64 `json:"temperatureIncrement"` // 回退时的温度增量（-tpi）
+	EntropyThreshold     float64 `json:"entropyThreshold"`     // 熵阈值，超过时温度回退（-et）
+	LogprobThreshold     float64 `json:"logprobThreshold"`     // 平均对数概率阈值，低于时温度回退（-lpt）
+	MaxSegmentLength     int     `json:"maxSegmentLength"`     // 段落最大字符数（-ml）
+	SplitOnWord          bool    `json:"splitOnWord"`          // 按词而不是token切分段落（-sow）
+	NoSpeechThreshold    float64 `json:"noSpeechThreshold"`    // 无语音概率阈值（-nth）
+	Threads              int     `json:"threads"`              // 解码线程数（-t）
+	NoFallback           *bool   `json:"noFallback,omitempty"` // 关闭温度回退（--no-fallback），nil时使用预设或whisper-cli默认值（启用回退）
+}
+
+// AudioFilterConfig 音频预处理滤镜参数，数值未设置(null)时使用默认值，0是有效的设置
+type AudioFilterConfig struct {
+	LoudnessTarget       *float64 `json:"loudnessTarget,omitempty"`       // 响度归一化目标(LUFS)，默认-16
+	LoudnessRange        *float64 `json:"loudnessRange,omitempty"`        // 响度范围(LU)，默认11
+	TruePeak             *float64 `json:"truePeak,omitempty"`             // 真峰值上限(dBTP)，默认-1.5
+	NoiseReductionMethod string   `json:"noiseReductionMethod"`           // 降噪方式: afftdn | arnndn，默认afftdn
+	NoiseFloor           *float64 `json:"noiseFloor,omitempty"`           // afftdn噪声基底(dB)，默认-25
+	NoiseReduction       *float64 `json:"noiseReduction,omitempty"`       // afftdn降噪量(dB)，默认12
+	RNNoiseModel         string   `json:"rnnoiseModel"`                   // arnndn使用的RNNoise模型文件(.rnnn)
+	HighPassFrequency    int      `json:"highPassFrequency"`              // 高通滤波截止频率(Hz)，0为关闭
+	EnableCompression    bool     `json:"enableCompression"`              // 启用动态范围压缩
+	CompressionThreshold *float64 `json:"compressionThreshold,omitempty"` // 压缩阈值(dB)，默认-20
+	CompressionRatio     *float64 `json:"compressionRatio,omitempty"`     // 压缩比，默认3
+}
+
+// ExportFormat 导出格式
+type ExportFormat string
+
+const (
+	ExportFormatTXT  ExportFormat = "txt"  // 纯文本
+	ExportFormatSRT  ExportFormat = "srt"  // SRT字幕
+	ExportFormatVTT  ExportFormat = "vtt"  // WebVTT
+	ExportFormatJSON ExportFormat = "json" // JSON
+	ExportFormatASS  ExportFormat = "ass"  // Advanced SubStation Alpha
+	ExportFormatTTML ExportFormat = "ttml" // TTML（IMSC1文本配置）
+	ExportFormatSBV  ExportFormat = "sbv"  // YouTube SBV
+	ExportFormatSTL  ExportFormat = "stl"  // EBU-STL（二进制）
+	ExportFormatFCPXML   ExportFormat = "fcpxml"   // Final Cut Pro / DaVinci Resolve 时间线
+	ExportFormatXMEML    ExportFormat = "xmeml"    // Premiere Pro XML 时间线
+	ExportFormatEDL      ExportFormat = "edl"      // CMX3600 EDL
+	ExportFormatAudacity ExportFormat = "audacity" // Audacity 标签轨道
+	ExportFormatDOCX     ExportFormat = "docx"     // Word文档
+	ExportFormatMarkdown ExportFormat = "md"       // Markdown文稿
+	ExportFormatHTML     ExportFormat = "html"     // 单文件HTML文稿
+)
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	Format            ExportFormat `json:"format"`            // 导出格式
+	IncludeTimestamp  bool         `json:"includeTimestamp"`  // 包含时间戳
+	IncludeConfidence bool         `json:"includeConfidence"` // 包含置信度
+	OutputEncoding    string       `json:"outputEncoding"`    // 输出编码
+	SplitText         bool         `json:"splitText"`         // 分段文本（字幕：按词时间把长段落拆成多条字幕）
+	MaxLineLength     int          `json:"maxLineLength"`     // 最大行长度（字幕每行字符数，0为按语言的默认值）
+
+	// 字幕选项，0为默认值
+	MaxLines           int     `json:"maxLines"`           // 每条字幕最多行数（默认2）
+	MaxCharsPerSecond  float64 `json:"maxCharsPerSecond"`  // 最大阅读速度(字符/秒)，超出时在不重叠下一条的前提下延长显示
+	MinCueDuration     float64 `json:"minCueDuration"`     // 每条字幕最短显示时长(秒)
+	MaxCueDuration     float64 `json:"maxCueDuration"`     // 每条字幕最长显示时长(秒)
+	MinCueGap          float64 `json:"minCueGap"`          // 相邻字幕的最小间隔(秒)
+	IncludeSpeaker     bool    `json:"includeSpeaker"`     // 说话人变化时标注说话人
+	IncludeTranslation bool    `json:"includeTranslation"` // 在原文下方附加译文（双语字幕）
+	FrameRate          float64 `json:"frameRate"`          // 时间码帧率（TTML、EBU-STL、剪辑软件时间线），默认使用视频帧率或25
+	StartTimecode      string  `json:"startTimecode"`      // 剪辑软件时间线的起始时间码 HH:MM:SS:FF，默认 00:00:00:00
+	ParagraphPause     float64 `json:"paragraphPause"`     // 文稿（DOCX、Markdown、HTML）中停顿超过该时长(秒)时另起一段，默认2
+	TemplatePath       string  `json:"templatePath"`       // 文稿模板文件，为空时使用模板目录中的模板或内置模板
+
+	ASS ASSOptions `json:"ass"` // ASS字幕选项
+}
+
+// ASSStyle ASS字幕样式，颜色为 #RRGGBB，空值和0为默认值
+type ASSStyle struct {
+	FontName       string  `json:"fontName"`       // 字体
+	FontSize       int     `json:"fontSize"`       // 字号（按1080p画面）
+	PrimaryColor   string  `json:"primaryColor"`   // 文字颜色（卡拉OK已唱部分）
+	SecondaryColor string  `json:"secondaryColor"` // 卡拉OK未唱部分的颜色
+	OutlineColor   string  `json:"outlineColor"`   // 描边颜色
+	BackColor      string  `json:"backColor"`      // 阴影颜色
+	Bold           bool    `json:"bold"`           // 粗体
+	Italic         bool    `json:"italic"`         // 斜体
+	Outline        float64 `json:"outline"`        // 描边宽度
+	Shadow         float64 `json:"shadow"`         // 阴影距离
+	Alignment      int     `json:"alignment"`      // 对齐方式，按小键盘布局1-9（默认2，底部居中）
+	MarginL        int     `json:"marginL"`        // 左边距
+	MarginR        int     `json:"marginR"`        // 右边距
+	MarginV        int     `json:"marginV"`        // 垂直边距
+}
+
+// ASSOptions ASS字幕选项
+type ASSOptions struct {
+	Style            ASSStyle          `json:"style"`            // 原文样式
+	TranslationStyle ASSStyle          `json:"translationStyle"` // 双语字幕的译文样式，未设置的项沿用原文样式（字号为原文的75%）
+	SpeakerColors    map[string]string `json:"speakerColors"`    // 说话人 -> 文字颜色，未指定的说话人依次使用预设颜色
+	Karaoke          bool              `json:"karaoke"`          // 按词时间生成卡拉OK(\k)标签
+	PlayResX         int               `json:"playResX"`         // 画面宽度，默认使用视频宽度或1920
+	PlayResY         int               `json:"playResY"`         // 画面高度，默认使用视频高度或1080
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// VocabularyTerm 词汇表中的术语
+type VocabularyTerm struct {
+	Term          string   `json:"term"`          // 术语
+	Spelling      string   `json:"spelling"`      // 首选写法，留空时使用Term
+	Pronunciation string   `json:"pronunciation"` // 发音提示：拼音（如"wei1 xin4"，可带声调）或英文近音写法
+	Aliases       []string `json:"aliases"`       // 常见的误识别写法，出现时直接替换
+}
+
+// Output 纠正时写入文本的形式
+func (t VocabularyTerm) Output() string {
+	if t.Spelling != "" {
+		return t.Spelling
+	}
+	return t.Term
+}
+
+// Vocabulary 项目词汇表（专有名词、产品名和行业术语）
+type Vocabulary struct {
+	Name      string           `json:"name"`      // 词汇表名称（项目名）
+	Terms     []VocabularyTerm `json:"terms"`     // 术语列表，靠前的术语优先写入初始提示词
+	UpdatedAt time.Time        `json:"updatedAt"` // 更新时间
+}
+
+// VocabularyCorrection 词汇纠正记录（也用于未改写文本的纠正建议）
+type VocabularyCorrection struct {
+	SegmentIndex int     `json:"segmentIndex"` // 段落序号
+	Start        float64 `json:"start"`        // 段落开始时间(秒)
+	End          float64 `json:"end"`          // 段落结束时间(秒)
+	Original     string  `json:"original"`     // 原识别文本
+	Corrected    string  `json:"corrected"`    // 纠正后（或建议）的文本
+	Term         string  `json:"term"`         // 命中的术语
+	Method       string  `json:"method"`       // 匹配方式: alias | case | fuzzy | pinyin | pinyin_fuzzy
+}
@@ -3,6 +3,7 @@ package path
 import (
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -59,6 +60,13 @@ func (dm *DependencyManager) GetRequiredDependencyFiles() []string {
 	}
 }
 
+// GetOptionalDependencyFiles 获取可选的依赖文件列表（未打包时跳过，不视为失败）
+func (dm *DependencyManager) GetOptionalDependencyFiles() []string {
+	return []string{
+		"third-party/bin/whisper-server", // 常驻识别服务，存在时优先于whisper-cli使用
+	}
+}
+
 // ExtractThirdPartyFile 提取单个第三方依赖文件
 func (dm *DependencyManager) ExtractThirdPartyFile(embedPath, targetDir string) error {
 	fmt.Printf("📦 提取文件: %s\n", embedPath)
@@ -145,6 +153,18 @@ func (dm *DependencyManager) ExtractAllDependencies() *ExtractionResult {
 		}
 	}
 
+	for _, filePath := range dm.GetOptionalDependencyFiles() {
+		if _, err := fs.Stat(dm.fs, filePath); err != nil {
+			fmt.Printf("⏭️ 可选依赖未打包，跳过: %s\n", filePath)
+			continue
+		}
+		if err := dm.ExtractThirdPartyFile(filePath, targetDir); err != nil {
+			fmt.Printf("⚠️ 可选依赖提取失败: %s, %v\n", filePath, err)
+		} else {
+			result.ExtractedCount++
+		}
+	}
+
 	result.Success = len(result.FailedFiles) == 0
 
 	if result.Success {
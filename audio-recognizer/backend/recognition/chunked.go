@@ -0,0 +1,281 @@
+package recognition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+)
+
+// defaultChunkWorkers 默认并行识别的分块数
+const defaultChunkWorkers = 2
+
+// whisperRun 一次识别（整段音频或单个分块）的解析结果，时间相对于被识别的音频
+type whisperRun struct {
+	segments        []models.RecognitionResultSegment
+	detected        string  // 自动检测出的Whisper语言代码
+	probability     float64 // 检测语言的概率
+	timestampSource string  // token | srt
+}
+
+// chunkRecognizer 识别单个分块
+type chunkRecognizer func(ctx context.Context, chunk audio.AudioChunk, progressCallback func(*models.RecognitionProgress)) (*whisperRun, error)
+
+// chunkOptionsFromConfig 根据识别配置生成切分参数
+func chunkOptionsFromConfig(config *models.RecognitionConfig) audio.ChunkOptions {
+	return audio.ChunkOptions{TargetDuration: float64(config.ChunkDuration)}
+}
+
+// chunkWorkersFromConfig 获取并行识别的分块数
+func chunkWorkersFromConfig(config *models.RecognitionConfig) int {
+	if config.ChunkWorkers > 0 {
+		return config.ChunkWorkers
+	}
+	return defaultChunkWorkers
+}
+
+// splitForChunking 按配置在静音处切分长音频，未启用分块或音频较短时返回nil
+func splitForChunking(ctx context.Context, processor *audio.Processor, wavPath string, config *models.RecognitionConfig) ([]audio.AudioChunk, error) {
+	if config.DisableChunking {
+		return nil, nil
+	}
+	return processor.SplitWAVAtSilence(ctx, wavPath, chunkOptionsFromConfig(config))
+}
+
+// recognizeChunks 并行识别各分块，并按全局时间拼接结果
+// 任一分块失败时取消其余分块，返回第一个错误
+func recognizeChunks(ctx context.Context, chunks []audio.AudioChunk, totalDuration float64, workers int, recognize chunkRecognizer, progressCallback func(*models.RecognitionProgress)) (*whisperRun, error) {
+	chunkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+	fmt.Printf("🧩 分块识别: %d 块, 并行数 %d\n", len(chunks), workers)
+
+	progress := newChunkProgressTracker(chunks, totalDuration, progressCallback)
+	runs := make([]*whisperRun, len(chunks))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				chunk := chunks[index]
+				run, err := recognize(chunkCtx, chunk, progress.callbackFor(chunk))
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				runs[index] = run
+				progress.complete(chunk)
+			}
+		}()
+	}
+
+feed:
+	for index := range chunks {
+		select {
+		case jobs <- index:
+		case <-chunkCtx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, newCancelledError(ctx)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return stitchChunkRuns(chunks, runs), nil
+}
+
+// stitchChunkRuns 将各分块结果平移到全局时间并拼接
+// 分块之间有重叠，只保留中点落在分块负责范围内的段落，并去掉与上一段重复的段落
+func stitchChunkRuns(chunks []audio.AudioChunk, runs []*whisperRun) *whisperRun {
+	merged := &whisperRun{}
+	sources := make(map[string]bool)
+
+	for i, chunk := range chunks {
+		run := runs[i]
+		if run == nil {
+			continue
+		}
+		if run.detected != "" && (merged.detected == "" || run.probability > merged.probability) {
+			merged.detected = run.detected
+			merged.probability = run.probability
+		}
+		sources[run.timestampSource] = true
+
+		last := i == len(chunks)-1
+		for _, segment := range run.segments {
+			segment = shiftSegment(segment, chunk.Start)
+			middle := (segment.Start + segment.End) / 2
+			if middle < chunk.KeepStart || (middle >= chunk.KeepEnd && !last) {
+				continue
+			}
+			if n := len(merged.segments); n > 0 && isDuplicateSegment(merged.segments[n-1], segment) {
+				continue
+			}
+			segment.Metadata["chunk"] = chunk.Index
+			merged.segments = append(merged.segments, segment)
+		}
+	}
+
+	merged.timestampSource = "token"
+	if len(sources) == 1 {
+		for source := range sources {
+			merged.timestampSource = source
+		}
+	} else if len(sources) > 1 {
+		merged.timestampSource = "mixed"
+	}
+	return merged
+}
+
+// shiftSegment 将段落及其中的词平移offset秒
+func shiftSegment(segment models.RecognitionResultSegment, offset float64) models.RecognitionResultSegment {
+	segment.Start += offset
+	segment.End += offset
+
+	words := make([]models.Word, len(segment.Words))
+	for i, word := range segment.Words {
+		word.Start += offset
+		word.End += offset
+		words[i] = word
+	}
+	segment.Words = words
+
+	metadata := make(map[string]interface{}, len(segment.Metadata)+1)
+	for key, value := range segment.Metadata {
+		metadata[key] = value
+	}
+	segment.Metadata = metadata
+	return segment
+}
+
+// isDuplicateSegment 判断相邻段落是否为重叠区域被两个分块重复识别的同一内容
+func isDuplicateSegment(previous, current models.RecognitionResultSegment) bool {
+	if current.Start >= previous.End {
+		return false
+	}
+	a, b := normalizeSegmentText(previous.Text), normalizeSegmentText(current.Text)
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// normalizeSegmentText 去掉空白和标点并转为小写，用于比较段落内容
+func normalizeSegmentText(text string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// chunkProgressTracker 汇总各分块的识别进度
+type chunkProgressTracker struct {
+	mu               sync.Mutex
+	chunks           []audio.AudioChunk
+	fractions        []float64 // 各分块已完成比例
+	totalDuration    float64
+	completed        int
+	startedAt        time.Time
+	progressCallback func(*models.RecognitionProgress)
+}
+
+// newChunkProgressTracker 创建分块进度汇总器
+func newChunkProgressTracker(chunks []audio.AudioChunk, totalDuration float64, progressCallback func(*models.RecognitionProgress)) *chunkProgressTracker {
+	return &chunkProgressTracker{
+		chunks:           chunks,
+		fractions:        make([]float64, len(chunks)),
+		totalDuration:    totalDuration,
+		startedAt:        time.Now(),
+		progressCallback: progressCallback,
+	}
+}
+
+// callbackFor 生成单个分块的进度回调，将分块内进度换算为整体进度，实时段落平移到全局时间
+func (t *chunkProgressTracker) callbackFor(chunk audio.AudioChunk) func(*models.RecognitionProgress) {
+	return func(progress *models.RecognitionProgress) {
+		if t.progressCallback == nil || progress == nil {
+			return
+		}
+
+		t.mu.Lock()
+		if fraction := float64(min(progress.Percentage, 99)) / 100.0; fraction > t.fractions[chunk.Index] {
+			t.fractions[chunk.Index] = fraction
+		}
+		snapshot := t.snapshot(chunk, fmt.Sprintf("正在识别第 %d/%d 块...", chunk.Index+1, len(t.chunks)))
+		t.mu.Unlock()
+
+		snapshot.WordsPerSec = progress.WordsPerSec
+		if progress.Partial != nil {
+			partial := shiftSegment(*progress.Partial, chunk.Start)
+			middle := (partial.Start + partial.End) / 2
+			if middle >= chunk.KeepStart && middle < chunk.KeepEnd {
+				snapshot.Partial = &partial
+			}
+		}
+		t.progressCallback(snapshot)
+	}
+}
+
+// complete 标记分块识别完成
+func (t *chunkProgressTracker) complete(chunk audio.AudioChunk) {
+	if t.progressCallback == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.fractions[chunk.Index] = 1
+	t.completed++
+	snapshot := t.snapshot(chunk, fmt.Sprintf("已完成 %d/%d 块", t.completed, len(t.chunks)))
+	t.mu.Unlock()
+
+	t.progressCallback(snapshot)
+}
+
+// snapshot 按各分块时长加权计算整体进度（调用方需持有锁）
+func (t *chunkProgressTracker) snapshot(chunk audio.AudioChunk, status string) *models.RecognitionProgress {
+	var done, total float64
+	for i, c := range t.chunks {
+		done += c.Duration() * t.fractions[i]
+		total += c.Duration()
+	}
+	fraction := 0.0
+	if total > 0 {
+		fraction = done / total
+	}
+
+	return &models.RecognitionProgress{
+		CurrentTime: t.totalDuration * fraction,
+		TotalTime:   t.totalDuration,
+		Percentage:  min(int(fraction*100), 99), // 100% 留给结果拼接完成后发送
+		Status:      status,
+		ChunkIndex:  chunk.Index + 1,
+		ChunkCount:  len(t.chunks),
+	}
+}
@@ -0,0 +1,250 @@
+package recognition
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"tingshengbianzi/backend/models"
+)
+
+// 解码参数预设
+const (
+	DecodingPresetFast     = "fast"     // 贪心解码、关闭温度回退，速度优先
+	DecodingPresetBalanced = "balanced" // whisper-cli默认的束搜索参数
+	DecodingPresetAccurate = "accurate" // 最大束宽并启用温度回退，准确率优先
+)
+
+// maxWhisperDecoders whisper.cpp支持的最大并行解码数，束宽和候选数不能超过该值
+const maxWhisperDecoders = 8
+
+// decodingPresets 预设对应的解码参数
+var decodingPresets = map[string]models.DecodingOptions{
+	DecodingPresetFast: {
+		BeamSize:   1,
+		BestOf:     1,
+		NoFallback: boolPtr(true),
+	},
+	DecodingPresetBalanced: {
+		BeamSize:   5,
+		BestOf:     5,
+		NoFallback: boolPtr(false),
+	},
+	DecodingPresetAccurate: {
+		BeamSize:             maxWhisperDecoders,
+		BestOf:               maxWhisperDecoders,
+		TemperatureIncrement: 0.2,
+		EntropyThreshold:     2.4,
+		LogprobThreshold:     -1.0,
+		NoFallback:           boolPtr(false),
+	},
+}
+
+// GetDecodingPresets 获取解码参数预设（供前端展示）
+func GetDecodingPresets() map[string]models.DecodingOptions {
+	presets := make(map[string]models.DecodingOptions, len(decodingPresets))
+	for name, preset := range decodingPresets {
+		preset.Preset = name
+		presets[name] = preset
+	}
+	return presets
+}
+
+// ResolveDecodingOptions 展开预设并校验解码参数，显式设置的非零参数优先于预设
+func ResolveDecodingOptions(options models.DecodingOptions) (models.DecodingOptions, error) {
+	if options.Preset != "" {
+		preset, ok := decodingPresets[options.Preset]
+		if !ok {
+			return options, fmt.Errorf("未知的解码预设: %s（可选 fast、balanced、accurate）", options.Preset)
+		}
+		if options.BeamSize == 0 {
+			options.BeamSize = preset.BeamSize
+		}
+		if options.BestOf == 0 {
+			options.BestOf = preset.BestOf
+		}
+		if options.TemperatureIncrement == 0 {
+			options.TemperatureIncrement = preset.TemperatureIncrement
+		}
+		if options.EntropyThreshold == 0 {
+			options.EntropyThreshold = preset.EntropyThreshold
+		}
+		if options.LogprobThreshold == 0 {
+			options.LogprobThreshold = preset.LogprobThreshold
+		}
+		if options.NoFallback == nil {
+			options.NoFallback = cloneBool(preset.NoFallback)
+		}
+	}
+
+	return options, validateDecodingOptions(options)
+}
+
+// validateDecodingOptions 校验解码参数范围
+func validateDecodingOptions(options models.DecodingOptions) error {
+	switch {
+	case options.BeamSize < 0 || options.BeamSize > maxWhisperDecoders:
+		return fmt.Errorf("beamSize 必须在 0~%d 之间: %d", maxWhisperDecoders, options.BeamSize)
+	case options.BestOf < 0 || options.BestOf > maxWhisperDecoders:
+		return fmt.Errorf("bestOf 必须在 0~%d 之间: %d", maxWhisperDecoders, options.BestOf)
+	case options.Temperature < 0 || options.Temperature > 1:
+		return fmt.Errorf("temperature 必须在 0~1 之间: %g", options.Temperature)
+	case options.TemperatureIncrement < 0 || options.TemperatureIncrement > 1:
+		return fmt.Errorf("temperatureIncrement 必须在 0~1 之间: %g", options.TemperatureIncrement)
+	case options.EntropyThreshold < 0:
+		return fmt.Errorf("entropyThreshold 不能为负数: %g", options.EntropyThreshold)
+	case options.LogprobThreshold > 0:
+		return fmt.Errorf("logprobThreshold 不能为正数: %g", options.LogprobThreshold)
+	case options.MaxSegmentLength < 0:
+		return fmt.Errorf("maxSegmentLength 不能为负数: %d", options.MaxSegmentLength)
+	case options.NoSpeechThreshold < 0 || options.NoSpeechThreshold > 1:
+		return fmt.Errorf("noSpeechThreshold 必须在 0~1 之间: %g", options.NoSpeechThreshold)
+	case options.Threads < 0:
+		return fmt.Errorf("threads 不能为负数: %d", options.Threads)
+	}
+	return nil
+}
+
+// MergeDecodingOverrides 用单次识别选项覆盖解码参数
+// overrides 中与DecodingOptions的JSON字段同名的键生效（如 preset、beamSize、initialPrompt），其余键忽略
+// 优先级: 全局配置 < 本次识别选择的预设 < 本次识别显式设置的参数
+func MergeDecodingOverrides(base models.DecodingOptions, overrides map[string]interface{}) (models.DecodingOptions, error) {
+	if len(overrides) == 0 {
+		return base, nil
+	}
+	content, err := json.Marshal(overrides)
+	if err != nil {
+		return base, fmt.Errorf("序列化识别选项失败: %w", err)
+	}
+	var job struct {
+		Preset string `json:"preset"`
+	}
+	if err := json.Unmarshal(content, &job); err != nil {
+		return base, fmt.Errorf("解析解码参数失败: %w", err)
+	}
+
+	// 解析时会写入已有指针指向的值，先复制，避免修改全局配置
+	merged := base
+	merged.NoFallback = cloneBool(base.NoFallback)
+	if job.Preset != "" {
+		preset, ok := decodingPresets[job.Preset]
+		if !ok {
+			return base, fmt.Errorf("未知的解码预设: %s（可选 fast、balanced、accurate）", job.Preset)
+		}
+		merged = applyDecodingPreset(merged, job.Preset, preset)
+	}
+	if err := json.Unmarshal(content, &merged); err != nil {
+		return base, fmt.Errorf("解析解码参数失败: %w", err)
+	}
+	return merged, nil
+}
+
+// applyDecodingPreset 用预设替换解码策略相关的参数（束宽、候选数、温度回退），提示词、线程数等其他参数保留
+func applyDecodingPreset(options models.DecodingOptions, name string, preset models.DecodingOptions) models.DecodingOptions {
+	options.Preset = name
+	options.BeamSize = preset.BeamSize
+	options.BestOf = preset.BestOf
+	options.TemperatureIncrement = preset.TemperatureIncrement
+	options.EntropyThreshold = preset.EntropyThreshold
+	options.LogprobThreshold = preset.LogprobThreshold
+	options.NoFallback = cloneBool(preset.NoFallback)
+	return options
+}
+
+// boolPtr 返回指向布尔值的指针，用于可选的布尔参数
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+// cloneBool 复制可选的布尔参数，nil保持nil
+func cloneBool(value *bool) *bool {
+	if value == nil {
+		return nil
+	}
+	return boolPtr(*value)
+}
+
+// decodingCLIArgs 生成whisper-cli的解码参数（线程数由调用方根据并行度设置）
+func decodingCLIArgs(options models.DecodingOptions) []string {
+	var args []string
+	if options.InitialPrompt != "" {
+		args = append(args, "--prompt", options.InitialPrompt)
+	}
+	if options.BeamSize > 0 {
+		args = append(args, "-bs", strconv.Itoa(options.BeamSize))
+	}
+	if options.BestOf > 0 {
+		args = append(args, "-bo", strconv.Itoa(options.BestOf))
+	}
+	if options.Temperature > 0 {
+		args = append(args, "-tp", formatDecodingNumber(options.Temperature))
+	}
+	if options.TemperatureIncrement > 0 {
+		args = append(args, "-tpi", formatDecodingNumber(options.TemperatureIncrement))
+	}
+	if options.EntropyThreshold > 0 {
+		args = append(args, "-et", formatDecodingNumber(options.EntropyThreshold))
+	}
+	if options.LogprobThreshold < 0 {
+		args = append(args, "-lpt", formatDecodingNumber(options.LogprobThreshold))
+	}
+	if options.MaxSegmentLength > 0 {
+		args = append(args, "-ml", strconv.Itoa(options.MaxSegmentLength))
+	}
+	if options.SplitOnWord {
+		args = append(args, "-sow")
+	}
+	if options.NoSpeechThreshold > 0 {
+		args = append(args, "-nth", formatDecodingNumber(options.NoSpeechThreshold))
+	}
+	if options.NoFallback != nil && *options.NoFallback {
+		args = append(args, "--no-fallback")
+	}
+	return args
+}
+
+// decodingFormFields 生成whisper-server /inference 接口的解码参数
+// 线程数在服务启动时指定，不能按请求设置
+func decodingFormFields(options models.DecodingOptions) map[string]string {
+	fields := make(map[string]string)
+	if options.InitialPrompt != "" {
+		fields["prompt"] = options.InitialPrompt
+	}
+	if options.BeamSize > 0 {
+		fields["beam_size"] = strconv.Itoa(options.BeamSize)
+	}
+	if options.BestOf > 0 {
+		fields["best_of"] = strconv.Itoa(options.BestOf)
+	}
+	if options.Temperature > 0 {
+		fields["temperature"] = formatDecodingNumber(options.Temperature)
+	}
+	if options.TemperatureIncrement > 0 {
+		fields["temperature_inc"] = formatDecodingNumber(options.TemperatureIncrement)
+	}
+	if options.EntropyThreshold > 0 {
+		fields["entropy_thold"] = formatDecodingNumber(options.EntropyThreshold)
+	}
+	if options.LogprobThreshold < 0 {
+		fields["logprob_thold"] = formatDecodingNumber(options.LogprobThreshold)
+	}
+	if options.MaxSegmentLength > 0 {
+		fields["max_len"] = strconv.Itoa(options.MaxSegmentLength)
+	}
+	if options.SplitOnWord {
+		fields["split_on_word"] = "true"
+	}
+	if options.NoSpeechThreshold > 0 {
+		fields["no_speech_thold"] = formatDecodingNumber(options.NoSpeechThreshold)
+	}
+	if options.NoFallback != nil && *options.NoFallback {
+		// 服务端没有no_fallback参数，温度增量为0等同于关闭回退
+		fields["temperature_inc"] = "0"
+	}
+	return fields
+}
+
+// formatDecodingNumber 格式化解码参数中的小数
+func formatDecodingNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,580 @@
+package recognition
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/utils"
+)
+
+// 说话人分离方式
+const (
+	DiarizationOff         = "off"         // 不区分说话人
+	DiarizationAuto        = "auto"        // 自动选择：tdrz模型用tinydiarize，双声道录音用stereo，否则用pause
+	DiarizationTinydiarize = "tinydiarize" // whisper.cpp tinydiarize（-tdrz），需要tdrz模型，由模型标记说话轮次
+	DiarizationStereo      = "stereo"      // 双声道通话录音，每侧声道一个说话人（-di）
+	DiarizationPause       = "pause"       // 按停顿切分说话轮次，再按音量和过零率聚类
+)
+
+// 说话人分离默认参数
+const (
+	defaultMaxSpeakers       = 8
+	defaultSpeakerTurnGap    = 1.0  // 段落间停顿超过该值(秒)时视为可能换人
+	speakerLevelScale        = 4.0  // 聚类距离中音量相差4dB记为1
+	speakerZeroCrossingScale = 0.02 // 聚类距离中过零率相差0.02记为1
+	speakerMergeDistance     = 1.0  // 自动估计人数时，与已有说话人距离都超过该值的轮次才作为新的说话人
+	speakerRefineIterations  = 5    // 聚类迭代次数
+	stereoDominanceRatio     = 1.1  // 一侧声道功率超过另一侧的倍数时判定为该侧说话人（与whisper.cpp一致）
+)
+
+// 识别引擎输出的说话人提示（段落元数据，分配说话人后删除）
+const (
+	speakerChannelKey  = "speaker_channel"   // whisper-cli -di 输出的声道: "0" | "1" | "?"
+	speakerTurnNextKey = "speaker_turn_next" // whisper-cli -tdrz 输出：下一段换人
+)
+
+var (
+	// whisperSpeakerPrefix whisper-cli -di 在控制台和SRT文本前添加的说话人标记: "(speaker 0)"
+	whisperSpeakerPrefix = regexp.MustCompile(`^\s*\(speaker ([01?])\)\s*`)
+)
+
+// whisperSpeakerTurnMark whisper-cli -tdrz 在控制台输出的段落末尾添加的换人标记
+const whisperSpeakerTurnMark = "[SPEAKER_TURN]"
+
+// NormalizeDiarizationMode 校验说话人分离方式，空值视为off
+func NormalizeDiarizationMode(mode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", DiarizationOff:
+		return DiarizationOff, nil
+	case DiarizationAuto:
+		return DiarizationAuto, nil
+	case DiarizationTinydiarize, "tdrz":
+		return DiarizationTinydiarize, nil
+	case DiarizationStereo:
+		return DiarizationStereo, nil
+	case DiarizationPause:
+		return DiarizationPause, nil
+	default:
+		return "", fmt.Errorf("不支持的说话人分离方式: %s（可选 off、auto、tinydiarize、stereo、pause）", mode)
+	}
+}
+
+// ResolveDiarizationConfig 校验说话人分离参数并补全默认值
+func ResolveDiarizationConfig(config models.DiarizationConfig) (models.DiarizationConfig, error) {
+	mode, err := NormalizeDiarizationMode(config.Mode)
+	if err != nil {
+		return config, err
+	}
+	config.Mode = mode
+
+	switch {
+	case config.Speakers < 0:
+		return config, fmt.Errorf("speakers 不能为负数: %d", config.Speakers)
+	case config.MaxSpeakers < 0:
+		return config, fmt.Errorf("maxSpeakers 不能为负数: %d", config.MaxSpeakers)
+	case config.TurnGap < 0:
+		return config, fmt.Errorf("turnGap 不能为负数: %g", config.TurnGap)
+	}
+	if config.MaxSpeakers == 0 {
+		config.MaxSpeakers = defaultMaxSpeakers
+	}
+	if config.Speakers > config.MaxSpeakers {
+		config.MaxSpeakers = config.Speakers
+	}
+	if config.TurnGap == 0 {
+		config.TurnGap = defaultSpeakerTurnGap
+	}
+	return config, nil
+}
+
+// SpeakerLabel 说话人的默认名称（从0开始的序号对应 Speaker 1..N）
+func SpeakerLabel(index int) string {
+	return fmt.Sprintf("Speaker %d", index+1)
+}
+
+// diarizationPlan 根据引擎能力、模型和音频声道数确定的分离方式
+type diarizationPlan struct {
+	requested string // 请求的分离方式
+	method    string // 实际使用的分离方式，off表示不分离
+	modelPath string // 使用的模型文件（tinydiarize可能换用tdrz模型）
+	channels  int    // 转换WAV时保留的声道数
+	warning   string // 无法使用请求的方式时的说明
+}
+
+// enabled 是否需要分离说话人
+func (p diarizationPlan) enabled() bool {
+	return p.method != DiarizationOff
+}
+
+// planDiarization 确定分离方式，无法满足请求时退回pause并记录原因
+// sourceChannels为原始音频的声道数（未知时为0），tinydiarize表示引擎能否输出tdrz换人标记
+func planDiarization(config models.DiarizationConfig, modelPath string, sourceChannels int, tinydiarize bool) diarizationPlan {
+	plan := diarizationPlan{requested: config.Mode, method: config.Mode, modelPath: modelPath, channels: 1}
+
+	switch config.Mode {
+	case DiarizationAuto:
+		switch {
+		case tinydiarize && isTinydiarizeModel(modelPath):
+			plan.method = DiarizationTinydiarize
+		case sourceChannels == 2:
+			plan.method = DiarizationStereo
+		default:
+			plan.method = DiarizationPause
+		}
+	case DiarizationTinydiarize:
+		switch {
+		case !tinydiarize:
+			plan.method = DiarizationPause
+			plan.warning = "当前识别引擎不输出tinydiarize换人标记，已改用pause方式"
+		case !isTinydiarizeModel(modelPath):
+			if tdrzModel := findTinydiarizeModel(filepath.Dir(modelPath)); tdrzModel != "" {
+				plan.modelPath = tdrzModel
+			} else {
+				plan.method = DiarizationPause
+				plan.warning = "模型目录中没有tdrz模型（如ggml-small.en-tdrz.bin），已改用pause方式"
+			}
+		}
+	case DiarizationStereo:
+		if sourceChannels != 2 {
+			plan.method = DiarizationPause
+			plan.warning = fmt.Sprintf("音频不是双声道（声道数: %d），已改用pause方式", sourceChannels)
+		}
+	}
+
+	if plan.method == DiarizationStereo {
+		plan.channels = 2
+	}
+	if plan.warning != "" {
+		utils.LogWarn("说话人分离: %s", plan.warning)
+	}
+	return plan
+}
+
+// probeSourceChannels 获取原始音频的声道数，只有可能使用stereo方式时才探测，失败时返回0
+func probeSourceChannels(ctx context.Context, processor *audio.Processor, audioPath string, mode string, convert audio.ConvertOptions) int {
+	if mode != DiarizationAuto && mode != DiarizationStereo {
+		return 0
+	}
+	audioInfo, err := processor.ProbeAudio(ctx, audioPath)
+	if err != nil {
+		fmt.Printf("⚠️ 探测音频声道数失败: %v\n", err)
+		return 0
+	}
+	// 多音轨文件使用将要识别的音轨的声道数
+	if stream, err := audio.SelectAudioStream(audioInfo, convert.AudioTrack, convert.Language); err == nil && stream < len(audioInfo.AudioStreams) {
+		return audioInfo.AudioStreams[stream].Channels
+	}
+	return audioInfo.Channels
+}
+
+// analyzeVoice 分析WAV文件的声学特征，不分离说话人或分析失败时返回nil
+func analyzeVoice(ctx context.Context, wavPath string, plan diarizationPlan) *audio.VoiceProfile {
+	if !plan.enabled() {
+		return nil
+	}
+	profile, err := audio.AnalyzeVoice(ctx, wavPath)
+	if err != nil {
+		fmt.Printf("⚠️ 分析音频特征失败，说话人分离只使用识别引擎的输出: %v\n", err)
+		return nil
+	}
+	return profile
+}
+
+// isTinydiarizeModel 判断是否为tinydiarize模型（文件名含tdrz）
+func isTinydiarizeModel(modelPath string) bool {
+	return strings.Contains(strings.ToLower(filepath.Base(modelPath)), "tdrz")
+}
+
+// findTinydiarizeModel 在模型目录中查找tdrz模型，未找到时返回空字符串
+func findTinydiarizeModel(modelDir string) string {
+	files, err := os.ReadDir(modelDir)
+	if err != nil {
+		return ""
+	}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".bin") && isTinydiarizeModel(file.Name()) {
+			return filepath.Join(modelDir, file.Name())
+		}
+	}
+	return ""
+}
+
+// stripSpeakerMarks 去掉whisper-cli在控制台和SRT输出中添加的说话人标记，返回文本、声道提示和是否换人
+func stripSpeakerMarks(text string) (string, string, bool) {
+	channel := ""
+	if match := whisperSpeakerPrefix.FindStringSubmatch(text); match != nil {
+		channel = match[1]
+		text = text[len(match[0]):]
+	}
+	trimmed := strings.TrimSpace(text)
+	turn := strings.HasSuffix(trimmed, whisperSpeakerTurnMark)
+	if turn {
+		text = strings.TrimSpace(strings.TrimSuffix(trimmed, whisperSpeakerTurnMark))
+	}
+	return text, channel, turn
+}
+
+// diarizationReport 说话人分离结果，写入识别结果元数据
+type diarizationReport struct {
+	requested string
+	method    string
+	warning   string
+}
+
+// apply 将分离信息、说话人列表和说话人轮次写入识别结果元数据
+func (r *diarizationReport) apply(result *models.RecognitionResult) {
+	if r == nil {
+		return
+	}
+	info := map[string]interface{}{
+		"requested": r.requested,
+		"method":    r.method,
+	}
+	if r.warning != "" {
+		info["warning"] = r.warning
+	}
+	result.Metadata["diarization"] = info
+	refreshSpeakerMetadata(result)
+}
+
+// diarize 为段落和其中的词分配说话人（Speaker 1..N）
+// profile为nil时（无法分析音频）只使用识别引擎输出的提示，pause方式保留段落已有的说话人
+func diarize(segments []models.RecognitionResultSegment, plan diarizationPlan, config models.DiarizationConfig, profile *audio.VoiceProfile) *diarizationReport {
+	if !plan.enabled() {
+		return nil
+	}
+	report := &diarizationReport{requested: plan.requested, method: plan.method, warning: plan.warning}
+
+	var speakerOf []int
+	switch plan.method {
+	case DiarizationStereo:
+		speakerOf = stereoSpeakers(segments, profile)
+	default:
+		turns := speakerTurns(segments, plan.method, config.TurnGap)
+		if profile == nil && plan.method == DiarizationTinydiarize {
+			// 无法提取声学特征时，假定两人交替发言
+			speakerOf = make([]int, len(segments))
+			for i, turn := range turns {
+				for _, index := range turn.segments {
+					speakerOf[index] = i % 2
+				}
+			}
+		} else if profile != nil {
+			speakerOf = clusterTurns(turns, profile, config, len(segments))
+		} else if report.warning == "" {
+			report.warning = "无法分析音频，未区分说话人"
+		}
+	}
+
+	for i := range segments {
+		delete(segments[i].Metadata, speakerChannelKey)
+		delete(segments[i].Metadata, speakerTurnNextKey)
+		if speakerOf == nil {
+			continue
+		}
+		speaker := SpeakerLabel(speakerOf[i])
+		segments[i].Speaker = speaker
+		for j := range segments[i].Words {
+			segments[i].Words[j].Speaker = speaker
+		}
+	}
+
+	fmt.Printf("🗣️ 说话人分离完成: 方式=%s, 段落数=%d\n", plan.method, len(segments))
+	return report
+}
+
+// stereoSpeakers 按声道为段落分配说话人：左声道为Speaker 1，右声道为Speaker 2
+// 优先使用whisper-cli -di的输出，没有时比较两侧声道功率；无法判断的段落沿用相邻段落的说话人
+func stereoSpeakers(segments []models.RecognitionResultSegment, profile *audio.VoiceProfile) []int {
+	speakerOf := make([]int, len(segments))
+	for i, segment := range segments {
+		channel, _ := segment.Metadata[speakerChannelKey].(string)
+		if channel == "" && profile != nil && profile.Channels() == 2 {
+			channel = dominantChannel(profile.ChannelPower(segment.Start, segment.End))
+		}
+		switch channel {
+		case "0":
+			speakerOf[i] = 0
+		case "1":
+			speakerOf[i] = 1
+		default:
+			speakerOf[i] = -1
+		}
+	}
+
+	fillUnknownSpeakers(speakerOf)
+	return speakerOf
+}
+
+// dominantChannel 判断功率占优的声道，两侧接近时返回"?"
+func dominantChannel(power []float64) string {
+	switch {
+	case power[0] > stereoDominanceRatio*power[1]:
+		return "0"
+	case power[1] > stereoDominanceRatio*power[0]:
+		return "1"
+	default:
+		return "?"
+	}
+}
+
+// fillUnknownSpeakers 未知说话人(-1)沿用前一段的说话人，开头的沿用之后第一个已知说话人，全部未知时为0
+func fillUnknownSpeakers(speakerOf []int) {
+	previous := -1
+	for i, speaker := range speakerOf {
+		if speaker >= 0 {
+			previous = speaker
+			continue
+		}
+		speakerOf[i] = previous
+	}
+	next := 0
+	for i := len(speakerOf) - 1; i >= 0; i-- {
+		if speakerOf[i] >= 0 {
+			next = speakerOf[i]
+			continue
+		}
+		speakerOf[i] = next
+	}
+}
+
+// speakerTurn 同一说话人连续发言的若干段落
+type speakerTurn struct {
+	segments     []int
+	start        float64
+	end          float64
+	level        float64 // 语音帧平均能量(dBFS)
+	zeroCrossing float64 // 语音帧平均过零率
+	voiced       float64 // 语音时长(秒)，为0时没有可用特征
+}
+
+// speakerTurns 切分说话轮次：tinydiarize按模型输出的换人标记，pause按段落间的停顿
+func speakerTurns(segments []models.RecognitionResultSegment, method string, turnGap float64) []speakerTurn {
+	var turns []speakerTurn
+	newTurn := true
+	for i, segment := range segments {
+		if i > 0 && method != DiarizationTinydiarize && segment.Start-segments[i-1].End >= turnGap {
+			newTurn = true
+		}
+		if newTurn || len(turns) == 0 {
+			turns = append(turns, speakerTurn{start: segment.Start})
+			newTurn = false
+		}
+		turn := &turns[len(turns)-1]
+		turn.segments = append(turn.segments, i)
+		turn.end = segment.End
+
+		if method == DiarizationTinydiarize {
+			newTurn, _ = segment.Metadata[speakerTurnNextKey].(bool)
+		}
+	}
+	return turns
+}
+
+// speakerCluster 聚类中的一个说话人
+type speakerCluster struct {
+	level        float64
+	zeroCrossing float64
+	weight       float64
+}
+
+// distance 说话轮次与说话人的特征距离
+func (c speakerCluster) distance(turn speakerTurn) float64 {
+	return math.Hypot((turn.level-c.level)/speakerLevelScale, (turn.zeroCrossing-c.zeroCrossing)/speakerZeroCrossingScale)
+}
+
+// clusterTurns 按音量和过零率聚类说话轮次（不依赖声纹模型），返回每个段落的说话人序号
+// 指定说话人数时聚为该数量；否则依次以离已有说话人最远的轮次作为新说话人，直到距离不超过阈值
+// 说话人序号按首次发言的先后排列
+func clusterTurns(turns []speakerTurn, profile *audio.VoiceProfile, config models.DiarizationConfig, segmentCount int) []int {
+	var featured []int
+	for i := range turns {
+		turns[i].level, turns[i].zeroCrossing, turns[i].voiced = profile.VoiceFeatures(turns[i].start, turns[i].end)
+		if turns[i].voiced > 0 {
+			featured = append(featured, i)
+		}
+	}
+
+	clusterOf := make([]int, len(turns))
+	for i := range clusterOf {
+		clusterOf[i] = -1
+	}
+
+	if len(featured) > 0 {
+		clusters := seedSpeakerClusters(turns, featured, config)
+		for iteration := 0; iteration < speakerRefineIterations; iteration++ {
+			changed := false
+			for _, i := range featured {
+				nearest := nearestCluster(clusters, turns[i])
+				if nearest != clusterOf[i] {
+					clusterOf[i] = nearest
+					changed = true
+				}
+			}
+			if !changed {
+				break
+			}
+			clusters = recomputeClusters(turns, featured, clusterOf, len(clusters))
+		}
+	}
+
+	// 没有语音特征的轮次沿用相邻轮次的说话人
+	fillUnknownSpeakers(clusterOf)
+
+	// 按首次发言的先后重新编号
+	order := make(map[int]int)
+	speakerOf := make([]int, segmentCount)
+	for i, turn := range turns {
+		speaker, ok := order[clusterOf[i]]
+		if !ok {
+			speaker = len(order)
+			order[clusterOf[i]] = speaker
+		}
+		for _, index := range turn.segments {
+			speakerOf[index] = speaker
+		}
+	}
+	return speakerOf
+}
+
+// seedSpeakerClusters 选取初始说话人：第一个为语音最长的轮次，之后每次选离已有说话人最远的轮次
+func seedSpeakerClusters(turns []speakerTurn, featured []int, config models.DiarizationConfig) []speakerCluster {
+	byDuration := make([]int, len(featured))
+	copy(byDuration, featured)
+	sort.SliceStable(byDuration, func(a, b int) bool { return turns[byDuration[a]].voiced > turns[byDuration[b]].voiced })
+
+	first := turns[byDuration[0]]
+	clusters := []speakerCluster{{level: first.level, zeroCrossing: first.zeroCrossing, weight: first.voiced}}
+
+	limit := config.MaxSpeakers
+	if config.Speakers > 0 {
+		limit = config.Speakers
+	}
+	for len(clusters) < limit {
+		farthest, farthestDistance := -1, 0.0
+		for _, i := range byDuration {
+			distance := clusters[nearestCluster(clusters, turns[i])].distance(turns[i])
+			if distance > farthestDistance {
+				farthest, farthestDistance = i, distance
+			}
+		}
+		if farthest < 0 || (config.Speakers == 0 && farthestDistance <= speakerMergeDistance) {
+			break
+		}
+		turn := turns[farthest]
+		clusters = append(clusters, speakerCluster{level: turn.level, zeroCrossing: turn.zeroCrossing, weight: turn.voiced})
+	}
+	return clusters
+}
+
+// nearestCluster 距离说话轮次最近的说话人
+func nearestCluster(clusters []speakerCluster, turn speakerTurn) int {
+	nearest, nearestDistance := 0, math.Inf(1)
+	for i, cluster := range clusters {
+		if cluster.weight == 0 {
+			continue
+		}
+		if distance := cluster.distance(turn); distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	return nearest
+}
+
+// recomputeClusters 按语音时长加权重新计算说话人特征，没有轮次的说话人权重为0（不再参与分配）
+func recomputeClusters(turns []speakerTurn, featured []int, clusterOf []int, count int) []speakerCluster {
+	clusters := make([]speakerCluster, count)
+	for _, i := range featured {
+		cluster := &clusters[clusterOf[i]]
+		cluster.level += turns[i].level * turns[i].voiced
+		cluster.zeroCrossing += turns[i].zeroCrossing * turns[i].voiced
+		cluster.weight += turns[i].voiced
+	}
+	for i := range clusters {
+		if clusters[i].weight > 0 {
+			clusters[i].level /= clusters[i].weight
+			clusters[i].zeroCrossing /= clusters[i].weight
+		}
+	}
+	return clusters
+}
+
+// refreshSpeakerMetadata 根据段落的说话人更新结果元数据中的说话人列表（按首次发言排列）和说话人轮次标记
+func refreshSpeakerMetadata(result *models.RecognitionResult) {
+	speakers := []string{}
+	seen := make(map[string]bool)
+	formatter := utils.NewSpecialMarkFormatter()
+
+	turnStart := -1
+	for i, segment := range result.Segments {
+		if segment.Speaker == "" {
+			continue
+		}
+		if !seen[segment.Speaker] {
+			seen[segment.Speaker] = true
+			speakers = append(speakers, segment.Speaker)
+		}
+		if turnStart < 0 {
+			turnStart = i
+		}
+		last := i == len(result.Segments)-1 || result.Segments[i+1].Speaker != segment.Speaker
+		if last {
+			formatter.AddSpeakerMark(result.Segments[turnStart].Start, segment.End, segment.Speaker)
+			turnStart = -1
+		}
+	}
+
+	if len(speakers) == 0 {
+		delete(result.Metadata, "speakers")
+		delete(result.Metadata, "speaker_turns")
+		return
+	}
+	result.Metadata["speakers"] = speakers
+	result.Metadata["speaker_turns"] = formatter.GetMarks()
+}
+
+// RenameSpeaker 重命名识别结果中的说话人（段落、词汇和元数据），新名称已存在时两个说话人合并
+// 返回修改的段落数
+func RenameSpeaker(result *models.RecognitionResult, speaker, name string) (int, error) {
+	speaker = strings.TrimSpace(speaker)
+	name = strings.TrimSpace(name)
+	if speaker == "" || name == "" {
+		return 0, fmt.Errorf("说话人名称不能为空")
+	}
+
+	renamed := 0
+	for i := range result.Segments {
+		segment := &result.Segments[i]
+		if segment.Speaker == speaker {
+			segment.Speaker = name
+			renamed++
+		}
+		for j := range segment.Words {
+			if segment.Words[j].Speaker == speaker {
+				segment.Words[j].Speaker = name
+			}
+		}
+	}
+	if renamed == 0 {
+		return 0, fmt.Errorf("识别结果中没有说话人: %s", speaker)
+	}
+	for i := range result.Words {
+		if result.Words[i].Speaker == speaker {
+			result.Words[i].Speaker = name
+		}
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	refreshSpeakerMetadata(result)
+	return renamed, nil
+}
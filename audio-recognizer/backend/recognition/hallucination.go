@@ -0,0 +1,375 @@
+package recognition
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+)
+
+// 幻觉检测原因
+const (
+	HallucinationRepeatedSegment = "repeated_segment"  // 与上一段内容重复（已合并）
+	HallucinationSilent          = "silent"            // 段落所在音频接近静音
+	HallucinationKnownPhrase     = "known_phrase"      // 常见的幻觉短语
+	HallucinationRepetitionLoop  = "repetition_loop"   // 段落内部同一片段连续重复
+	HallucinationCompression     = "compression_ratio" // 文本压缩比异常（重复度过高）
+)
+
+// 幻觉检测默认参数
+const (
+	defaultCompressionRatioThreshold = 2.4 // 与Whisper温度回退使用的阈值一致
+	defaultSilenceMarginDB           = 6.0
+	noSpeechProbThreshold            = 0.6 // whisper-server返回的无语音概率阈值
+	noSpeechConfidenceThreshold      = 0.5 // 无语音概率高且置信度低于该值时视为静音段
+	repeatedNgramSize                = 3   // 比较相邻段落时使用的n-gram长度
+	repeatedNgramOverlap             = 0.8 // 当前段落的n-gram有多大比例出现在上一段时视为重复
+)
+
+// knownHallucinationPhrases 常见的Whisper幻觉短语（按Whisper语言代码），多出现在静音、音乐或片尾
+var knownHallucinationPhrases = map[string][]string{
+	"zh": {
+		"谢谢观看",
+		"感谢观看",
+		"谢谢收看",
+		"请不吝点赞订阅转发打赏支持明镜与点点栏目",
+		"字幕由Amara.org社区提供",
+		"中文字幕志愿者",
+		"优优独播剧场",
+		"请订阅我的频道",
+		"点赞订阅",
+	},
+	"yue": {
+		"多谢收睇",
+		"字幕由Amara.org社区提供",
+	},
+	"en": {
+		"Thank you for watching",
+		"Thanks for watching",
+		"Please subscribe",
+		"Subtitles by the Amara.org community",
+		"Like and subscribe",
+	},
+	"ja": {
+		"ご視聴ありがとうございました",
+		"チャンネル登録お願いします",
+	},
+	"ko": {
+		"시청해주셔서 감사합니다",
+		"구독과 좋아요",
+	},
+}
+
+// removedSegment 被删除或合并的段落记录，保留完整的段落（含词汇时间），便于用户恢复
+type removedSegment struct {
+	models.RecognitionResultSegment
+	Reason string `json:"reason"`
+}
+
+// hallucinationReport 幻觉检测结果统计
+type hallucinationReport struct {
+	dropped int
+	merged  int
+	flagged int
+	removed []removedSegment
+}
+
+// apply 将检测统计写入识别结果元数据
+func (r *hallucinationReport) apply(result *models.RecognitionResult) {
+	if r == nil {
+		return
+	}
+	result.Metadata["hallucination_filter"] = map[string]interface{}{
+		"dropped": r.dropped,
+		"merged":  r.merged,
+		"flagged": r.flagged,
+	}
+	if len(r.removed) > 0 {
+		result.Metadata["filtered_segments"] = r.removed
+	}
+}
+
+// applyHallucinationFilter 对识别段落执行幻觉与重复检测，wavPath用于判断段落是否处于静音
+// translated为true时段落为英文译文，按英文幻觉短语检测
+func (s *WhisperService) applyHallucinationFilter(ctx context.Context, config models.HallucinationFilterConfig, run *whisperRun, wavPath, whisperLang string, translated bool) *hallucinationReport {
+	if config.Disabled || len(run.segments) == 0 {
+		return nil
+	}
+
+	profile, err := audio.AnalyzeEnergy(ctx, wavPath)
+	if err != nil {
+		fmt.Printf("⚠️ 分析音频能量失败，跳过静音检测: %v\n", err)
+		profile = nil
+	}
+
+	language := run.detected
+	if language == "" {
+		language = whisperLang
+	}
+	if translated {
+		language = translationLanguage
+	}
+
+	segments, report := filterHallucinations(run.segments, language, profile, config)
+	run.segments = segments
+	if report.dropped+report.merged+report.flagged > 0 {
+		fmt.Printf("🧹 幻觉检测: 删除 %d 段, 合并 %d 段, 标记 %d 段\n", report.dropped, report.merged, report.flagged)
+	}
+	return report
+}
+
+// filterHallucinations 检测重复、静音、幻觉短语和压缩比异常的段落
+// 默认只合并重复段，其余问题只标记；DropSilent、DropKnownPhrases时删除对应段落；FlagOnly时全部只标记
+// 被删除或合并的段落完整记录在报告中。profile为nil时不做能量静音检测
+func filterHallucinations(segments []models.RecognitionResultSegment, language string, profile *audio.EnergyProfile, config models.HallucinationFilterConfig) ([]models.RecognitionResultSegment, *hallucinationReport) {
+	ratioThreshold := config.CompressionRatioThreshold
+	if ratioThreshold <= 0 {
+		ratioThreshold = defaultCompressionRatioThreshold
+	}
+	silenceMargin := config.SilenceMarginDB
+	if silenceMargin <= 0 {
+		silenceMargin = defaultSilenceMarginDB
+	}
+	phrases := hallucinationPhrases(language, config.Phrases)
+
+	report := &hallucinationReport{}
+	kept := make([]models.RecognitionResultSegment, 0, len(segments))
+
+	remove := func(segment models.RecognitionResultSegment, reason string) {
+		report.removed = append(report.removed, removedSegment{
+			RecognitionResultSegment: segment,
+			Reason:                   reason,
+		})
+	}
+
+	for _, segment := range segments {
+		if segment.Metadata == nil {
+			segment.Metadata = make(map[string]interface{})
+		}
+		normalized := normalizeSegmentText(segment.Text)
+		var reasons []string
+
+		// 与上一段重复：合并到上一段
+		if n := len(kept); n > 0 && isRepeatedSegment(kept[n-1].Text, segment.Text) {
+			if !config.FlagOnly {
+				previous := &kept[n-1]
+				if segment.End > previous.End {
+					previous.End = segment.End
+				}
+				repeats, _ := previous.Metadata["merged_repeats"].(int)
+				previous.Metadata["merged_repeats"] = repeats + 1
+				addHallucinationReason(previous, HallucinationRepeatedSegment)
+				remove(segment, HallucinationRepeatedSegment)
+				report.merged++
+				continue
+			}
+			reasons = append(reasons, HallucinationRepeatedSegment)
+		}
+
+		// 静音段：音频能量接近噪声基底，或服务端判定无语音且置信度低
+		silent := false
+		if profile != nil && profile.PeakLevel(segment.Start, segment.End) < profile.NoiseFloor()+silenceMargin {
+			silent = true
+		}
+		if prob, ok := segment.Metadata["no_speech_prob"].(float64); ok && prob > noSpeechProbThreshold && segment.Confidence < noSpeechConfidenceThreshold {
+			silent = true
+		}
+		// 轻声说话或有背景音乐时能量判断可能出错，默认只标记
+		if silent {
+			if config.DropSilent && !config.FlagOnly {
+				remove(segment, HallucinationSilent)
+				report.dropped++
+				continue
+			}
+			reasons = append(reasons, HallucinationSilent)
+		}
+
+		// 幻觉短语：也可能是真实内容（如视频结尾确实说了"谢谢观看"），默认只标记
+		if matchesHallucinationPhrase(normalized, phrases) {
+			if config.DropKnownPhrases && !config.FlagOnly {
+				remove(segment, HallucinationKnownPhrase)
+				report.dropped++
+				continue
+			}
+			reasons = append(reasons, HallucinationKnownPhrase)
+		}
+
+		if hasRepetitionLoop(segment.Text) {
+			reasons = append(reasons, HallucinationRepetitionLoop)
+		}
+
+		if ratio := compressionRatio(segment.Text); ratio > ratioThreshold {
+			segment.Metadata["compression_ratio"] = ratio
+			reasons = append(reasons, HallucinationCompression)
+		}
+
+		if len(reasons) > 0 {
+			for _, reason := range reasons {
+				addHallucinationReason(&segment, reason)
+			}
+			report.flagged++
+		}
+		kept = append(kept, segment)
+	}
+
+	return kept, report
+}
+
+// addHallucinationReason 在段落元数据中记录检测原因（去重）
+func addHallucinationReason(segment *models.RecognitionResultSegment, reason string) {
+	reasons, _ := segment.Metadata["hallucination_reasons"].([]string)
+	for _, existing := range reasons {
+		if existing == reason {
+			return
+		}
+	}
+	segment.Metadata["hallucination_reasons"] = append(reasons, reason)
+}
+
+// hallucinationPhrases 合并内置和配置中的幻觉短语，并统一规范化
+func hallucinationPhrases(language string, extra map[string][]string) []string {
+	var phrases []string
+	for _, phrase := range knownHallucinationPhrases[language] {
+		phrases = append(phrases, normalizeSegmentText(phrase))
+	}
+	for _, phrase := range extra[language] {
+		phrases = append(phrases, normalizeSegmentText(phrase))
+	}
+	// 用户配置的通用短语
+	for _, phrase := range extra["*"] {
+		phrases = append(phrases, normalizeSegmentText(phrase))
+	}
+	return phrases
+}
+
+// matchesHallucinationPhrase 段落内容是否基本只有幻觉短语（允许少量额外字符）
+func matchesHallucinationPhrase(normalized string, phrases []string) bool {
+	if normalized == "" {
+		return false
+	}
+	length := len([]rune(normalized))
+	for _, phrase := range phrases {
+		if phrase == "" || !strings.Contains(normalized, phrase) {
+			continue
+		}
+		if length-len([]rune(phrase)) <= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// isRepeatedSegment 判断当前段落是否重复上一段的内容（n-gram大部分出现在上一段中）
+func isRepeatedSegment(previous, current string) bool {
+	a, b := repetitionTokens(previous), repetitionTokens(current)
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	if strings.Join(a, "") == strings.Join(b, "") {
+		return true
+	}
+	if len(b) < repeatedNgramSize {
+		return false
+	}
+
+	seen := make(map[string]bool)
+	for _, gram := range ngrams(a, repeatedNgramSize) {
+		seen[gram] = true
+	}
+	grams := ngrams(b, repeatedNgramSize)
+	matched := 0
+	for _, gram := range grams {
+		if seen[gram] {
+			matched++
+		}
+	}
+	return float64(matched)/float64(len(grams)) >= repeatedNgramOverlap
+}
+
+// hasRepetitionLoop 检测段落内部同一片段连续重复（如"我们我们我们我们"）
+// 单字连续重复5次以上、或2~8个词的片段连续重复3次以上视为循环
+func hasRepetitionLoop(text string) bool {
+	tokens := repetitionTokens(text)
+	for size := 1; size <= 8; size++ {
+		threshold := 3
+		if size == 1 {
+			threshold = 5
+		}
+		for start := 0; start+size*threshold <= len(tokens); start++ {
+			repeats := 1
+			for next := start + size; next+size <= len(tokens) && equalTokens(tokens[start:start+size], tokens[next:next+size]); next += size {
+				repeats++
+			}
+			if repeats >= threshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// repetitionTokens 切分用于重复检测的词：中日韩文字逐字，其余按空白分词，忽略标点并转小写
+func repetitionTokens(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsSpace(r) || unicode.IsPunct(r):
+			flush()
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// ngrams 生成n-gram列表
+func ngrams(tokens []string, n int) []string {
+	if len(tokens) < n {
+		return []string{strings.Join(tokens, " ")}
+	}
+	grams := make([]string, 0, len(tokens)-n+1)
+	for i := 0; i+n <= len(tokens); i++ {
+		grams = append(grams, strings.Join(tokens[i:i+n], " "))
+	}
+	return grams
+}
+
+// equalTokens 比较两个词序列
+func equalTokens(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compressionRatio 文本长度与zlib压缩后长度之比，重复度越高比值越大
+func compressionRatio(text string) float64 {
+	raw := []byte(text)
+	if len(raw) == 0 {
+		return 0
+	}
+	var buf bytes.Buffer
+	writer := zlib.NewWriter(&buf)
+	writer.Write(raw)
+	writer.Close()
+	return float64(len(raw)) / float64(buf.Len())
+}
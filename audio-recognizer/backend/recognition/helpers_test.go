@@ -0,0 +1,145 @@
+package recognition
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tingshengbianzi/backend/models"
+)
+
+// TestMain 设置环境变量 FAKE_WHISPER_SERVER=1 时测试程序作为whisper-server替身运行
+func TestMain(m *testing.M) {
+	if os.Getenv("FAKE_WHISPER_SERVER") == "1" {
+		runFakeWhisperServer(os.Args[1:])
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeWhisperServer whisper-server替身：/inference 对英文请求解码3秒、其他语言0.5秒后返回一个段落
+// 与真实服务一样，客户端断开后仍继续解码
+func runFakeWhisperServer(args []string) {
+	port := ""
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "--port" {
+			port = args[i+1]
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/inference", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		language := r.FormValue("language")
+		if language == "en" {
+			time.Sleep(3 * time.Second)
+		} else {
+			time.Sleep(500 * time.Millisecond)
+		}
+		json.NewEncoder(w).Encode(whisperServerResponse{
+			Language: language,
+			Text:     "你好",
+			Segments: []whisperServerSegment{{
+				Text:  "你好",
+				Start: 0,
+				End:   1,
+				Words: []whisperServerWord{{Word: "你好", Start: 0, End: 1, Probability: 0.9}},
+			}},
+		})
+	})
+	http.ListenAndServe(whisperServerHost+":"+port, mux)
+}
+
+// fakeFFprobeOutput 替身ffprobe输出的3秒16kHz单声道WAV信息
+const fakeFFprobeOutput = `{"streams":[{"index":0,"codec_type":"audio","codec_name":"pcm_s16le","sample_rate":"16000","channels":1,"duration":"3.000000"}],"format":{"format_name":"wav","duration":"3.000000","nb_streams":1}}`
+
+// installFakeFFmpeg 在PATH最前面放置替身ffmpeg和ffprobe：ffmpeg把预先生成的WAV复制到输出路径，ffprobe输出固定的音频信息
+func installFakeFFmpeg(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	wavPath := filepath.Join(dir, "source.wav")
+	writeTestWAV(t, wavPath, 3*16000)
+
+	ffmpeg := "#!/bin/sh\n" +
+		"[ \"$1\" = \"-version\" ] && exit 0\n" +
+		"for last; do :; done\n" +
+		"cp '" + wavPath + "' \"$last\"\n"
+	ffprobe := "#!/bin/sh\n" +
+		"[ \"$1\" = \"-version\" ] && exit 0\n" +
+		"echo '" + fakeFFprobeOutput + "'\n"
+	for name, script := range map[string]string{"ffmpeg": ffmpeg, "ffprobe": ffprobe} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// writeTestWAV 生成16kHz单声道16位WAV（440Hz正弦波）
+func writeTestWAV(t *testing.T, path string, samples int) {
+	t.Helper()
+	data := make([]byte, 44+samples*2)
+	copy(data[0:], "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(36+samples*2))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 1)
+	binary.LittleEndian.PutUint16(data[22:], 1)
+	binary.LittleEndian.PutUint32(data[24:], 16000)
+	binary.LittleEndian.PutUint32(data[28:], 32000)
+	binary.LittleEndian.PutUint16(data[32:], 2)
+	binary.LittleEndian.PutUint16(data[34:], 16)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], uint32(samples*2))
+	for i := 0; i < samples; i++ {
+		sample := int16(8000 * math.Sin(2*math.Pi*440*float64(i)/16000))
+		binary.LittleEndian.PutUint16(data[44+i*2:], uint16(sample))
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// buildFakeWhisperCLI 编译tests/fakewhisper替身程序
+func buildFakeWhisperCLI(t *testing.T) string {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "whisper-cli")
+	cmd := exec.Command("go", "build", "-o", output, "tingshengbianzi/tests/fakewhisper")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("编译fakewhisper失败: %v\n%s", err, out)
+	}
+	return output
+}
+
+// writeTestModels 在临时目录中创建空的模型文件，返回模型目录
+func writeTestModels(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("model"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// testConfig 测试用的识别配置：关闭分块，模型目录为modelDir
+func testConfig(modelDir string) *models.RecognitionConfig {
+	return &models.RecognitionConfig{
+		Language:        "zh-CN",
+		ModelPath:       modelDir,
+		SampleRate:      16000,
+		DisableChunking: true,
+	}
+}
@@ -0,0 +1,162 @@
+package recognition
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LanguageAuto 自动检测语言
+const LanguageAuto = "auto"
+
+// whisperDetectedLanguagePattern 匹配自动检测结果: "auto-detected language: zh (p = 0.973291)"
+var whisperDetectedLanguagePattern = regexp.MustCompile(`auto-detected language:\s*([a-z]{2,3})\s*\(p\s*=\s*([0-9.]+)\)`)
+
+// WhisperLanguage 语言代码与Whisper语言代码的对应关系
+type WhisperLanguage struct {
+	Code        string `json:"code"`        // BCP-47语言代码
+	WhisperCode string `json:"whisperCode"` // Whisper语言代码
+	Name        string `json:"name"`        // 显示名称
+}
+
+// whisperLanguageTable 语言映射表，同一Whisper代码的第一项作为检测结果的默认BCP-47代码
+var whisperLanguageTable = []WhisperLanguage{
+	{Code: LanguageAuto, WhisperCode: LanguageAuto, Name: "自动检测"},
+
+	// 中文
+	{Code: "zh-CN", WhisperCode: "zh", Name: "中文（普通话）"},
+	{Code: "zh-TW", WhisperCode: "zh", Name: "中文（台湾）"},
+	{Code: "zh-SG", WhisperCode: "zh", Name: "中文（新加坡）"},
+	{Code: "zh", WhisperCode: "zh", Name: "中文"},
+	{Code: "yue", WhisperCode: "yue", Name: "粤语"},
+	{Code: "yue-HK", WhisperCode: "yue", Name: "粤语（香港）"},
+	{Code: "zh-HK", WhisperCode: "yue", Name: "粤语（香港）"}, // 香港语音识别场景通常为粤语
+	{Code: "zh-yue", WhisperCode: "yue", Name: "粤语"},
+
+	// 常用语言
+	{Code: "en-US", WhisperCode: "en", Name: "English (US)"},
+	{Code: "en-GB", WhisperCode: "en", Name: "English (UK)"},
+	{Code: "en", WhisperCode: "en", Name: "English"},
+	{Code: "ja-JP", WhisperCode: "ja", Name: "日本語"},
+	{Code: "ja", WhisperCode: "ja", Name: "日本語"},
+	{Code: "ko-KR", WhisperCode: "ko", Name: "한국어"},
+	{Code: "ko", WhisperCode: "ko", Name: "한국어"},
+	{Code: "fr-FR", WhisperCode: "fr", Name: "Français"},
+	{Code: "fr", WhisperCode: "fr", Name: "Français"},
+	{Code: "de-DE", WhisperCode: "de", Name: "Deutsch"},
+	{Code: "de", WhisperCode: "de", Name: "Deutsch"},
+	{Code: "es-ES", WhisperCode: "es", Name: "Español"},
+	{Code: "es", WhisperCode: "es", Name: "Español"},
+	{Code: "it-IT", WhisperCode: "it", Name: "Italiano"},
+	{Code: "it", WhisperCode: "it", Name: "Italiano"},
+	{Code: "pt-BR", WhisperCode: "pt", Name: "Português (Brasil)"},
+	{Code: "pt-PT", WhisperCode: "pt", Name: "Português"},
+	{Code: "pt", WhisperCode: "pt", Name: "Português"},
+	{Code: "ru-RU", WhisperCode: "ru", Name: "Русский"},
+	{Code: "ru", WhisperCode: "ru", Name: "Русский"},
+	{Code: "ar-SA", WhisperCode: "ar", Name: "العربية"},
+	{Code: "ar", WhisperCode: "ar", Name: "العربية"},
+	{Code: "hi-IN", WhisperCode: "hi", Name: "हिन्दी"},
+	{Code: "hi", WhisperCode: "hi", Name: "हिन्दी"},
+	{Code: "th-TH", WhisperCode: "th", Name: "ไทย"},
+	{Code: "vi-VN", WhisperCode: "vi", Name: "Tiếng Việt"},
+	{Code: "id-ID", WhisperCode: "id", Name: "Bahasa Indonesia"},
+	{Code: "ms-MY", WhisperCode: "ms", Name: "Bahasa Melayu"},
+	{Code: "tr-TR", WhisperCode: "tr", Name: "Türkçe"},
+	{Code: "nl-NL", WhisperCode: "nl", Name: "Nederlands"},
+	{Code: "pl-PL", WhisperCode: "pl", Name: "Polski"},
+	{Code: "uk-UA", WhisperCode: "uk", Name: "Українська"},
+	{Code: "sv-SE", WhisperCode: "sv", Name: "Svenska"},
+}
+
+// whisperLanguageNames Whisper支持的全部语言代码及其英文全称（whisper-server返回全称）
+var whisperLanguageNames = map[string]string{
+	"en": "english", "zh": "chinese", "de": "german", "es": "spanish", "ru": "russian",
+	"ko": "korean", "fr": "french", "ja": "japanese", "pt": "portuguese", "tr": "turkish",
+	"pl": "polish", "ca": "catalan", "nl": "dutch", "ar": "arabic", "sv": "swedish",
+	"it": "italian", "id": "indonesian", "hi": "hindi", "fi": "finnish", "vi": "vietnamese",
+	"he": "hebrew", "uk": "ukrainian", "el": "greek", "ms": "malay", "cs": "czech",
+	"ro": "romanian", "da": "danish", "hu": "hungarian", "ta": "tamil", "no": "norwegian",
+	"th": "thai", "ur": "urdu", "hr": "croatian", "bg": "bulgarian", "lt": "lithuanian",
+	"la": "latin", "mi": "maori", "ml": "malayalam", "cy": "welsh", "sk": "slovak",
+	"te": "telugu", "fa": "persian", "lv": "latvian", "bn": "bengali", "sr": "serbian",
+	"az": "azerbaijani", "sl": "slovenian", "kn": "kannada", "et": "estonian", "mk": "macedonian",
+	"br": "breton", "eu": "basque", "is": "icelandic", "hy": "armenian", "ne": "nepali",
+	"mn": "mongolian", "bs": "bosnian", "kk": "kazakh", "sq": "albanian", "sw": "swahili",
+	"gl": "galician", "mr": "marathi", "pa": "punjabi", "si": "sinhala", "km": "khmer",
+	"sn": "shona", "yo": "yoruba", "so": "somali", "af": "afrikaans", "oc": "occitan",
+	"ka": "georgian", "be": "belarusian", "tg": "tajik", "sd": "sindhi", "gu": "gujarati",
+	"am": "amharic", "yi": "yiddish", "lo": "lao", "uz": "uzbek", "fo": "faroese",
+	"ht": "haitian creole", "ps": "pashto", "tk": "turkmen", "nn": "nynorsk", "mt": "maltese",
+	"sa": "sanskrit", "lb": "luxembourgish", "my": "myanmar", "bo": "tibetan", "tl": "tagalog",
+	"mg": "malagasy", "as": "assamese", "tt": "tatar", "haw": "hawaiian", "ln": "lingala",
+	"ha": "hausa", "ba": "bashkir", "jw": "javanese", "su": "sundanese", "yue": "cantonese",
+}
+
+// MapLanguageToWhisper 将BCP-47语言代码映射为Whisper语言代码
+// 依次尝试：完整代码、Whisper原生代码、主语言子标签；无法识别时返回auto
+func MapLanguageToWhisper(language string) (string, bool) {
+	code := strings.TrimSpace(language)
+	if code == "" || strings.EqualFold(code, LanguageAuto) {
+		return LanguageAuto, true
+	}
+
+	for _, lang := range whisperLanguageTable {
+		if strings.EqualFold(lang.Code, code) {
+			return lang.WhisperCode, true
+		}
+	}
+
+	lower := strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+	if _, ok := whisperLanguageNames[lower]; ok {
+		return lower, true
+	}
+
+	primary := strings.SplitN(lower, "-", 2)[0]
+	if _, ok := whisperLanguageNames[primary]; ok {
+		return primary, true
+	}
+
+	return LanguageAuto, false
+}
+
+// MapWhisperToLanguage 将Whisper检测出的语言代码映射回BCP-47语言代码
+func MapWhisperToLanguage(whisperCode string) string {
+	for _, lang := range whisperLanguageTable {
+		if lang.WhisperCode == whisperCode && lang.Code != LanguageAuto {
+			return lang.Code
+		}
+	}
+	return whisperCode
+}
+
+// normalizeWhisperLanguage 将Whisper返回的语言（代码或英文全称）统一为语言代码
+func normalizeWhisperLanguage(language string) string {
+	lower := strings.ToLower(strings.TrimSpace(language))
+	if _, ok := whisperLanguageNames[lower]; ok {
+		return lower
+	}
+	for code, name := range whisperLanguageNames {
+		if name == lower {
+			return code
+		}
+	}
+	return lower
+}
+
+// GetLanguageTable 获取语言映射表（供前端展示语言列表）
+func GetLanguageTable() []WhisperLanguage {
+	table := make([]WhisperLanguage, len(whisperLanguageTable))
+	copy(table, whisperLanguageTable)
+	return table
+}
+
+// parseDetectedLanguage 从whisper-cli输出行中解析自动检测的语言及其概率
+func parseDetectedLanguage(line string) (string, float64, bool) {
+	match := whisperDetectedLanguagePattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", 0, false
+	}
+	probability, _ := strconv.ParseFloat(match[2], 64)
+	return match[1], probability, true
+}
@@ -0,0 +1,68 @@
+package recognition
+
+import (
+	"fmt"
+
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+)
+
+// jobOptions 校验并展开后的单次识别选项
+type jobOptions struct {
+	config      *models.RecognitionConfig // 识别开始时的配置，识别期间更新配置不影响本次识别
+	task        string
+	decoding    models.DecodingOptions
+	vocabulary  *vocabularyCorrector // 词汇纠正，未使用词汇表时为nil
+	promptTerms int                  // 写入初始提示词的术语数
+	diarization models.DiarizationConfig
+	audioTrack  *int               // 指定的音轨，nil时自动选择
+	timeRanges  []models.TimeRange // 只识别的时间范围，为空时识别整个文件
+}
+
+// resolveJobOptions 校验识别选项，未指定的选项使用配置中的值
+func resolveJobOptions(config *models.RecognitionConfig, options models.RecognitionOptions) (jobOptions, error) {
+	task, err := NormalizeTask(options.Task)
+	if err != nil {
+		return jobOptions{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的识别任务", err.Error())
+	}
+
+	decoding := config.Decoding
+	if options.Decoding != nil {
+		decoding = *options.Decoding
+	}
+	decoding, err = ResolveDecodingOptions(decoding)
+	if err != nil {
+		return jobOptions{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的解码参数", err.Error())
+	}
+
+	diarization := config.Diarization
+	if options.Diarization != nil {
+		diarization = *options.Diarization
+	}
+	diarization, err = ResolveDiarizationConfig(diarization)
+	if err != nil {
+		return jobOptions{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的说话人分离参数", err.Error())
+	}
+
+	if options.AudioTrack != nil && *options.AudioTrack < 0 {
+		return jobOptions{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的音轨序号", fmt.Sprintf("audioTrack: %d", *options.AudioTrack))
+	}
+
+	// 文件时长要等探测后才知道，这里只做基本校验，排序、合并、补全和截断由音频处理器完成
+	if err := audio.ValidateTimeRanges(options.TimeRanges); err != nil {
+		return jobOptions{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的时间范围", err.Error())
+	}
+
+	job := jobOptions{config: config, task: task, decoding: decoding, diarization: diarization, audioTrack: options.AudioTrack, timeRanges: options.TimeRanges}
+	if options.Vocabulary != nil {
+		job.decoding.InitialPrompt, job.promptTerms = buildVocabularyPrompt(decoding.InitialPrompt, options.Vocabulary)
+		job.vocabulary = newVocabularyCorrector(options.Vocabulary)
+	}
+	return job, nil
+}
+
+// convertOptions 音频转换选项，language为请求的识别语言
+func (j jobOptions) convertOptions(language string) audio.ConvertOptions {
+	whisperLanguage, _ := MapLanguageToWhisper(language)
+	return audio.ConvertOptions{AudioTrack: j.audioTrack, Language: whisperLanguage, TimeRanges: j.timeRanges}
+}
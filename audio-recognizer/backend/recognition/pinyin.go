@@ -0,0 +1,150 @@
+package recognition
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	pinyinOnce      sync.Once
+	pinyinOfHan     map[rune]string
+	pinyinSyllables map[string]bool // 不带声调的音节，用于校验发音提示
+)
+
+// hanPinyin 获取汉字的带调拼音（如"wei1"），未收录的字返回false
+func hanPinyin(r rune) (string, bool) {
+	pinyinOnce.Do(func() {
+		pinyinOfHan = make(map[rune]string, 6800)
+		pinyinSyllables = make(map[string]bool, 420)
+		for syllable, chars := range pinyinTable {
+			for _, char := range chars {
+				pinyinOfHan[char] = syllable
+			}
+			base, _ := splitPinyinTone(syllable)
+			pinyinSyllables[base] = true
+		}
+	})
+	syllable, ok := pinyinOfHan[r]
+	return syllable, ok
+}
+
+// textPinyin 将全部由汉字组成的文本转换为带调拼音音节，含其他字符或未收录的字时返回false
+func textPinyin(text string) ([]string, bool) {
+	var syllables []string
+	for _, r := range text {
+		syllable, ok := hanPinyin(r)
+		if !ok {
+			return nil, false
+		}
+		syllables = append(syllables, syllable)
+	}
+	return syllables, len(syllables) > 0
+}
+
+// pinyinToneMarks 带声调符号的拼音字母对应的字母和声调
+var pinyinToneMarks = map[rune]struct {
+	letter rune
+	tone   byte
+}{
+	'ā': {'a', '1'}, 'á': {'a', '2'}, 'ǎ': {'a', '3'}, 'à': {'a', '4'},
+	'ē': {'e', '1'}, 'é': {'e', '2'}, 'ě': {'e', '3'}, 'è': {'e', '4'},
+	'ī': {'i', '1'}, 'í': {'i', '2'}, 'ǐ': {'i', '3'}, 'ì': {'i', '4'},
+	'ō': {'o', '1'}, 'ó': {'o', '2'}, 'ǒ': {'o', '3'}, 'ò': {'o', '4'},
+	'ū': {'u', '1'}, 'ú': {'u', '2'}, 'ǔ': {'u', '3'}, 'ù': {'u', '4'},
+	'ǖ': {'u', '1'}, 'ǘ': {'u', '2'}, 'ǚ': {'u', '3'}, 'ǜ': {'u', '4'},
+}
+
+// parsePinyinHint 解析拼音发音提示，音节以空格、连字符或撇号分隔，可带声调数字或声调符号
+// 音节统一为"wei1"的形式，未标声调的音节不带数字；含不是拼音的音节时返回nil（此时提示按英文近音写法处理）
+func parsePinyinHint(hint string) []string {
+	hanPinyin(0) // 确保音节表已初始化
+	fields := strings.FieldsFunc(strings.ToLower(hint), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '\''
+	})
+	syllables := make([]string, 0, len(fields))
+	for _, field := range fields {
+		var base strings.Builder
+		var tone byte
+		for _, r := range field {
+			switch {
+			case r >= '1' && r <= '5':
+				tone = byte(r)
+			case r == 'ü' || r == 'v':
+				base.WriteRune('u')
+			default:
+				if mark, ok := pinyinToneMarks[r]; ok {
+					base.WriteRune(mark.letter)
+					tone = mark.tone
+				} else {
+					base.WriteRune(r)
+				}
+			}
+		}
+		if !pinyinSyllables[base.String()] {
+			return nil
+		}
+		syllable := base.String()
+		if tone != 0 {
+			syllable += string(tone)
+		}
+		syllables = append(syllables, syllable)
+	}
+	if len(syllables) == 0 {
+		return nil
+	}
+	return syllables
+}
+
+// splitPinyinTone 拆分音节和声调，未标声调时返回0
+func splitPinyinTone(syllable string) (string, byte) {
+	if n := len(syllable); n > 0 && syllable[n-1] >= '1' && syllable[n-1] <= '5' {
+		return syllable[:n-1], syllable[n-1]
+	}
+	return syllable, 0
+}
+
+// tonedPinyin 判断各音节是否都标了声调
+func tonedPinyin(syllables []string) bool {
+	for _, syllable := range syllables {
+		if _, tone := splitPinyinTone(syllable); tone == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzyPinyin 归一化易混淆的读音（平翘舌 z/zh、c/ch、s/sh，n/l，前后鼻音 an/ang、en/eng、in/ing）
+func fuzzyPinyin(syllable string) string {
+	switch {
+	case strings.HasPrefix(syllable, "zh"), strings.HasPrefix(syllable, "ch"), strings.HasPrefix(syllable, "sh"):
+		syllable = syllable[:1] + syllable[2:]
+	case strings.HasPrefix(syllable, "l"):
+		syllable = "n" + syllable[1:]
+	}
+	if strings.HasSuffix(syllable, "ang") || strings.HasSuffix(syllable, "eng") || strings.HasSuffix(syllable, "ing") {
+		syllable = strings.TrimSuffix(syllable, "g")
+	}
+	return syllable
+}
+
+// samePinyin 比较两组音节，声调不同视为不同读音（未标声调的音节不比较声调），fuzzy为true时按易混淆读音比较
+func samePinyin(a, b []string, fuzzy bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		baseA, toneA := splitPinyinTone(a[i])
+		baseB, toneB := splitPinyinTone(b[i])
+		if toneA != 0 && toneB != 0 && toneA != toneB {
+			return false
+		}
+		if baseA == baseB {
+			continue
+		}
+		if !fuzzy || fuzzyPinyin(baseA) != fuzzyPinyin(baseB) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,1224 @@
+package recognition
+
+// pinyinTable GB2312一、二级汉字（6763字）的带调拼音，按音节分组，音节末尾的数字为声调（5为轻声）
+// 由 ICU 的 Han-Latin 转写生成后将声调符号改写为数字（多音字取常用读音，ü记为u）:
+//
+//	uconv -x "Han-Latin; Lower" < gb2312-hanzi.txt
+var pinyinTable = map[string]string{
+	"a1":      "阿锕",
+	"a2":      "嗄",
+	"a5":      "啊",
+	"ai1":     "埃挨哎唉哀嗳锿",
+	"ai2":     "皑癌捱",
+	"ai3":     "蔼矮霭",
+	"ai4":     "艾碍爱隘嗌嫒瑷暧砹",
+	"an1":     "鞍氨安谙庵桉鹌",
+	"an3":     "俺埯揞铵",
+	"an4":     "按暗岸胺案犴黯",
+	"ang1":    "肮",
+	"ang2":    "昂",
+	"ang4":    "盎",
+	"ao1":     "凹",
+	"ao2":     "敖熬翱嗷廒遨獒聱螯鳌鏖",
+	"ao3":     "袄拗媪",
+	"ao4":     "傲奥懊澳坳岙骜鏊",
+	"ba1":     "芭捌扒叭笆八疤巴岜粑",
+	"ba2":     "拔跋茇菝魃",
+	"ba3":     "靶把钯",
+	"ba4":     "耙坝霸罢爸灞鲅",
+	"ba5":     "吧",
+	"bai1":    "掰擘",
+	"bai2":    "白",
+	"bai3":    "柏百摆佰捭",
+	"bai4":    "败拜稗",
+	"ban1":    "斑班搬扳般颁瘢癍",
+	"ban3":    "板版阪坂钣舨",
+	"ban4":    "扮拌伴瓣半办绊",
+	"bang1":   "邦帮梆浜",
+	"bang3":   "榜膀绑",
+	"bang4":   "棒磅蚌镑傍谤蒡",
+	"bao1":    "苞胞包褒勹孢煲龅",
+	"bao2":    "薄雹",
+	"bao3":    "保堡饱宝葆鸨褓",
+	"bao4":    "抱报暴豹鲍爆趵",
+	"bei1":    "杯碑悲卑陂鹎",
+	"bei3":    "北",
+	"bei4":    "辈背贝钡倍狈备惫焙被孛邶蓓悖碚褙鐾鞴",
+	"bei5":    "呗",
+	"ben1":    "奔贲锛",
+	"ben3":    "苯本畚",
+	"ben4":    "笨坌",
+	"beng1":   "崩绷嘣",
+	"beng2":   "甭",
+	"beng4":   "泵蹦迸甏",
+	"bi1":     "逼",
+	"bi2":     "鼻荸",
+	"bi3":     "比鄙笔彼匕俾吡妣秕舭",
+	"bi4":     "碧蓖蔽毕毙毖币庇痹闭敝弊必壁臂避陛荜萆薜哔狴庳愎滗濞弼婢嬖璧畀铋裨筚箅篦襞跸髀",
+	"bian1":   "鞭边编煸砭蝙笾鳊",
+	"bian3":   "贬扁匾碥窆褊",
+	"bian4":   "便变卞辨辩辫遍弁苄忭汴缏",
+	"biao1":   "标彪膘骠杓飑飙飚灬镖镳瘭髟",
+	"biao3":   "表婊裱",
+	"biao4":   "鳔",
+	"bie1":    "鳖憋",
+	"bie2":    "别蹩",
+	"bie3":    "瘪",
+	"bin1":    "彬斌濒滨宾傧豳缤玢槟镔",
+	"bin4":    "摈殡膑髌鬓",
+	"bing1":   "兵冰冫",
+	"bing3":   "柄丙秉饼炳禀邴摒",
+	"bing4":   "病并",
+	"bo1":     "剥玻菠播拨钵波啵饽",
+	"bo2":     "博勃搏铂箔伯帛舶脖膊渤驳亳礴钹鹁踣",
+	"bo3":     "簸跛",
+	"bo4":     "檗",
+	"bo5":     "卜",
+	"bu1":     "逋晡钸",
+	"bu2":     "醭",
+	"bu3":     "捕哺补卟",
+	"bu4":     "埠不布步簿部怖埔瓿钚",
+	"ca1":     "擦嚓",
+	"ca3":     "礤",
+	"cai1":    "猜",
+	"cai2":    "裁材才财",
+	"cai3":    "睬踩采彩",
+	"cai4":    "菜蔡",
+	"can1":    "餐参骖",
+	"can2":    "蚕残惭",
+	"can3":    "惨黪",
+	"can4":    "灿掺孱璨粲",
+	"cang1":   "苍舱仓沧伧",
+	"cang2":   "藏",
+	"cao1":    "操糙",
+	"cao2":    "槽曹嘈漕螬艚",
+	"cao3":    "草",
+	"cao5":    "艹",
+	"ce4":     "厕策侧册测恻",
+	"cen2":    "岑涔",
+	"ceng1":   "噌",
+	"ceng2":   "层曾",
+	"ceng4":   "蹭",
+	"cha1":    "插叉馇杈锸",
+	"cha2":    "茬茶查碴搽察猹槎檫",
+	"cha3":    "镲衩",
+	"cha4":    "岔差诧汊姹",
+	"chai1":   "拆钗",
+	"chai2":   "柴豺侪",
+	"chai4":   "瘥虿",
+	"chan1":   "搀觇",
+	"chan2":   "蝉馋谗缠廛潺澶婵禅镡蟾躔",
+	"chan3":   "铲产阐冁谄蒇骣",
+	"chan4":   "颤忏羼",
+	"chang1":  "昌猖伥菖阊娼鲳",
+	"chang2":  "尝常偿肠苌徜嫦",
+	"chang3":  "场厂敞惝昶氅",
+	"chang4":  "畅唱倡鬯怅",
+	"chao1":   "超抄钞怊焯",
+	"chao2":   "朝嘲潮巢晁",
+	"chao3":   "吵炒",
+	"chao4":   "耖",
+	"che1":    "车砗",
+	"che3":    "扯",
+	"che4":    "撤掣彻澈坼屮",
+	"chen1":   "郴抻嗔琛",
+	"chen2":   "臣辰尘晨忱沉陈谌宸",
+	"chen3":   "碜",
+	"chen4":   "趁衬谶榇龀",
+	"cheng1":  "撑称柽瞠蛏",
+	"cheng2":  "城橙成呈乘程惩澄诚承丞埕枨晟塍铖裎酲",
+	"cheng3":  "逞骋",
+	"cheng4":  "秤",
+	"chi1":    "吃痴哧嗤媸眵鸱蚩螭笞魑",
+	"chi2":    "持池迟弛驰坻墀茌篪踟",
+	"chi3":    "耻齿侈尺褫",
+	"chi4":    "赤翅斥炽傺叱啻彳饬敕瘛",
+	"chong1":  "充冲茺忡憧舂艟",
+	"chong2":  "虫崇",
+	"chong3":  "宠",
+	"chong4":  "铳",
+	"chou1":   "抽瘳",
+	"chou2":   "酬畴踌稠愁筹仇绸俦帱惆雠",
+	"chou3":   "瞅丑",
+	"chou4":   "臭",
+	"chu1":    "初出樗",
+	"chu2":    "橱厨躇锄雏滁除刍蜍蹰",
+	"chu3":    "楚础储杵楮褚",
+	"chu4":    "矗搐触处畜亍怵憷绌黜",
+	"chuai1":  "揣搋",
+	"chuai4":  "啜嘬膪踹",
+	"chuan1":  "川穿巛氚",
+	"chuan2":  "椽传船遄舡",
+	"chuan3":  "喘舛",
+	"chuan4":  "串钏",
+	"chuang1": "疮窗",
+	"chuang2": "幢床",
+	"chuang3": "闯",
+	"chuang4": "创怆",
+	"chui1":   "吹炊",
+	"chui2":   "捶锤垂椎陲棰槌",
+	"chun1":   "春椿蝽",
+	"chun2":   "醇唇淳纯莼鹑",
+	"chun3":   "蠢",
+	"chuo1":   "戳踔",
+	"chuo4":   "绰辶辍龊",
+	"ci1":     "疵呲",
+	"ci2":     "茨磁雌辞慈瓷词茈祠鹚糍",
+	"ci3":     "此",
+	"ci4":     "刺赐次伺",
+	"cong1":   "聪葱囱匆苁骢璁枞",
+	"cong2":   "从丛淙琮",
+	"cou4":    "凑辏腠",
+	"cu1":     "粗",
+	"cu2":     "徂殂",
+	"cu4":     "醋簇促蔟猝酢蹙蹴",
+	"cuan1":   "蹿汆撺镩",
+	"cuan4":   "篡窜爨",
+	"cui1":    "摧崔催榱",
+	"cui3":    "璀",
+	"cui4":    "脆瘁粹淬翠萃啐悴毳",
+	"cun1":    "村皴",
+	"cun2":    "存",
+	"cun3":    "忖",
+	"cun4":    "寸",
+	"cuo1":    "磋撮搓蹉",
+	"cuo2":    "嵯矬痤鹾",
+	"cuo3":    "脞",
+	"cuo4":    "措挫错厝锉",
+	"da1":     "搭耷嗒褡",
+	"da2":     "达答哒怛妲沓笪靼鞑",
+	"da3":     "打",
+	"da4":     "大",
+	"da5":     "瘩",
+	"dai1":    "呆呔",
+	"dai3":    "歹傣逮",
+	"dai4":    "戴带殆代贷袋待怠埭甙岱迨骀绐玳黛",
+	"dan1":    "耽担丹单郸儋殚眈瘅聃箪",
+	"dan3":    "掸胆赕疸",
+	"dan4":    "旦氮但惮淡诞弹蛋萏啖澹",
+	"dang1":   "当铛裆",
+	"dang3":   "挡党谠",
+	"dang4":   "荡档凼菪宕砀",
+	"dao1":    "刀刂叨忉氘",
+	"dao3":    "捣蹈岛祷导",
+	"dao4":    "倒到稻悼道盗焘纛",
+	"de2":     "德得锝",
+	"de5":     "的地",
+	"deng1":   "蹬灯登噔簦",
+	"deng3":   "等戥",
+	"deng4":   "瞪凳邓嶝磴镫",
+	"di1":     "堤低滴氐镝羝",
+	"di2":     "迪敌笛狄涤翟嫡籴荻嘀觌",
+	"di3":     "抵底诋邸柢砥骶",
+	"di4":     "蒂第帝弟递缔谛娣棣碲睇",
+	"dian1":   "颠掂滇甸巅癫",
+	"dian3":   "碘点典踮",
+	"dian4":   "靛垫电佃店惦奠淀殿阽坫玷钿癜簟",
+	"diao1":   "碉叼雕凋刁貂鲷",
+	"diao4":   "掉吊钓调铞铫",
+	"die1":    "跌爹嗲",
+	"die2":    "碟蝶迭谍叠垤堞揲喋牒瓞耋蹀鲽",
+	"ding1":   "丁盯叮钉仃玎疔耵酊",
+	"ding3":   "顶鼎",
+	"ding4":   "锭定订啶腚碇铤",
+	"diu1":    "丢铥",
+	"dong1":   "东冬咚岽氡鸫",
+	"dong3":   "董懂",
+	"dong4":   "动栋侗恫冻洞垌峒胨胴硐",
+	"dou1":    "兜都蔸篼",
+	"dou3":    "抖陡蚪",
+	"dou4":    "斗豆逗痘窦",
+	"du1":     "督嘟",
+	"du2":     "毒犊独读渎椟牍碡髑黩",
+	"du3":     "堵睹赌笃",
+	"du4":     "杜镀肚度渡妒芏蠹",
+	"duan1":   "端",
+	"duan3":   "短",
+	"duan4":   "锻段断缎椴煅簖",
+	"dui1":    "堆",
+	"dui4":    "兑队对怼憝碓镦",
+	"dun1":    "墩吨蹲敦礅",
+	"dun3":    "盹趸",
+	"dun4":    "顿囤钝盾遁沌炖砘",
+	"duo1":    "掇哆多咄裰",
+	"duo2":    "夺铎踱",
+	"duo3":    "垛躲朵哚缍",
+	"duo4":    "跺舵剁惰堕柁",
+	"e1":      "屙婀",
+	"e2":      "蛾峨鹅俄额讹娥莪锇",
+	"e4":      "恶厄扼遏鄂饿噩谔垩苊萼呃愕阏轭腭锷鹗颚鳄",
+	"ei2":     "诶",
+	"en1":     "恩蒽",
+	"en4":     "摁",
+	"er2":     "而儿鸸鲕",
+	"er3":     "耳尔饵洱迩珥铒",
+	"er4":     "二贰佴",
+	"fa1":     "发",
+	"fa2":     "罚筏伐乏阀垡砝",
+	"fa3":     "法",
+	"fa4":     "珐",
+	"fan1":    "藩帆番翻蕃幡",
+	"fan2":    "樊矾钒繁凡烦蘩燔蹯",
+	"fan3":    "反返",
+	"fan4":    "范贩犯饭泛梵畈",
+	"fang1":   "坊芳方匚邡枋钫",
+	"fang2":   "肪房防妨鲂",
+	"fang3":   "仿访纺彷舫",
+	"fang4":   "放",
+	"fei1":    "菲非啡飞妃绯扉蜚霏鲱",
+	"fei2":    "肥淝腓",
+	"fei3":    "匪诽悱榧斐篚翡",
+	"fei4":    "吠肺废沸费芾狒镄痱",
+	"fen1":    "芬酚吩氛分纷",
+	"fen2":    "坟焚汾棼鼢",
+	"fen3":    "粉",
+	"fen4":    "奋份忿愤粪偾瀵鲼",
+	"feng1":   "丰封枫蜂峰锋风疯烽酆葑沣砜",
+	"feng2":   "逢冯",
+	"feng3":   "讽唪",
+	"feng4":   "缝奉凤俸",
+	"fou3":    "否缶",
+	"fu1":     "夫敷肤孵呋稃麸趺跗",
+	"fu2":     "佛扶拂辐幅氟符伏俘服浮涪福袱弗匐凫郛芙苻茯莩菔幞怫艴孚绂绋桴祓砩黻罘蚨蜉蝠",
+	"fu3":     "甫抚辅俯釜斧腑府腐拊呒滏黼",
+	"fu4":     "赴副覆赋复傅付阜父腹负富讣附妇缚咐阝驸赙馥蝮鲋鳆",
+	"ga1":     "嘎呷旮",
+	"ga2":     "噶尜钆",
+	"ga3":     "尕",
+	"ga4":     "尬",
+	"gai1":    "该陔垓赅",
+	"gai3":    "改",
+	"gai4":    "概钙盖溉丐戤",
+	"gan1":    "甘杆柑竿肝坩苷尴泔矸疳酐",
+	"gan3":    "赶感秆敢擀澉橄",
+	"gan4":    "干赣淦绀旰",
+	"gang1":   "冈刚钢缸肛纲杠罡",
+	"gang3":   "岗港",
+	"gang4":   "戆筻",
+	"gao1":    "篙皋高膏羔糕睾槔",
+	"gao3":    "搞镐稿藁缟槁杲",
+	"gao4":    "告诰郜锆",
+	"ge1":     "哥歌搁戈鸽胳疙割咯仡圪纥袼",
+	"ge2":     "革葛格阁隔鬲塥嗝搿膈镉骼",
+	"ge3":     "哿舸",
+	"ge4":     "铬个各硌虼",
+	"gei3":    "给",
+	"gen1":    "根跟",
+	"gen2":    "哏",
+	"gen3":    "艮",
+	"gen4":    "亘茛",
+	"geng1":   "耕庚羹赓",
+	"geng3":   "埂耿梗哽绠鲠",
+	"geng4":   "更",
+	"gong1":   "工攻功恭龚供躬公宫弓肱蚣觥",
+	"gong3":   "巩汞拱廾珙",
+	"gong4":   "贡共",
+	"gou1":    "钩勾沟佝缑篝鞲",
+	"gou3":    "苟狗岣枸笱",
+	"gou4":    "垢构购够诟遘媾觏彀",
+	"gu1":     "辜菇咕箍估沽孤姑菰呱轱鸪蛄酤觚",
+	"gu3":     "鼓古蛊骨谷股嘏诂汩牯臌毂瞽罟钴鹄鹘",
+	"gu4":     "故顾固雇崮梏牿锢痼鲴",
+	"gua1":    "刮瓜栝胍鸹聒",
+	"gua3":    "剐寡",
+	"gua4":    "挂褂卦诖",
+	"guai1":   "乖掴",
+	"guai3":   "拐",
+	"guai4":   "怪",
+	"guan1":   "棺关官冠观倌鳏",
+	"guan3":   "管馆莞",
+	"guan4":   "罐惯灌贯掼涫盥鹳",
+	"guang1":  "光咣桄胱",
+	"guang3":  "广犷",
+	"guang4":  "逛",
+	"gui1":    "瑰规圭硅归龟闺傀妫皈鲑",
+	"gui3":    "轨鬼诡癸匦庋宄晷簋",
+	"gui4":    "桂柜跪贵刽炔刿桧鳜",
+	"gun3":    "辊滚丨衮绲磙鲧",
+	"gun4":    "棍",
+	"guo1":    "锅郭埚呙崞蝈",
+	"guo2":    "国馘帼虢",
+	"guo3":    "果裹猓椁蜾",
+	"guo4":    "过",
+	"ha1":     "哈铪",
+	"ha2":     "蛤",
+	"hai1":    "咳嗨",
+	"hai2":    "骸孩还",
+	"hai3":    "海胲醢",
+	"hai4":    "氦亥害骇",
+	"han1":    "酣憨顸蚶鼾",
+	"han2":    "邯韩含涵寒函邗晗焓",
+	"han3":    "喊罕阚",
+	"han4":    "翰撼捍旱憾悍焊汗汉菡撖瀚颔",
+	"hang1":   "夯",
+	"hang2":   "杭航绗珩颃",
+	"hang4":   "沆",
+	"hao1":    "蒿薅嚆",
+	"hao2":    "壕嚎豪毫貉嗥濠蚝",
+	"hao3":    "郝好",
+	"hao4":    "耗号浩灏昊皓颢",
+	"he1":     "呵喝诃嗬",
+	"he2":     "荷菏核禾和何合盒阂河涸劾阖曷盍颌蚵翮",
+	"he4":     "赫褐鹤贺壑",
+	"hei1":    "嘿黑",
+	"hen2":    "痕",
+	"hen3":    "很狠",
+	"hen4":    "恨",
+	"heng1":   "哼亨",
+	"heng2":   "横衡恒蘅桁",
+	"hong1":   "轰哄烘訇薨",
+	"hong2":   "虹鸿洪宏弘红黉荭蕻闳泓",
+	"hong4":   "讧",
+	"hou2":    "喉侯猴瘊篌糇骺",
+	"hou3":    "吼",
+	"hou4":    "厚候后堠後逅鲎",
+	"hu1":     "呼乎忽唿惚滹轷烀虍",
+	"hu2":     "瑚壶葫胡蝴狐糊湖弧囫猢槲觳煳鹕醐斛",
+	"hu3":     "虎唬浒琥",
+	"hu4":     "护互沪户冱岵怙戽扈祜瓠鹱笏",
+	"hua1":    "花哗",
+	"hua2":    "华猾滑骅铧",
+	"hua4":    "画划化话桦",
+	"huai2":   "槐徊怀淮踝",
+	"huai4":   "坏",
+	"huan1":   "欢獾",
+	"huan2":   "环桓郇萑圜洹寰缳锾鬟",
+	"huan3":   "缓",
+	"huan4":   "换患唤痪豢焕涣宦幻奂擐浣漶逭鲩",
+	"huang1":  "荒慌肓",
+	"huang2":  "黄磺蝗簧皇凰惶煌隍徨湟潢遑璜癀蟥篁鳇",
+	"huang3":  "晃幌恍谎",
+	"hui1":    "灰挥辉徽恢诙咴隳珲晖虺麾",
+	"hui2":    "蛔回茴洄",
+	"hui3":    "毁悔",
+	"hui4":    "慧卉惠晦贿秽会烩汇讳诲绘荟蕙哕喙浍彗缋恚蟪",
+	"hun1":    "荤昏婚阍",
+	"hun2":    "魂浑馄",
+	"hun4":    "混诨溷",
+	"huo1":    "豁劐攉锪耠",
+	"huo2":    "活",
+	"huo3":    "伙火夥钬",
+	"huo4":    "获或惑霍货祸藿嚯砉镬蠖",
+	"ji1":     "击圾基机畸稽积箕肌饥迹激讥鸡姬绩缉丌乩剞墼芨叽咭唧屐畿玑赍犄齑矶羁嵇笄跻",
+	"ji2":     "吉极棘辑籍集及急疾汲即嫉级脊藉亟佶诘蒺蕺岌楫殛戢瘠笈",
+	"ji3":     "挤几己掎嵴戟虮麂",
+	"ji4":     "蓟技冀季伎祭剂悸济寄寂计记既忌际妓继纪偈芰荠哜洎彐骥觊稷暨跽霁鲚鲫髻",
+	"jia1":    "嘉枷夹佳家加茄伽葭浃迦珈镓痂笳袈跏",
+	"jia2":    "荚颊郏戛恝铗蛱",
+	"jia3":    "贾甲钾假岬胛瘕",
+	"jia4":    "稼价架驾嫁",
+	"jian1":   "歼监坚尖笺间煎兼肩艰奸缄菅蒹搛湔缣戋犍鹣鲣鞯",
+	"jian3":   "茧检柬碱硷拣捡简俭剪减谫囝蹇謇枧戬睑锏裥笕翦趼",
+	"jian4":   "荐鉴践贱见键箭件健舰剑饯渐溅涧建僭谏楗牮毽腱踺",
+	"jiang1":  "僵姜将浆江疆茳缰礓豇",
+	"jiang3":  "蒋桨奖讲耩",
+	"jiang4":  "匠酱降洚绛犟糨",
+	"jiao1":   "蕉椒礁焦胶交郊浇骄娇僬艽茭姣鹪蛟跤鲛",
+	"jiao3":   "搅铰矫侥脚狡角饺缴绞剿佼挢徼湫敫皎",
+	"jiao4":   "教酵轿较叫窖噍峤醮",
+	"jie1":    "揭接皆秸街阶喈嗟疖",
+	"jie2":    "截劫节杰捷睫竭洁结讦卩拮婕孑桀碣颉羯鲒",
+	"jie3":    "解姐",
+	"jie4":    "戒芥界借介疥诫届蚧骱",
+	"jin1":    "巾筋斤金今津襟钅衿矜",
+	"jin3":    "紧锦仅谨尽卺堇馑廑瑾槿",
+	"jin4":    "进靳晋禁近烬浸劲荩噤妗缙赆觐",
+	"jing1":   "荆兢茎睛晶鲸京惊精粳经菁泾腈旌",
+	"jing3":   "井警景颈刭儆阱憬肼",
+	"jing4":   "静境敬镜径痉靖竟竞净獍迳弪婧胫靓",
+	"jiong1":  "冂扃",
+	"jiong3":  "炯窘迥炅",
+	"jiu1":    "揪究纠啾阄鸠赳鬏",
+	"jiu3":    "玖韭久灸九酒",
+	"jiu4":    "厩救旧臼舅咎就疚僦柩桕鹫",
+	"ju1":     "鞠拘狙疽居驹苴菹掬琚椐锔裾趄雎鞫",
+	"ju2":     "桔菊局橘",
+	"ju3":     "咀矩举沮莒榘榉踽龃",
+	"ju4":     "聚拒据巨具距踞锯俱句惧炬剧倨讵苣遽屦犋飓钜窭醵",
+	"juan1":   "捐鹃娟涓蠲镌",
+	"juan3":   "卷锩",
+	"juan4":   "倦眷绢鄄狷桊隽",
+	"jue1":    "撅噘",
+	"jue2":    "嚼攫抉掘倔爵觉决诀绝厥劂谲矍蕨噱崛獗孓珏桷橛爝镢蹶觖",
+	"jun1":    "均菌钧军君皲麇",
+	"jun4":    "峻俊竣浚郡骏捃",
+	"ka1":     "喀咖咔",
+	"ka3":     "卡佧胩",
+	"kai1":    "开揩锎",
+	"kai3":    "楷凯慨剀垲蒈恺铠锴",
+	"kai4":    "忾",
+	"kan1":    "刊堪勘戡龛",
+	"kan3":    "槛坎砍侃莰",
+	"kan4":    "看瞰",
+	"kang1":   "康慷糠闶",
+	"kang2":   "扛",
+	"kang4":   "抗亢炕伉钪",
+	"kao1":    "尻",
+	"kao3":    "考拷烤栲",
+	"kao4":    "靠犒铐",
+	"ke1":     "苛柯棵磕颗科嗑珂轲瞌钶稞疴窠颏蝌髁",
+	"ke2":     "壳",
+	"ke3":     "坷可渴岢",
+	"ke4":     "克刻客课恪溘骒缂氪锞",
+	"ken3":    "肯啃垦恳龈",
+	"ken4":    "裉",
+	"keng1":   "坑吭铿",
+	"kong1":   "空倥崆箜",
+	"kong3":   "恐孔",
+	"kong4":   "控",
+	"kou1":    "抠芤眍",
+	"kou3":    "口",
+	"kou4":    "扣寇蔻叩筘",
+	"ku1":     "枯哭窟刳堀骷",
+	"ku3":     "苦",
+	"ku4":     "酷库裤喾绔",
+	"kua1":    "夸",
+	"kua3":    "垮侉",
+	"kua4":    "挎跨胯",
+	"kuai3":   "蒯",
+	"kuai4":   "块筷侩快郐哙狯脍",
+	"kuan1":   "宽髋",
+	"kuan3":   "款",
+	"kuang1":  "匡筐框诓哐",
+	"kuang2":  "狂诳",
+	"kuang3":  "夼",
+	"kuang4":  "矿眶旷况邝圹纩贶",
+	"kui1":    "亏盔岿窥悝",
+	"kui2":    "葵奎魁馗夔隗揆喹逵暌睽蝰",
+	"kui3":    "跬",
+	"kui4":    "馈愧溃匮蒉喟愦聩篑",
+	"kun1":    "坤昆琨锟醌鲲髡",
+	"kun3":    "捆悃阃",
+	"kun4":    "困",
+	"kuo4":    "括扩廓阔蛞",
+	"la1":     "垃拉邋",
+	"la2":     "剌旯砬",
+	"la3":     "喇",
+	"la4":     "蜡腊辣瘌",
+	"la5":     "啦",
+	"lai2":    "莱来崃徕涞铼",
+	"lai4":    "赖濑赉睐癞籁",
+	"lan2":    "蓝婪栏拦篮阑兰澜谰岚斓镧褴",
+	"lan3":    "揽览懒缆漤榄罱",
+	"lan4":    "烂滥",
+	"lang1":   "啷",
+	"lang2":   "琅榔狼廊郎阆锒稂螂",
+	"lang3":   "朗",
+	"lang4":   "浪莨蒗",
+	"lao1":    "捞",
+	"lao2":    "劳牢唠崂铹痨醪",
+	"lao3":    "老佬姥潦栳铑",
+	"lao4":    "酪烙涝耢",
+	"le1":     "肋",
+	"le4":     "乐仂叻泐鳓",
+	"le5":     "了",
+	"lei1":    "勒",
+	"lei2":    "雷镭擂羸嫘缧檑",
+	"lei3":    "蕾磊儡垒诔耒",
+	"lei4":    "累类泪酹",
+	"lei5":    "嘞",
+	"leng2":   "棱楞塄",
+	"leng3":   "冷",
+	"leng4":   "愣",
+	"li1":     "哩",
+	"li2":     "厘梨犁黎篱狸离漓璃蓠藜喱嫠骊缡罹鹂蜊蠡鲡黧",
+	"li3":     "理李里鲤礼俚澧逦娌锂醴鳢",
+	"li4":     "莉荔吏栗丽厉励砾历利傈例俐痢立粒沥隶力俪郦坜苈莅呖唳猁溧枥栎轹戾砺詈疠疬蛎笠篥粝跞雳",
+	"lia3":    "俩",
+	"lian2":   "联莲连镰廉怜涟帘奁濂臁裢蠊鲢",
+	"lian3":   "敛脸蔹琏裣",
+	"lian4":   "链恋炼练潋楝殓",
+	"liang2":  "粮凉梁粱良墚椋踉",
+	"liang3":  "两魉",
+	"liang4":  "辆量晾亮谅",
+	"liao1":   "撩",
+	"liao2":   "聊僚疗燎寥辽嘹獠寮缭鹩",
+	"liao3":   "蓼钌",
+	"liao4":   "撂镣廖料尥",
+	"lie3":    "咧",
+	"lie4":    "列裂烈劣猎冽埒捩洌趔躐鬣",
+	"lin1":    "拎",
+	"lin2":    "琳林磷霖临邻鳞淋啉嶙遴辚瞵粼麟",
+	"lin3":    "凛廪懔檩",
+	"lin4":    "赁吝蔺膦躏",
+	"ling2":   "玲菱零龄铃伶羚凌灵陵酃苓囹泠绫柃棂瓴聆蛉翎鲮",
+	"ling3":   "岭领",
+	"ling4":   "另令呤",
+	"liu1":    "溜熘",
+	"liu2":    "琉榴硫馏留刘瘤流浏遛骝旒镏鎏",
+	"liu3":    "柳绺锍",
+	"liu4":    "六鹨",
+	"long2":   "龙聋咙笼窿隆茏泷珑栊胧砻癃",
+	"long3":   "垄拢陇垅",
+	"lou2":    "楼娄偻蒌喽耧蝼髅",
+	"lou3":    "搂篓嵝",
+	"lou4":    "漏陋镂瘘",
+	"lu1":     "撸噜",
+	"lu2":     "芦卢颅庐炉驴垆闾泸栌榈轳胪鸬舻鲈",
+	"lu3":     "掳卤虏鲁吕铝侣旅履屡缕捋橹膂镥稆褛",
+	"lu4":     "麓碌露路赂鹿潞禄录陆戮虑氯律率滤绿渌漉逯璐辂辘鹭簏",
+	"lu5":     "氇",
+	"luan2":   "峦挛孪滦脔娈栾鸾銮",
+	"luan3":   "卵",
+	"luan4":   "乱",
+	"lue4":    "掠略锊",
+	"lun1":    "抡",
+	"lun2":    "轮伦仑沦纶囵",
+	"lun4":    "论",
+	"luo1":    "罗",
+	"luo2":    "萝螺逻锣箩骡猡椤脶镙",
+	"luo3":    "裸倮蠃瘰",
+	"luo4":    "落洛骆络荦摞泺漯珞雒",
+	"ma1":     "妈嬷",
+	"ma2":     "麻蟆",
+	"ma3":     "玛码蚂马",
+	"ma4":     "骂唛犸杩",
+	"ma5":     "嘛吗",
+	"mai2":    "埋霾",
+	"mai3":    "买荬",
+	"mai4":    "麦卖迈脉劢",
+	"man1":    "颟",
+	"man2":    "瞒馒蛮谩蹒鳗鞔",
+	"man3":    "满螨",
+	"man4":    "蔓曼慢漫墁幔缦熳镘",
+	"mang2":   "芒茫盲氓忙邙硭",
+	"mang3":   "莽漭蟒",
+	"mao1":    "猫",
+	"mao2":    "茅锚毛矛茆牦旄蝥蟊髦",
+	"mao3":    "铆卯峁泖昴",
+	"mao4":    "茂冒帽貌贸袤瑁耄懋瞀",
+	"me5":     "么",
+	"mei2":    "玫枚梅酶霉煤没眉媒莓嵋猸湄楣镅鹛",
+	"mei3":    "镁每美浼",
+	"mei4":    "昧寐妹媚袂魅",
+	"men2":    "门扪钔",
+	"men4":    "闷焖懑",
+	"men5":    "们",
+	"meng2":   "萌蒙檬盟甍瞢朦礞虻艨",
+	"meng3":   "锰猛勐懵蜢蠓艋",
+	"meng4":   "梦孟",
+	"mi1":     "眯咪",
+	"mi2":     "醚靡糜迷谜弥蘼猕祢縻麋",
+	"mi3":     "米芈弭脒敉",
+	"mi4":     "秘觅泌蜜密幂冖谧嘧汨宓糸",
+	"mian2":   "棉眠绵宀",
+	"mian3":   "冕免勉娩缅沔渑湎腼眄黾",
+	"mian4":   "面",
+	"miao1":   "喵",
+	"miao2":   "苗描瞄鹋",
+	"miao3":   "藐秒渺邈缈杪淼眇",
+	"miao4":   "庙妙",
+	"mie1":    "乜咩",
+	"mie4":    "蔑灭蠛篾",
+	"min2":    "民苠岷缗珉",
+	"min3":    "抿皿敏悯闽闵泯愍鳘",
+	"ming2":   "明螟鸣铭名冥茗溟暝瞑",
+	"ming3":   "酩",
+	"ming4":   "命",
+	"miu4":    "谬",
+	"mo1":     "摸",
+	"mo2":     "摹蘑模膜磨摩魔谟馍嫫麽",
+	"mo3":     "抹",
+	"mo4":     "末莫墨默沫漠寞陌茉蓦殁镆秣瘼耱貊貘",
+	"mou1":    "哞",
+	"mou2":    "谋牟侔缪眸蛑鍪",
+	"mou3":    "某",
+	"mu2":     "毪",
+	"mu3":     "拇牡亩姆母坶",
+	"mu4":     "墓暮幕募慕木目睦牧穆仫苜沐钼",
+	"n2":      "嗯",
+	"na2":     "拿镎",
+	"na3":     "哪",
+	"na4":     "呐钠那娜纳捺肭衲",
+	"nai3":    "氖乃奶艿",
+	"nai4":    "耐奈鼐萘柰",
+	"nan1":    "囡",
+	"nan2":    "南男难喃楠",
+	"nan3":    "腩蝻赧",
+	"nang1":   "囔",
+	"nang2":   "囊馕",
+	"nang3":   "攮曩",
+	"nao1":    "孬",
+	"nao2":    "挠呶猱硇铙蛲",
+	"nao3":    "脑恼垴瑙",
+	"nao4":    "闹淖",
+	"ne4":     "讷疒",
+	"ne5":     "呢",
+	"nei3":    "馁",
+	"nei4":    "内",
+	"nen4":    "嫩恁",
+	"neng2":   "能",
+	"ni1":     "妮",
+	"ni2":     "霓倪泥尼坭猊怩铌鲵",
+	"ni3":     "拟你旎",
+	"ni4":     "匿腻逆溺伲昵睨",
+	"nian1":   "蔫拈",
+	"nian2":   "年黏鲇鲶",
+	"nian3":   "碾撵捻辗辇",
+	"nian4":   "念廿埝",
+	"niang2":  "娘",
+	"niang4":  "酿",
+	"niao3":   "鸟茑嬲袅",
+	"niao4":   "尿脲",
+	"nie1":    "捏",
+	"nie4":    "聂孽啮镊镍涅陧蘖嗫颞臬蹑",
+	"nin2":    "您",
+	"ning2":   "柠狞凝宁拧咛甯聍",
+	"ning4":   "泞佞",
+	"niu1":    "妞",
+	"niu2":    "牛",
+	"niu3":    "扭钮纽狃忸",
+	"nong2":   "脓浓农侬哝",
+	"nong4":   "弄",
+	"nou4":    "耨",
+	"nu2":     "奴孥驽",
+	"nu3":     "努女弩胬钕",
+	"nu4":     "怒恧衄",
+	"nuan3":   "暖",
+	"nue4":    "虐疟",
+	"nuo2":    "挪傩",
+	"nuo4":    "懦糯诺搦喏锘",
+	"o1":      "喔噢",
+	"o2":      "哦",
+	"ou1":     "欧鸥殴沤讴瓯",
+	"ou3":     "藕呕偶耦",
+	"ou4":     "怄",
+	"pa1":     "啪趴葩",
+	"pa2":     "爬琶杷筢",
+	"pa4":     "帕怕",
+	"pai1":    "拍",
+	"pai2":    "排牌徘俳",
+	"pai4":    "湃派蒎哌",
+	"pan1":    "攀潘",
+	"pan2":    "盘磐爿蟠",
+	"pan4":    "盼畔判叛拚泮袢襻",
+	"pang1":   "乓滂",
+	"pang2":   "庞旁逄螃",
+	"pang3":   "耪",
+	"pang4":   "胖",
+	"pao1":    "抛脬",
+	"pao2":    "咆刨袍匏狍庖",
+	"pao3":    "跑",
+	"pao4":    "炮泡疱",
+	"pei1":    "呸胚醅",
+	"pei2":    "培裴赔陪锫",
+	"pei4":    "配佩沛辔帔旆霈",
+	"pen1":    "喷",
+	"pen2":    "盆湓",
+	"peng1":   "砰抨烹澎嘭怦",
+	"peng2":   "彭蓬棚硼篷膨朋鹏堋蟛",
+	"peng3":   "捧",
+	"peng4":   "碰",
+	"pi1":     "坯砒霹批披劈丕邳噼纰铍",
+	"pi2":     "琵毗啤脾疲皮陴郫埤鼙芘枇罴蚍蜱貔",
+	"pi3":     "匹痞仳圮擗庀癖疋",
+	"pi4":     "辟僻屁譬淠媲甓睥",
+	"pian1":   "篇偏犏翩",
+	"pian2":   "谝骈胼蹁",
+	"pian4":   "片骗",
+	"piao1":   "飘剽缥螵",
+	"piao2":   "瓢嫖",
+	"piao3":   "殍瞟",
+	"piao4":   "漂票嘌",
+	"pie1":    "撇瞥氕",
+	"pie3":    "丿苤",
+	"pin1":    "拼姘",
+	"pin2":    "频贫嫔颦",
+	"pin3":    "品榀",
+	"pin4":    "聘牝",
+	"ping1":   "乒俜娉",
+	"ping2":   "坪苹萍平凭瓶评屏枰鲆",
+	"po1":     "泊坡泼钋",
+	"po2":     "婆鄱皤",
+	"po3":     "颇叵钷笸",
+	"po4":     "破魄迫粕珀",
+	"pou1":    "剖",
+	"pou2":    "裒掊",
+	"pu1":     "扑仆噗攴攵",
+	"pu2":     "脯莆葡菩蒲匍濮璞镤",
+	"pu3":     "朴圃普浦谱溥氆镨蹼",
+	"pu4":     "铺曝瀑",
+	"qi1":     "期欺栖戚妻七凄漆柒沏萋嘁桤槭蹊",
+	"qi2":     "其棋奇歧畦崎脐齐旗祈祁骑亓俟圻芪萁蕲岐淇骐琪琦耆祺颀蛴蜞綦鳍麒",
+	"qi3":     "起岂乞企启芑屺绮杞綮",
+	"qi4":     "契砌器气迄弃汽泣讫葺汔憩碛",
+	"qia1":    "掐葜袷",
+	"qia4":    "恰洽髂",
+	"qian1":   "牵扦钎铅千迁签仟谦佥阡芊岍悭骞搴褰愆",
+	"qian2":   "乾黔钱钳前潜掮钤虔箝",
+	"qian3":   "遣浅谴凵缱肷",
+	"qian4":   "堑嵌欠歉倩芡茜慊椠",
+	"qiang1":  "枪呛腔羌戕戗锖锵镪蜣跄",
+	"qiang2":  "墙蔷强丬嫱樯",
+	"qiang3":  "抢襁羟",
+	"qiang4":  "炝",
+	"qiao1":   "橇锹敲悄劁缲硗跷",
+	"qiao2":   "桥瞧乔侨谯荞憔樵鞒",
+	"qiao3":   "巧愀",
+	"qiao4":   "鞘撬翘峭俏窍诮",
+	"qie3":    "且",
+	"qie4":    "切怯窃郄惬妾挈锲箧",
+	"qin1":    "钦侵亲衾",
+	"qin2":    "秦琴勤芹擒禽芩嗪噙溱檎螓",
+	"qin3":    "寝锓",
+	"qin4":    "沁揿吣",
+	"qing1":   "青轻氢倾卿清圊蜻鲭",
+	"qing2":   "擎晴氰情檠黥",
+	"qing3":   "顷请苘",
+	"qing4":   "庆磬罄箐謦",
+	"qiong1":  "芎",
+	"qiong2":  "琼穷邛茕穹蛩筇跫銎",
+	"qiu1":    "秋丘邱楸蚯鳅",
+	"qiu2":    "球求囚酋泅俅巯犰逑遒赇虬蝤裘鼽",
+	"qiu3":    "糗",
+	"qu1":     "趋区蛆曲躯屈驱诎岖祛蛐麴黢",
+	"qu2":     "渠劬蕖蘧衢璩氍朐磲鸲癯蠼瞿",
+	"qu3":     "取娶龋",
+	"qu4":     "趣去阒觑",
+	"quan1":   "圈悛",
+	"quan2":   "颧权醛泉全痊拳诠荃辁铨蜷筌鬈",
+	"quan3":   "犬犭绻畎",
+	"quan4":   "券劝",
+	"que1":    "缺阙",
+	"que2":    "瘸",
+	"que4":    "却鹊榷确雀阕悫",
+	"qun1":    "逡",
+	"qun2":    "裙群",
+	"ran2":    "然燃蚺髯",
+	"ran3":    "冉染苒",
+	"rang2":   "瓤禳穰",
+	"rang3":   "壤攘嚷",
+	"rang4":   "让",
+	"rao2":    "饶荛娆桡",
+	"rao3":    "扰",
+	"rao4":    "绕",
+	"re3":     "惹",
+	"re4":     "热",
+	"ren2":    "壬仁人亻",
+	"ren3":    "忍荏稔",
+	"ren4":    "韧任认刃妊纫仞葚饪轫衽",
+	"reng1":   "扔",
+	"reng2":   "仍",
+	"ri4":     "日",
+	"rong1":   "茸",
+	"rong2":   "戎蓉荣融熔溶容绒嵘狨榕肜蝾",
+	"rong3":   "冗",
+	"rou2":    "揉柔糅蹂鞣",
+	"rou4":    "肉",
+	"ru2":     "茹蠕儒孺如薷嚅濡铷襦颥",
+	"ru3":     "辱乳汝",
+	"ru4":     "入褥蓐洳溽缛",
+	"ruan3":   "软阮朊",
+	"rui2":    "蕤",
+	"rui3":    "蕊",
+	"rui4":    "瑞锐芮枘睿蚋",
+	"run4":    "闰润",
+	"ruo4":    "若弱偌箬",
+	"sa1":     "撒仨挲",
+	"sa3":     "洒",
+	"sa4":     "萨卅脎飒",
+	"sai1":    "腮鳃塞噻",
+	"sai4":    "赛",
+	"san1":    "三叁毵",
+	"san3":    "伞馓糁",
+	"san4":    "散",
+	"sang1":   "桑",
+	"sang3":   "嗓搡磉颡",
+	"sang4":   "丧",
+	"sao1":    "搔骚缫臊鳋",
+	"sao3":    "扫嫂",
+	"sao4":    "埽瘙",
+	"se4":     "瑟色涩啬铯穑",
+	"sen1":    "森",
+	"seng1":   "僧",
+	"sha1":    "莎砂杀刹沙纱煞铩痧裟鲨",
+	"sha3":    "傻",
+	"sha4":    "啥厦唼歃霎",
+	"shai1":   "筛酾",
+	"shai4":   "晒",
+	"shan1":   "珊苫杉山删煽衫埏芟彡潸姗膻钐舢跚",
+	"shan3":   "闪陕",
+	"shan4":   "擅赡膳善汕扇缮剡讪鄯嬗骟疝蟮鳝",
+	"shang1":  "墒伤商殇熵觞",
+	"shang3":  "赏晌垧",
+	"shang4":  "上尚绱",
+	"shang5":  "裳",
+	"shao1":   "梢捎稍烧蛸筲艄",
+	"shao2":   "芍勺韶苕",
+	"shao3":   "少",
+	"shao4":   "哨邵绍劭潲",
+	"she1":    "奢赊猞畲",
+	"she2":    "蛇舌佘",
+	"she3":    "舍",
+	"she4":    "赦摄射慑涉社设厍滠歙麝",
+	"shei2":   "谁",
+	"shen1":   "砷申呻伸身深娠绅诜莘",
+	"shen2":   "神甚什",
+	"shen3":   "沈审婶谂哂渖矧",
+	"shen4":   "肾慎渗椹胂蜃",
+	"sheng1":  "声生甥牲升笙",
+	"sheng2":  "绳",
+	"sheng3":  "省眚",
+	"sheng4":  "盛剩胜圣嵊",
+	"shi1":    "师失狮施湿诗尸虱蓍鲺",
+	"shi2":    "十石拾时食蚀实识埘莳饣炻鲥",
+	"shi3":    "史矢使屎驶始豕",
+	"shi4":    "式示士世柿事拭誓逝势是嗜噬适仕侍释饰氏市恃室视试似谥弑轼贳礻铈螫舐筮豉",
+	"shi5":    "匙",
+	"shou1":   "收",
+	"shou3":   "手首守艏",
+	"shou4":   "寿授售受瘦兽狩绶",
+	"shou5":   "扌",
+	"shu1":    "蔬枢梳殊抒输叔舒淑疏书倏菽摅姝纾毹殳",
+	"shu2":    "赎孰熟塾秫",
+	"shu3":    "薯暑曙署蜀黍鼠属",
+	"shu4":    "术述树束戍竖墅庶数漱恕沭澍腧",
+	"shua1":   "刷唰",
+	"shua3":   "耍",
+	"shuai1":  "摔衰",
+	"shuai3":  "甩",
+	"shuai4":  "帅蟀",
+	"shuan1":  "栓拴闩",
+	"shuan4":  "涮",
+	"shuang1": "霜双孀",
+	"shuang3": "爽",
+	"shui3":   "水",
+	"shui4":   "睡税",
+	"shui5":   "氵",
+	"shun3":   "吮",
+	"shun4":   "瞬顺舜",
+	"shuo1":   "说",
+	"shuo4":   "硕朔烁蒴搠妁槊铄",
+	"si1":     "斯撕嘶思私司丝厮厶咝澌纟缌锶鸶蛳",
+	"si3":     "死",
+	"si4":     "肆寺嗣四饲巳兕汜泗姒驷祀耜笥",
+	"song1":   "松凇菘崧嵩忪淞",
+	"song3":   "耸怂悚竦",
+	"song4":   "颂送宋讼诵",
+	"sou1":    "搜艘嗖馊溲飕锼螋",
+	"sou3":    "擞叟薮嗾瞍",
+	"sou4":    "嗽",
+	"su1":     "苏酥稣",
+	"su2":     "俗",
+	"su4":     "素速粟僳塑溯宿诉肃夙谡蔌嗉愫涑簌觫",
+	"suan1":   "酸狻",
+	"suan4":   "蒜算",
+	"sui1":    "虽荽濉眭睢",
+	"sui2":    "隋随绥",
+	"sui3":    "髓",
+	"sui4":    "碎岁穗遂隧祟谇邃燧",
+	"sun1":    "孙荪狲飧",
+	"sun3":    "损笋榫隼",
+	"suo1":    "蓑梭唆缩嗍娑桫睃羧",
+	"suo3":    "琐索锁所唢",
+	"suo5":    "嗦",
+	"ta1":     "塌他它她溻铊趿",
+	"ta3":     "塔獭鳎",
+	"ta4":     "挞蹋踏拓闼遢榻",
+	"tai1":    "胎",
+	"tai2":    "苔抬台邰薹炱跆鲐",
+	"tai4":    "泰酞太态汰肽钛",
+	"tan1":    "坍摊贪瘫滩",
+	"tan2":    "坛檀痰潭谭谈郯昙锬覃",
+	"tan3":    "坦毯袒忐钽",
+	"tan4":    "碳探叹炭",
+	"tang1":   "汤铴镗耥羰",
+	"tang2":   "塘搪堂棠膛唐糖饧溏瑭樘螗螳醣",
+	"tang3":   "倘躺淌傥帑",
+	"tang4":   "趟烫",
+	"tao1":    "掏涛滔绦韬饕",
+	"tao2":    "萄桃逃淘陶鼗啕洮",
+	"tao3":    "讨",
+	"tao4":    "套",
+	"te4":     "特忒忑慝铽",
+	"teng2":   "藤腾疼誊滕",
+	"ti1":     "梯剔踢锑",
+	"ti2":     "提题蹄啼荑绨缇鹈醍",
+	"ti3":     "体",
+	"ti4":     "替嚏惕涕剃屉倜悌逖裼",
+	"tian1":   "天添",
+	"tian2":   "填田甜恬阗畋",
+	"tian3":   "舔腆忝殄",
+	"tian4":   "掭",
+	"tiao1":   "挑佻祧",
+	"tiao2":   "条迢蜩笤龆鲦髫",
+	"tiao3":   "窕",
+	"tiao4":   "眺跳粜",
+	"tie1":    "贴帖萜",
+	"tie3":    "铁",
+	"tie4":    "餮",
+	"ting1":   "厅听烃汀町",
+	"ting2":   "廷停亭庭莛葶婷蜓霆",
+	"ting3":   "挺艇梃",
+	"tong1":   "通嗵",
+	"tong2":   "桐酮瞳同铜彤童佟僮仝茼潼砼",
+	"tong3":   "桶捅筒统",
+	"tong4":   "痛恸",
+	"tou1":    "偷",
+	"tou2":    "投头亠骰",
+	"tou3":    "钭",
+	"tou4":    "透",
+	"tu1":     "凸秃突",
+	"tu2":     "图徒途涂屠荼菟酴",
+	"tu3":     "土吐钍",
+	"tu4":     "兔堍",
+	"tuan1":   "湍",
+	"tuan2":   "团抟",
+	"tuan3":   "疃",
+	"tuan4":   "彖",
+	"tui1":    "推",
+	"tui2":    "颓",
+	"tui3":    "腿",
+	"tui4":    "蜕褪退煺",
+	"tun1":    "吞暾",
+	"tun2":    "屯臀饨豚",
+	"tun3":    "氽",
+	"tuo1":    "拖托脱乇",
+	"tuo2":    "鸵陀驮驼佗坨沲沱橐砣酡跎鼍",
+	"tuo3":    "椭妥庹",
+	"tuo4":    "唾柝箨",
+	"wa1":     "挖蛙洼娲",
+	"wa2":     "娃",
+	"wa3":     "瓦佤",
+	"wa4":     "袜腽",
+	"wa5":     "哇",
+	"wai1":    "歪",
+	"wai3":    "崴",
+	"wai4":    "外",
+	"wan1":    "豌弯湾剜蜿",
+	"wan2":    "玩顽丸烷完芄纨",
+	"wan3":    "碗挽晚皖惋宛婉菀绾琬脘畹",
+	"wan4":    "万腕",
+	"wang1":   "汪",
+	"wang2":   "王亡",
+	"wang3":   "枉网往罔惘辋魍",
+	"wang4":   "旺望忘妄",
+	"wei1":    "威巍微危萎偎隈葳薇逶煨",
+	"wei2":    "韦违桅围唯惟潍维圩囗帏帷嵬闱沩涠",
+	"wei3":    "苇委伟伪尾纬诿猥洧娓玮韪炜痿艉鲔",
+	"wei4":    "为未蔚味畏胃喂魏位渭谓尉慰卫猬軎",
+	"wen1":    "瘟温",
+	"wen2":    "蚊文闻纹阌玟雯",
+	"wen3":    "吻稳紊刎",
+	"wen4":    "问汶璺",
+	"weng1":   "嗡翁",
+	"weng3":   "蓊",
+	"weng4":   "瓮蕹",
+	"wo1":     "挝蜗涡窝倭莴",
+	"wo3":     "我",
+	"wo4":     "斡卧握沃幄渥肟硪龌",
+	"wu1":     "巫呜钨乌污诬屋邬圬",
+	"wu2":     "无芜梧吾吴毋唔浯蜈鼯",
+	"wu3":     "武五捂午舞伍侮仵庑怃忤妩牾鹉",
+	"wu4":     "坞戊雾晤物勿务悟误兀阢芴寤迕婺骛杌焐鹜痦鋈",
+	"xi1":     "昔熙析西硒晰嘻吸锡牺稀息希悉膝夕惜熄烯溪汐犀僖兮郗菥奚唏浠淅嬉樨曦欷熹皙穸蜥螅蟋舾羲粞翕醯鼷",
+	"xi2":     "檄袭席习媳隰觋",
+	"xi3":     "喜铣洗葸蓰徙屣玺禧",
+	"xi4":     "矽系隙戏细饩阋禊舄",
+	"xia1":    "瞎虾",
+	"xia2":    "匣霞辖暇峡侠狭狎遐瑕柙硖黠",
+	"xia4":    "下夏吓罅",
+	"xian1":   "掀锨先仙鲜纤莶暹氙祆籼酰跹",
+	"xian2":   "咸贤衔舷闲涎弦嫌娴鹇痫",
+	"xian3":   "显险冼藓猃燹蚬筅跣",
+	"xian4":   "现献县腺馅羡宪陷限线苋岘霰",
+	"xiang1":  "相厢镶香箱襄湘乡芗葙骧缃",
+	"xiang2":  "翔祥详庠",
+	"xiang3":  "想响享饷鲞飨",
+	"xiang4":  "项巷橡像向象蟓",
+	"xiao1":   "萧硝霄哮嚣销消宵哓潇逍骁绡枭枵箫魈",
+	"xiao2":   "淆崤",
+	"xiao3":   "晓小筱",
+	"xiao4":   "孝校肖啸笑效",
+	"xie1":    "楔些歇蝎",
+	"xie2":    "鞋协挟携邪斜胁谐偕勰撷缬",
+	"xie3":    "写",
+	"xie4":    "械卸蟹懈泄泻谢屑亵燮薤獬廨渫瀣邂绁榭榍躞",
+	"xin1":    "薪芯锌欣辛新忻心馨昕歆鑫",
+	"xin4":    "信衅囟",
+	"xin5":    "忄",
+	"xing1":   "星腥猩惺",
+	"xing2":   "刑型形邢行陉荥硎",
+	"xing3":   "醒擤",
+	"xing4":   "兴幸杏性姓荇悻",
+	"xiong1":  "兄凶胸匈汹",
+	"xiong2":  "雄熊",
+	"xiu1":    "休修羞咻馐庥鸺貅髹",
+	"xiu3":    "朽",
+	"xiu4":    "嗅锈秀袖绣岫溴",
+	"xu1":     "墟戌需虚嘘须吁顼盱胥",
+	"xu2":     "徐",
+	"xu3":     "许诩栩糈醑",
+	"xu4":     "蓄酗叙旭序恤絮婿绪续勖洫溆煦",
+	"xu5":     "蓿",
+	"xuan1":   "轩喧宣儇谖萱揎暄煊",
+	"xuan2":   "悬旋玄漩璇痃",
+	"xuan3":   "选癣",
+	"xuan4":   "眩绚泫渲楦炫碹铉镟",
+	"xue1":    "削靴薛",
+	"xue2":    "学穴泶踅",
+	"xue3":    "雪鳕",
+	"xue4":    "血谑",
+	"xun1":    "勋熏埙薰獯曛窨醺",
+	"xun2":    "循旬询寻驯巡荀荨峋恂洵浔鲟",
+	"xun4":    "殉汛训讯逊迅巽蕈徇",
+	"ya1":     "压押鸦鸭丫垭吖桠",
+	"ya2":     "芽牙蚜崖衙涯伢岈琊睚",
+	"ya3":     "雅哑痖",
+	"ya4":     "亚讶轧揠迓娅氩砑",
+	"ya5":     "呀",
+	"yan1":    "焉阉烟淹鄢菸崦恹湮嫣胭腌",
+	"yan2":    "盐严研蜒岩延言颜阎炎沿讠芫闫妍檐筵",
+	"yan3":    "奄掩眼衍演厣俨偃兖郾琰罨魇鼹",
+	"yan4":    "咽艳堰燕厌砚雁唁彦焰宴谚验赝谳滟晏焱酽餍",
+	"yang1":   "殃央鸯秧泱鞅",
+	"yang2":   "杨扬佯疡羊洋阳徉炀烊蛘",
+	"yang3":   "氧仰痒养",
+	"yang4":   "样漾怏恙",
+	"yao1":    "邀腰妖夭吆幺",
+	"yao2":    "瑶摇尧遥窑谣姚爻徭珧轺肴繇鳐",
+	"yao3":    "咬舀崾杳窈",
+	"yao4":    "药要耀钥曜鹞",
+	"ye1":     "椰噎掖",
+	"ye2":     "耶爷揶铘",
+	"ye3":     "野冶也",
+	"ye4":     "页业叶曳腋夜液靥谒邺晔烨",
+	"yi1":     "一壹医揖铱依伊衣咿噫猗漪欹衤黟",
+	"yi2":     "颐夷遗移仪胰疑沂宜姨彝诒圯咦嶷饴怡迤贻眙痍",
+	"yi3":     "椅蚁倚已乙矣以苡旖钇舣酏",
+	"yi4":     "艺抑易邑屹亿役臆逸肄疫亦裔意毅忆义益溢诣议谊译异翼翌绎刈劓佚佾埸懿薏弈奕挹弋呓峄怿悒驿缢殪轶熠镒镱瘗癔翊蜴羿翳",
+	"yin1":    "茵荫因殷音阴姻堙喑洇氤铟",
+	"yin2":    "吟银淫寅鄞垠狺夤霪",
+	"yin3":    "饮尹引隐廴吲瘾蚓",
+	"yin4":    "印胤茚",
+	"ying1":   "英樱婴鹰应缨莺撄嘤膺瑛璎鹦罂",
+	"ying2":   "莹萤营荧蝇迎赢盈嬴茔萦蓥滢潆瀛楹",
+	"ying3":   "影颖郢瘿颍",
+	"ying4":   "硬映媵",
+	"yo1":     "哟唷",
+	"yong1":   "拥佣臃痈庸雍壅墉慵邕镛鳙饔",
+	"yong2":   "喁",
+	"yong3":   "踊蛹咏泳涌永恿勇俑甬",
+	"yong4":   "用",
+	"you1":    "幽优悠忧攸呦",
+	"you2":    "尤由邮铀犹油游莜莸尢猷疣蚰蝣鱿",
+	"you3":    "酉有友卣莠牖铕黝",
+	"you4":    "右佑釉诱又幼侑囿宥柚蚴鼬",
+	"yu1":     "迂淤纡瘀",
+	"yu2":     "于盂榆虞愚舆余俞逾鱼愉渝渔隅娱禺谀萸揄嵛狳馀妤瑜觎腴欤於窬蝓竽臾舁雩",
+	"yu3":     "予雨与屿禹宇语羽伛俣圄圉庾瘐窳龉",
+	"yu4":     "玉域芋郁遇喻峪御愈欲狱育誉浴寓裕预豫驭毓谕蓣饫阈鬻妪昱煜燠肀聿钰鹆鹬蜮",
+	"yuan1":   "鸳渊冤眢鸢箢",
+	"yuan2":   "元垣袁原援辕园员圆猿源缘塬沅橼爰螈鼋",
+	"yuan3":   "远",
+	"yuan4":   "苑愿怨院垸掾媛瑗",
+	"yue1":    "曰约",
+	"yue4":    "越跃岳粤月悦阅龠瀹樾刖钺",
+	"yun1":    "晕氲",
+	"yun2":    "耘云郧匀芸纭昀筠",
+	"yun3":    "陨允狁殒",
+	"yun4":    "运蕴酝韵孕郓恽愠韫熨",
+	"za1":     "匝拶咂",
+	"za2":     "砸杂",
+	"za3":     "咋",
+	"zai1":    "栽哉灾甾",
+	"zai3":    "宰崽",
+	"zai4":    "载再在",
+	"zan1":    "簪糌",
+	"zan2":    "咱",
+	"zan3":    "攒昝趱",
+	"zan4":    "暂赞瓒錾",
+	"zang1":   "赃臧",
+	"zang3":   "驵",
+	"zang4":   "脏葬奘",
+	"zao1":    "遭糟",
+	"zao2":    "凿",
+	"zao3":    "藻枣早澡蚤",
+	"zao4":    "躁噪造皂灶燥唣",
+	"ze2":     "责择则泽赜啧帻迮笮箦舴",
+	"ze4":     "仄昃",
+	"zei2":    "贼",
+	"zen3":    "怎",
+	"zen4":    "谮",
+	"zeng1":   "增憎缯罾",
+	"zeng4":   "赠甑锃",
+	"zha1":    "扎喳渣揸吒哳楂齄",
+	"zha2":    "札铡闸",
+	"zha3":    "眨砟",
+	"zha4":    "栅榨乍炸诈柞咤痄蚱",
+	"zhai1":   "摘斋",
+	"zhai2":   "宅",
+	"zhai3":   "窄",
+	"zhai4":   "债寨砦瘵",
+	"zhan1":   "瞻毡詹粘沾谵旃",
+	"zhan3":   "盏斩崭展搌",
+	"zhan4":   "蘸栈占战站湛绽",
+	"zhang1":  "樟章彰漳张鄣獐嫜璋蟑",
+	"zhang3":  "长掌涨仉",
+	"zhang4":  "杖丈帐账仗胀瘴障幛嶂",
+	"zhao1":   "招昭啁钊",
+	"zhao3":   "找沼爪",
+	"zhao4":   "赵照罩兆肇召诏棹笊",
+	"zhe1":    "遮蜇",
+	"zhe2":    "折哲蛰辙谪摺辄磔",
+	"zhe3":    "者锗褶赭",
+	"zhe4":    "蔗这浙柘鹧",
+	"zhe5":    "著着",
+	"zhen1":   "珍斟真甄砧臻贞针侦蓁浈桢榛胗祯箴",
+	"zhen3":   "枕疹诊缜轸畛稹",
+	"zhen4":   "震振镇阵圳赈朕鸩",
+	"zheng1":  "蒸挣睁征狰争怔诤峥钲铮筝",
+	"zheng3":  "整拯",
+	"zheng4":  "正政帧症郑证",
+	"zhi1":    "芝枝支吱蜘知肢脂汁之织卮栀胝祗",
+	"zhi2":    "职直植殖执值侄埴摭絷跖踯",
+	"zhi3":    "址指止趾只旨纸芷徵夂咫枳轵祉黹酯",
+	"zhi4":    "志挚掷至致置帜峙制智秩稚质炙痔滞治窒陟郅帙忮彘骘栉桎轾贽膣雉鸷痣蛭踬豸觯",
+	"zhong1":  "中盅忠钟衷终锺螽舯",
+	"zhong3":  "种肿冢踵",
+	"zhong4":  "重仲众",
+	"zhou1":   "舟周州洲诌粥",
+	"zhou2":   "轴妯",
+	"zhou3":   "肘帚",
+	"zhou4":   "咒皱宙昼骤荮纣绉胄籀酎",
+	"zhu1":    "珠株蛛朱猪诸诛侏邾茱洙潴槠橥铢",
+	"zhu2":    "逐竹烛瘃竺舳躅",
+	"zhu3":    "煮拄瞩嘱主丶渚麈",
+	"zhu4":    "柱助蛀贮铸筑住注祝驻伫苎杼炷疰箸翥",
+	"zhua1":   "抓",
+	"zhuai1":  "拽",
+	"zhuan1":  "专砖颛",
+	"zhuan3":  "转",
+	"zhuan4":  "撰赚篆啭馔",
+	"zhuang1": "桩庄装妆",
+	"zhuang4": "撞壮状",
+	"zhui1":   "锥追骓隹",
+	"zhui4":   "赘坠缀惴缒",
+	"zhun1":   "谆肫窀",
+	"zhun3":   "准",
+	"zhuo1":   "捉拙卓桌倬涿",
+	"zhuo2":   "茁酌啄灼浊诼擢浞濯禚斫镯",
+	"zi1":     "兹咨资姿滋淄孜谘嵫孳缁辎赀锱粢趑觜訾龇鲻髭",
+	"zi3":     "紫仔籽滓姊梓秭耔笫",
+	"zi4":     "自渍字恣眦",
+	"zi5":     "子",
+	"zong1":   "鬃棕踪宗综腙",
+	"zong3":   "总偬",
+	"zong4":   "纵粽",
+	"zou1":    "邹诹陬鄹驺鲰",
+	"zou3":    "走",
+	"zou4":    "奏揍楱",
+	"zu1":     "租",
+	"zu2":     "足卒族镞",
+	"zu3":     "祖诅阻组俎",
+	"zuan1":   "钻躜",
+	"zuan3":   "纂缵",
+	"zuan4":   "攥",
+	"zui3":    "嘴",
+	"zui4":    "醉最罪蕞",
+	"zun1":    "尊遵樽鳟",
+	"zun3":    "撙",
+	"zuo2":    "琢昨",
+	"zuo3":    "左佐",
+	"zuo4":    "做作坐座阼唑怍胙祚",
+}
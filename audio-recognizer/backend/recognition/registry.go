@@ -0,0 +1,164 @@
+package recognition
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"tingshengbianzi/backend/models"
+)
+
+// EngineFactory 识别引擎工厂函数
+type EngineFactory func(config *models.RecognitionConfig) (RecognitionService, error)
+
+// EngineCapabilities 识别引擎能力
+type EngineCapabilities struct {
+	Languages      []string `json:"languages"`      // 支持的语言代码
+	WordTimestamps bool     `json:"wordTimestamps"` // 是否提供词级时间戳
+	Diarization    bool     `json:"diarization"`    // 是否支持说话人分离
+	Streaming      bool     `json:"streaming"`      // 是否实时输出识别段落
+	Translation    bool     `json:"translation"`    // 是否支持翻译为英文（translate/bilingual任务）
+}
+
+// EngineConfigField 引擎配置项说明（供前端生成设置表单）
+type EngineConfigField struct {
+	Key         string      `json:"key"`                   // 对应RecognitionConfig中的JSON字段名
+	Type        string      `json:"type"`                  // string | number | boolean | path
+	Label       string      `json:"label"`                 // 显示名称
+	Description string      `json:"description,omitempty"` // 说明
+	Default     interface{} `json:"default,omitempty"`     // 默认值
+	Required    bool        `json:"required"`              // 是否必填
+}
+
+// EngineDescriptor 识别引擎描述
+type EngineDescriptor struct {
+	Name         string              `json:"name"`         // 引擎标识，用于配置和请求中的engine字段
+	DisplayName  string              `json:"displayName"`  // 显示名称
+	Description  string              `json:"description"`  // 说明
+	Priority     int                 `json:"priority"`     // 自动选择时的优先级，越大越优先
+	Capabilities EngineCapabilities  `json:"capabilities"` // 引擎能力
+	ConfigSchema []EngineConfigField `json:"configSchema"` // 引擎使用的配置项
+	Factory      EngineFactory       `json:"-"`            // 创建引擎实例
+	Available    func() bool         `json:"-"`            // 检查引擎依赖是否就绪（可选）
+}
+
+// EngineInfo 引擎信息（含当前可用状态）
+type EngineInfo struct {
+	EngineDescriptor
+	Available bool `json:"available"`
+}
+
+var (
+	enginesMu sync.RWMutex
+	engines   = make(map[string]EngineDescriptor)
+)
+
+// RegisterEngine 注册识别引擎，通常在引擎实现文件的init中调用
+// 名称为空、缺少工厂函数或重复注册属于编程错误，直接panic
+func RegisterEngine(descriptor EngineDescriptor) {
+	if descriptor.Name == "" {
+		panic("recognition: 注册的引擎名称不能为空")
+	}
+	if descriptor.Factory == nil {
+		panic(fmt.Sprintf("recognition: 引擎 %s 缺少工厂函数", descriptor.Name))
+	}
+
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+
+	if _, exists := engines[descriptor.Name]; exists {
+		panic(fmt.Sprintf("recognition: 引擎 %s 重复注册", descriptor.Name))
+	}
+	engines[descriptor.Name] = descriptor
+}
+
+// GetEngine 获取已注册的引擎
+func GetEngine(name string) (EngineDescriptor, bool) {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	descriptor, ok := engines[name]
+	return descriptor, ok
+}
+
+// ListEngines 列出所有已注册的引擎，按优先级从高到低排序
+func ListEngines() []EngineInfo {
+	enginesMu.RLock()
+	list := make([]EngineInfo, 0, len(engines))
+	for _, descriptor := range engines {
+		list = append(list, EngineInfo{EngineDescriptor: descriptor})
+	}
+	enginesMu.RUnlock()
+
+	for i := range list {
+		list[i].Available = isEngineAvailable(list[i].EngineDescriptor)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Priority != list[j].Priority {
+			return list[i].Priority > list[j].Priority
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// NewEngine 创建指定引擎的实例，name为空时自动选择
+// 返回实际使用的引擎名称
+func NewEngine(name string, config *models.RecognitionConfig) (RecognitionService, string, error) {
+	if name != "" {
+		descriptor, ok := GetEngine(name)
+		if !ok {
+			return nil, "", fmt.Errorf("未知的识别引擎: %s", name)
+		}
+		service, err := descriptor.Factory(config)
+		if err != nil {
+			return nil, "", fmt.Errorf("创建识别引擎 %s 失败: %w", name, err)
+		}
+		return service, name, nil
+	}
+
+	// 自动选择：按优先级依次尝试可用的引擎
+	var lastErr error
+	for _, info := range ListEngines() {
+		if !info.Available {
+			continue
+		}
+		service, err := info.Factory(config)
+		if err != nil {
+			fmt.Printf("⚠️ 识别引擎 %s 初始化失败，尝试下一个: %v\n", info.Name, err)
+			lastErr = err
+			continue
+		}
+		return service, info.Name, nil
+	}
+
+	if lastErr != nil {
+		return nil, "", fmt.Errorf("没有可用的识别引擎: %w", lastErr)
+	}
+	return nil, "", fmt.Errorf("没有可用的识别引擎")
+}
+
+// isEngineAvailable 检查引擎是否可用，未提供检查函数时视为可用
+func isEngineAvailable(descriptor EngineDescriptor) bool {
+	if descriptor.Available == nil {
+		return true
+	}
+	return descriptor.Available()
+}
+
+// whisperConfigSchema Whisper系列引擎共用的配置项
+var whisperConfigSchema = []EngineConfigField{
+	{Key: "language", Type: "string", Label: "识别语言", Description: "BCP-47语言代码，auto为自动检测", Default: "zh-CN"},
+	{Key: "modelPath", Type: "path", Label: "模型目录", Description: "存放ggml格式Whisper模型(.bin)的目录", Required: true},
+	{Key: "specificModelFile", Type: "path", Label: "模型文件", Description: "指定使用的模型文件，留空时按质量自动选择"},
+}
+
+// languageCodes 获取语言映射表中的全部语言代码
+func languageCodes() []string {
+	codes := make([]string, 0, len(whisperLanguageTable))
+	for _, lang := range whisperLanguageTable {
+		codes = append(codes, lang.Code)
+	}
+	return codes
+}
@@ -0,0 +1,214 @@
+package recognition
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"tingshengbianzi/backend/models"
+)
+
+// DefaultRerecognizePadding 重新识别时段落前后扩展的时长(秒)，给模型留出上下文，避免切掉首尾的字
+const DefaultRerecognizePadding = 0.5
+
+// maxRerecognizePadding 段落前后扩展时长的上限(秒)
+const maxRerecognizePadding = 10.0
+
+// rerecognizeGroup 一组连续的待重新识别段落
+type rerecognizeGroup struct {
+	first, last int              // 段落序号范围（含）
+	window      models.TimeRange // 新段落的中点落在该窗口内才替换进来，窗口不与相邻的保留段落重叠
+}
+
+// RerecognizePlan 重新识别计划：需要截取的时间范围和替换位置
+type RerecognizePlan struct {
+	TimeRanges []models.TimeRange // 截取识别的时间范围（已扩展，传给识别选项的timeRanges）
+	Padding    float64
+	groups     []rerecognizeGroup
+}
+
+// PlanRerecognition 根据段落序号生成重新识别计划，连续的段落合并为一组一起识别
+func PlanRerecognition(result *models.RecognitionResult, indexes []int, padding float64) (*RerecognizePlan, error) {
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("没有指定要重新识别的段落")
+	}
+	if padding < 0 || padding > maxRerecognizePadding {
+		return nil, fmt.Errorf("扩展时长超出范围(0-%.0f秒): %.2f", maxRerecognizePadding, padding)
+	}
+
+	sorted := append([]int(nil), indexes...)
+	sort.Ints(sorted)
+	segments := result.Segments
+	plan := &RerecognizePlan{Padding: padding}
+	for i, index := range sorted {
+		if index < 0 || index >= len(segments) {
+			return nil, fmt.Errorf("段落序号超出范围: %d（共 %d 段）", index, len(segments))
+		}
+		if i > 0 && index == sorted[i-1] {
+			continue
+		}
+		if n := len(plan.groups); n > 0 && plan.groups[n-1].last == index-1 {
+			plan.groups[n-1].last = index
+			continue
+		}
+		plan.groups = append(plan.groups, rerecognizeGroup{first: index, last: index})
+	}
+
+	for i := range plan.groups {
+		group := &plan.groups[i]
+		start, end := segments[group.first].Start, segments[group.last].End
+		r := models.TimeRange{Start: math.Max(0, start-padding), End: end + padding}
+		if result.Duration > 0 {
+			r.End = math.Min(r.End, result.Duration)
+		}
+		if r.End <= r.Start {
+			return nil, fmt.Errorf("段落 %d 的时间无效: %.3f-%.3f", group.first, start, end)
+		}
+		plan.TimeRanges = append(plan.TimeRanges, r)
+
+		// 扩展部分可能含有相邻段落的语音，替换窗口只延伸到相邻段落的边界
+		group.window = r
+		if group.first > 0 {
+			group.window.Start = math.Max(r.Start, math.Min(segments[group.first-1].End, start))
+		}
+		if group.last < len(segments)-1 {
+			group.window.End = math.Min(r.End, math.Max(segments[group.last+1].Start, end))
+		}
+	}
+	return plan, nil
+}
+
+// SpliceRerecognition 将重新识别的段落替换进识别结果，返回新的结果（原结果不修改，用于撤销）和本次的记录
+// 没有识别出文本的段落保留原文
+func SpliceRerecognition(result *models.RecognitionResult, plan *RerecognizePlan, replacement *models.RecognitionResult, modelFile string) (*models.RecognitionResult, *models.RerecognitionRecord, error) {
+	record := &models.RerecognitionRecord{
+		ModelFile: modelFile,
+		Padding:   plan.Padding,
+		At:        time.Now(),
+	}
+
+	var segments []models.RecognitionResultSegment
+	next := 0
+	for _, group := range plan.groups {
+		for ; next < group.first; next++ {
+			segments = append(segments, shiftSegment(result.Segments[next], 0))
+		}
+		next = group.last + 1
+		old := result.Segments[group.first:next]
+
+		var fresh []models.RecognitionResultSegment
+		for _, segment := range replacement.Segments {
+			middle := (segment.Start + segment.End) / 2
+			if middle < group.window.Start || middle >= group.window.End {
+				continue
+			}
+			fresh = append(fresh, spliceSegment(segment, group.window, old, modelFile))
+		}
+
+		indexes := make([]int, 0, len(old))
+		for i := group.first; i < next; i++ {
+			indexes = append(indexes, i)
+		}
+		if len(fresh) == 0 {
+			for _, segment := range old {
+				segments = append(segments, shiftSegment(segment, 0))
+			}
+			record.Unchanged = append(record.Unchanged, indexes)
+			continue
+		}
+		segments = append(segments, fresh...)
+		record.Revisions = append(record.Revisions, models.SegmentRevision{
+			Indexes:  indexes,
+			Start:    old[0].Start,
+			End:      old[len(old)-1].End,
+			OldText:  joinSegmentText(old),
+			NewText:  joinSegmentText(fresh),
+			NewCount: len(fresh),
+		})
+	}
+	for ; next < len(result.Segments); next++ {
+		segments = append(segments, shiftSegment(result.Segments[next], 0))
+	}
+
+	if len(record.Revisions) == 0 {
+		return nil, record, fmt.Errorf("重新识别没有得到新的文本，已保留原识别结果")
+	}
+
+	updated := *result
+	updated.Segments = segments
+	updated.Metadata = make(map[string]interface{}, len(result.Metadata)+1)
+	for key, value := range result.Metadata {
+		updated.Metadata[key] = value
+	}
+	history, _ := updated.Metadata["rerecognitions"].([]models.RerecognitionRecord)
+	updated.Metadata["rerecognitions"] = append(append([]models.RerecognitionRecord(nil), history...), *record)
+	refreshResultText(&updated)
+	return &updated, record, nil
+}
+
+// spliceSegment 将新段落限制在替换窗口内，未分离说话人时沿用时间重叠最多的原段落的说话人
+func spliceSegment(segment models.RecognitionResultSegment, window models.TimeRange, old []models.RecognitionResultSegment, modelFile string) models.RecognitionResultSegment {
+	segment = shiftSegment(segment, 0)
+	segment.Start = math.Max(segment.Start, window.Start)
+	segment.End = math.Min(segment.End, window.End)
+	for i := range segment.Words {
+		word := &segment.Words[i]
+		word.Start = math.Min(math.Max(word.Start, window.Start), window.End)
+		word.End = math.Min(math.Max(word.End, word.Start), window.End)
+	}
+
+	if segment.Speaker == "" {
+		best := 0.0
+		for _, previous := range old {
+			if overlap := math.Min(segment.End, previous.End) - math.Max(segment.Start, previous.Start); previous.Speaker != "" && overlap > best {
+				best = overlap
+				segment.Speaker = previous.Speaker
+			}
+		}
+		if segment.Speaker == "" && len(old) > 0 {
+			segment.Speaker = old[0].Speaker
+		}
+		for i := range segment.Words {
+			segment.Words[i].Speaker = segment.Speaker
+		}
+	}
+
+	segment.Metadata["rerecognized"] = true
+	segment.Metadata["rerecognized_model"] = modelFile
+	return segment
+}
+
+// joinSegmentText 连接段落文本
+func joinSegmentText(segments []models.RecognitionResultSegment) string {
+	texts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		texts = append(texts, segment.Text)
+	}
+	return strings.Join(texts, " ")
+}
+
+// refreshResultText 段落变化后重新生成识别结果的全文、词汇、置信度和说话人信息
+func refreshResultText(result *models.RecognitionResult) {
+	words := []models.Word{}
+	hasSpeakers := false
+	for _, segment := range result.Segments {
+		words = append(words, segment.Words...)
+		if segment.Speaker != "" {
+			hasSpeakers = true
+		}
+	}
+
+	result.Text = (&WhisperService{}).addTimestampsToText(result.Segments, result.Duration)
+	result.TimestampedText = result.Text
+	result.Words = words
+	if len(words) > 0 {
+		result.Confidence = averageWordConfidence(words)
+	}
+	result.Metadata["total_words"] = len(words)
+	result.Metadata["total_segments"] = len(result.Segments)
+	if hasSpeakers {
+		refreshSpeakerMetadata(result)
+	}
+}
@@ -1,17 +1,24 @@
 package recognition
 
-import "tingshengbianzi/backend/models"
+import (
+	"context"
+
+	"tingshengbianzi/backend/models"
+)
 
 // RecognitionService 语音识别服务接口
 type RecognitionService interface {
 	// LoadModel 加载语音模型
 	LoadModel(language, modelPath string) error
 
-	// RecognizeFile 识别音频文件
-	RecognizeFile(audioPath string, language string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error)
+	// RecognizeFile 识别音频文件，ctx取消时应终止外部进程并返回取消错误
+	RecognizeFile(ctx context.Context, audioPath string, language string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error)
 
 	// RecognizeFileWithModel 使用指定模型文件识别音频文件
-	RecognizeFileWithModel(audioPath string, language string, specificModelFile string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error)
+	RecognizeFileWithModel(ctx context.Context, audioPath string, language string, specificModelFile string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error)
+
+	// RecognizeFileWithOptions 按识别选项（任务类型、指定模型等）识别音频文件
+	RecognizeFileWithOptions(ctx context.Context, audioPath string, language string, options models.RecognitionOptions, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error)
 
 	// GetSupportedLanguages 获取支持的语言列表
 	GetSupportedLanguages() []string
@@ -0,0 +1,100 @@
+package recognition
+
+import (
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+)
+
+// timeRangeMapper 将截取后音频上的时间换算回原始文件的时间
+// 截取后的音频由各时间范围依次拼接而成，相邻范围之间有 audio.TimeRangeGap 秒静音
+type timeRangeMapper struct {
+	ranges  []models.TimeRange
+	offsets []float64 // 每个范围在截取后音频中的开始时间
+}
+
+// newTimeRangeMapper 创建时间换算器，未截取时间范围时返回nil
+func newTimeRangeMapper(ranges []models.TimeRange) *timeRangeMapper {
+	if len(ranges) == 0 {
+		return nil
+	}
+	offsets := make([]float64, len(ranges))
+	position := 0.0
+	for i, r := range ranges {
+		offsets[i] = position
+		position += r.Duration() + audio.TimeRangeGap
+	}
+	return &timeRangeMapper{ranges: ranges, offsets: offsets}
+}
+
+// mapTime 换算单个时间点，落在范围之间静音里的时间归到前一个范围的结束时间
+func (m *timeRangeMapper) mapTime(t float64) float64 {
+	index := 0
+	for i := len(m.offsets) - 1; i > 0; i-- {
+		if t >= m.offsets[i] {
+			index = i
+			break
+		}
+	}
+	r := m.ranges[index]
+	local := t - m.offsets[index]
+	if local < 0 {
+		local = 0
+	}
+	if local > r.Duration() {
+		local = r.Duration()
+	}
+	return r.Start + local
+}
+
+// mapSegments 换算段落和其中词汇的时间
+func (m *timeRangeMapper) mapSegments(segments []models.RecognitionResultSegment) {
+	if m == nil {
+		return
+	}
+	for i := range segments {
+		segment := &segments[i]
+		segment.Start = m.mapTime(segment.Start)
+		segment.End = m.mapTime(segment.End)
+		for j := range segment.Words {
+			segment.Words[j].Start = m.mapTime(segment.Words[j].Start)
+			segment.Words[j].End = m.mapTime(segment.Words[j].End)
+		}
+	}
+}
+
+// mapReport 换算幻觉检测中被删除段落的时间
+func (m *timeRangeMapper) mapReport(report *hallucinationReport) {
+	if m == nil || report == nil {
+		return
+	}
+	for i := range report.removed {
+		segment := &report.removed[i].RecognitionResultSegment
+		segment.Start = m.mapTime(segment.Start)
+		segment.End = m.mapTime(segment.End)
+		for j := range segment.Words {
+			segment.Words[j].Start = m.mapTime(segment.Words[j].Start)
+			segment.Words[j].End = m.mapTime(segment.Words[j].End)
+		}
+	}
+}
+
+// apply 将识别的时间范围写入识别结果元数据
+func (m *timeRangeMapper) apply(result *models.RecognitionResult) {
+	if m == nil {
+		return
+	}
+	result.Metadata["time_ranges"] = m.ranges
+}
+
+// inTimeRanges 判断原始文件中的时间点是否落在时间范围内，未指定范围时总是返回true
+func inTimeRanges(ranges []models.TimeRange, t float64) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if t >= r.Start && t < r.End {
+			return true
+		}
+	}
+	return false
+}
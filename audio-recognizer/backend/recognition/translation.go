@@ -0,0 +1,130 @@
+package recognition
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"tingshengbianzi/backend/models"
+)
+
+// translationLanguage Whisper翻译任务的目标语言（Whisper只支持翻译为英文）
+const translationLanguage = "en"
+
+// passRecognizer 执行一遍识别，translate为true时输出英文译文
+type passRecognizer func(ctx context.Context, translate bool, progressCallback func(*models.RecognitionProgress)) (*whisperRun, error)
+
+// NormalizeTask 校验识别任务类型，留空时为转写
+func NormalizeTask(task string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(task)) {
+	case "", models.TaskTranscribe:
+		return models.TaskTranscribe, nil
+	case models.TaskTranslate:
+		return models.TaskTranslate, nil
+	case models.TaskBilingual:
+		return models.TaskBilingual, nil
+	default:
+		return "", fmt.Errorf("不支持的识别任务: %s（可选 transcribe、translate、bilingual）", task)
+	}
+}
+
+// runTask 按任务类型执行识别
+// translate只执行翻译；bilingual先转写再翻译，进度各占一半，返回的translation为译文结果
+func runTask(ctx context.Context, task string, recognize passRecognizer, progressCallback func(*models.RecognitionProgress)) (source, translation *whisperRun, err error) {
+	switch task {
+	case models.TaskTranslate:
+		source, err = recognize(ctx, true, progressCallback)
+		return source, nil, err
+	case models.TaskBilingual:
+		source, err = recognize(ctx, false, scaledProgress(progressCallback, 0, 50, "", true))
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("🌐 原文识别完成，开始翻译为英文\n")
+		translation, err = recognize(ctx, true, scaledProgress(progressCallback, 50, 100, "[翻译] ", false))
+		if err != nil {
+			return nil, nil, err
+		}
+		return source, translation, nil
+	default:
+		source, err = recognize(ctx, false, progressCallback)
+		return source, nil, err
+	}
+}
+
+// scaledProgress 将一遍识别的进度换算到整体进度的[from, to)区间
+// keepPartial为false时丢弃实时段落，避免译文混入实时显示的原文
+func scaledProgress(progressCallback func(*models.RecognitionProgress), from, to int, statusPrefix string, keepPartial bool) func(*models.RecognitionProgress) {
+	if progressCallback == nil {
+		return nil
+	}
+	return func(progress *models.RecognitionProgress) {
+		if progress == nil {
+			return
+		}
+		scaled := *progress
+		scaled.Percentage = from + min(progress.Percentage, 99)*(to-from)/100
+		scaled.Status = statusPrefix + progress.Status
+		if !keepPartial {
+			scaled.Partial = nil
+		}
+		progressCallback(&scaled)
+	}
+}
+
+// alignTranslations 按时间将译文段落对齐到原文段落
+// 每个译文段落归入时间重叠最多的原文段落（无重叠时取最近的），同一原文段落的多段译文依次拼接
+func alignTranslations(source, translated []models.RecognitionResultSegment) {
+	if len(source) == 0 {
+		return
+	}
+	parts := make([][]string, len(source))
+	for _, segment := range translated {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		index := bestAlignedSegment(source, segment.Start, segment.End)
+		parts[index] = append(parts[index], text)
+	}
+	for i := range source {
+		source[i].Translation = strings.Join(parts[i], " ")
+	}
+}
+
+// bestAlignedSegment 查找与时间范围重叠最多的段落，都不重叠时返回中点距离最近的段落
+func bestAlignedSegment(segments []models.RecognitionResultSegment, start, end float64) int {
+	best, bestOverlap := -1, 0.0
+	for i, segment := range segments {
+		overlap := math.Min(end, segment.End) - math.Max(start, segment.Start)
+		if overlap > bestOverlap {
+			best, bestOverlap = i, overlap
+		}
+	}
+	if best >= 0 {
+		return best
+	}
+
+	middle := (start + end) / 2
+	bestDistance := 0.0
+	for i, segment := range segments {
+		distance := math.Abs(middle - (segment.Start+segment.End)/2)
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best
+}
+
+// applyTaskInfo 将任务类型写入识别结果，翻译任务的结果语言为英文，原语言记录在元数据中
+func applyTaskInfo(result *models.RecognitionResult, task string) {
+	result.Metadata["task"] = task
+	switch task {
+	case models.TaskTranslate:
+		result.Metadata["source_language"] = result.Language
+		result.Language = translationLanguage
+	case models.TaskBilingual:
+		result.Metadata["translation_language"] = translationLanguage
+	}
+}
@@ -0,0 +1,489 @@
+package recognition
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"tingshengbianzi/backend/models"
+)
+
+// 词汇纠正的匹配方式
+const (
+	CorrectionAlias       = "alias"        // 命中配置的误识别写法
+	CorrectionCase        = "case"         // 英文术语大小写不一致
+	CorrectionFuzzy       = "fuzzy"        // 英文拼写相近，或与近音写法相同
+	CorrectionPinyin      = "pinyin"       // 中文同音字（声调相同）
+	CorrectionPinyinFuzzy = "pinyin_fuzzy" // 中文近音字（平翘舌、前后鼻音、n/l）
+)
+
+const (
+	// whisperPromptTokenBudget Whisper初始提示词最多使用的token数（文本上下文448的一半）
+	whisperPromptTokenBudget = 224
+	// spellingMinLength 去掉空格后与术语拼写相同即纠正（如 "Kuber netes"）的最短术语长度
+	spellingMinLength = 5
+	// fuzzySpellingSimilarity 英文术语拼写相似度阈值，拼写相近只作为建议，不改写文本
+	fuzzySpellingSimilarity = 0.9
+	// fuzzySpellingMinLength 参与拼写相似匹配的最短术语长度，过短的词和常用词只差一两个字母（如 reach/React）
+	fuzzySpellingMinLength = 8
+	// pinyinRewriteMinLength 按读音直接纠正的最少汉字数，更短的词同音词太多（如 威信/微信），只作为建议
+	// 近音匹配同样要求至少这么多字，且只作为建议
+	pinyinRewriteMinLength = 3
+)
+
+// latinWordPattern 英文单词（含数字和 Node.js、e-mail 这类内部符号）
+var latinWordPattern = regexp.MustCompile(`[\p{Latin}\p{N}]+(?:[.'+\-][\p{Latin}\p{N}]+)*`)
+
+// buildVocabularyPrompt 将词汇表术语追加到初始提示词，超过Whisper提示词预算的术语不再加入
+// 返回新的提示词和实际加入的术语数
+func buildVocabularyPrompt(prompt string, vocabulary *models.Vocabulary) (string, int) {
+	if vocabulary == nil || len(vocabulary.Terms) == 0 {
+		return prompt, 0
+	}
+
+	separator := ", "
+	for _, term := range vocabulary.Terms {
+		if containsCJK(term.Output()) {
+			separator = "、"
+			break
+		}
+	}
+
+	used := estimatePromptTokens(prompt)
+	var terms []string
+	for _, term := range vocabulary.Terms {
+		output := strings.TrimSpace(term.Output())
+		if output == "" {
+			continue
+		}
+		cost := estimatePromptTokens(output) + 1
+		if used+cost > whisperPromptTokenBudget {
+			fmt.Printf("⚠️ 初始提示词已达到token预算，剩余 %d 个术语未加入\n", len(vocabulary.Terms)-len(terms))
+			break
+		}
+		used += cost
+		terms = append(terms, output)
+	}
+	if len(terms) == 0 {
+		return prompt, 0
+	}
+
+	vocabularyPrompt := strings.Join(terms, separator)
+	if separator == "、" {
+		vocabularyPrompt += "。"
+	} else {
+		vocabularyPrompt += "."
+	}
+	if prompt == "" {
+		return vocabularyPrompt, len(terms)
+	}
+	return strings.TrimSpace(prompt) + " " + vocabularyPrompt, len(terms)
+}
+
+// estimatePromptTokens 估算文本的Whisper token数
+// Whisper使用字节级BPE：常见英文词约4个字母一个token，汉字通常占1~2个token，这里按偏多估算
+func estimatePromptTokens(text string) int {
+	tokens := 0
+	letters := 0
+	flush := func() {
+		if letters > 0 {
+			tokens += (letters + 3) / 4
+			letters = 0
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			letters++
+		case unicode.IsSpace(r):
+			flush()
+		case r < unicode.MaxASCII:
+			flush()
+			tokens++
+		default:
+			flush()
+			tokens += 2
+		}
+	}
+	flush()
+	return tokens
+}
+
+// vocabularyRule 一个术语的匹配规则
+type vocabularyRule struct {
+	term       string   // 纠正后写入的文本
+	aliases    []string // 误识别写法
+	spelling   string   // 英文拼写相似匹配的目标（小写、去掉空格），为空时不做拼写匹配
+	soundsLike string   // 英文近音写法（小写、去掉空格）
+	pinyin     []string // 中文读音匹配的音节，为空时不做读音匹配
+	words      int      // 英文术语的单词数
+}
+
+// vocabularyCorrector 识别后的词汇纠正
+type vocabularyCorrector struct {
+	name  string
+	rules []vocabularyRule
+}
+
+// newVocabularyCorrector 根据词汇表生成匹配规则，词汇表为空时返回nil
+func newVocabularyCorrector(vocabulary *models.Vocabulary) *vocabularyCorrector {
+	if vocabulary == nil || len(vocabulary.Terms) == 0 {
+		return nil
+	}
+
+	corrector := &vocabularyCorrector{name: vocabulary.Name}
+	for _, term := range vocabulary.Terms {
+		output := strings.TrimSpace(term.Output())
+		if output == "" {
+			continue
+		}
+		rule := vocabularyRule{term: output}
+		for _, alias := range term.Aliases {
+			if alias = strings.TrimSpace(alias); alias != "" && alias != output {
+				rule.aliases = append(rule.aliases, alias)
+			}
+		}
+
+		if containsCJK(output) {
+			rule.pinyin, _ = textPinyin(output)
+		} else {
+			rule.words = len(latinWordPattern.FindAllString(output, -1))
+			if compact := compactLatin(output); len(compact) >= spellingMinLength {
+				rule.spelling = compact
+			}
+		}
+
+		// 发音提示：拼音用于中文读音匹配（英文术语被识别成汉字时也能纠正），否则作为英文近音写法
+		if term.Pronunciation != "" {
+			if syllables := parsePinyinHint(term.Pronunciation); syllables != nil {
+				rule.pinyin = syllables
+			} else if compact := compactLatin(term.Pronunciation); compact != "" {
+				rule.soundsLike = compact
+				if rule.words == 0 {
+					rule.words = len(latinWordPattern.FindAllString(term.Pronunciation, -1))
+				}
+			}
+		}
+		corrector.rules = append(corrector.rules, rule)
+	}
+	if len(corrector.rules) == 0 {
+		return nil
+	}
+	return corrector
+}
+
+// vocabularyReport 词汇纠正结果
+type vocabularyReport struct {
+	name        string
+	promptTerms int
+	corrections []models.VocabularyCorrection
+	suggestions []models.VocabularyCorrection // 把握不大、未改写文本的候选纠正
+}
+
+// apply 将纠正记录写入识别结果元数据
+func (r *vocabularyReport) apply(result *models.RecognitionResult) {
+	if r == nil {
+		return
+	}
+	result.Metadata["vocabulary"] = r.name
+	result.Metadata["vocabulary_prompt_terms"] = r.promptTerms
+	result.Metadata["vocabulary_corrections"] = r.corrections
+	result.Metadata["vocabulary_suggestions"] = r.suggestions
+	if len(r.corrections) > 0 || len(r.suggestions) > 0 {
+		fmt.Printf("📖 词汇纠正: %d 处，建议: %d 处\n", len(r.corrections), len(r.suggestions))
+	}
+}
+
+// correct 对各段落执行词汇纠正，原文本记录在段落元数据original_text中
+// 只有别名、大小写、近音写法、拼写相同和较长术语的同音字直接改写文本，其余命中只记为建议
+func (c *vocabularyCorrector) correct(segments []models.RecognitionResultSegment, promptTerms int) *vocabularyReport {
+	if c == nil {
+		return nil
+	}
+	report := &vocabularyReport{
+		name:        c.name,
+		promptTerms: promptTerms,
+		corrections: []models.VocabularyCorrection{},
+		suggestions: []models.VocabularyCorrection{},
+	}
+
+	for i := range segments {
+		segment := &segments[i]
+		original := segment.Text
+		for _, rule := range c.rules {
+			changes := rule.apply(segment.Text)
+			if len(changes) == 0 {
+				continue
+			}
+			var rewrites []textChange
+			for _, change := range changes {
+				record := models.VocabularyCorrection{
+					SegmentIndex: i,
+					Start:        segment.Start,
+					End:          segment.End,
+					Original:     change.original,
+					Corrected:    rule.term,
+					Term:         rule.term,
+					Method:       change.method,
+				}
+				if change.suggestion {
+					report.suggestions = append(report.suggestions, record)
+					continue
+				}
+				rewrites = append(rewrites, change)
+				report.corrections = append(report.corrections, record)
+				segment.Words = correctWords(segment.Words, change.original, rule.term)
+			}
+			segment.Text = replaceChanges(segment.Text, rewrites, rule.term)
+		}
+		if segment.Text != original {
+			if segment.Metadata == nil {
+				segment.Metadata = make(map[string]interface{})
+			}
+			segment.Metadata["original_text"] = original
+		}
+	}
+	return report
+}
+
+// textChange 一处待纠正的文本，start/end为字节位置
+type textChange struct {
+	start      int
+	end        int
+	original   string
+	method     string
+	suggestion bool // 只作为建议，不改写文本
+}
+
+// apply 在文本中查找需要纠正为该术语的片段，按位置排序并去掉重叠
+func (r vocabularyRule) apply(text string) []textChange {
+	var changes []textChange
+	for _, alias := range r.aliases {
+		changes = append(changes, findAlias(text, alias)...)
+	}
+	if r.words > 0 {
+		changes = append(changes, r.findLatin(text)...)
+	}
+	if len(r.pinyin) > 0 {
+		changes = append(changes, r.findPinyin(text)...)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].start < changes[j].start })
+	kept := changes[:0]
+	for _, change := range changes {
+		if n := len(kept); n > 0 && change.start < kept[n-1].end {
+			continue
+		}
+		kept = append(kept, change)
+	}
+	return kept
+}
+
+// replaceChanges 将各片段替换为术语（changes已按位置排序且不重叠）
+func replaceChanges(text string, changes []textChange, term string) string {
+	var builder strings.Builder
+	last := 0
+	for _, change := range changes {
+		builder.WriteString(text[last:change.start])
+		builder.WriteString(term)
+		last = change.end
+	}
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// findAlias 查找误识别写法，英文按整词匹配且不区分大小写
+func findAlias(text, alias string) []textChange {
+	expression := regexp.QuoteMeta(alias)
+	if !containsCJK(alias) {
+		expression = `(?i)\b` + expression + `\b`
+	}
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		return nil
+	}
+	var changes []textChange
+	for _, match := range pattern.FindAllStringIndex(text, -1) {
+		changes = append(changes, textChange{start: match[0], end: match[1], original: text[match[0]:match[1]], method: CorrectionAlias})
+	}
+	return changes
+}
+
+// findLatin 按连续的英文单词窗口查找大小写不一致或拼写相近的片段
+// 窗口比术语多一个单词，以覆盖术语被拆成两个词的情况（如 "Kuber netes"）
+func (r vocabularyRule) findLatin(text string) []textChange {
+	matches := latinWordPattern.FindAllStringIndex(text, -1)
+	var changes []textChange
+	for i := 0; i < len(matches); i++ {
+		for size := r.words; size <= r.words+1 && i+size <= len(matches); size++ {
+			window := matches[i : i+size]
+			if !onlySpacesBetween(text, window) {
+				break
+			}
+			start, end := window[0][0], window[size-1][1]
+			candidate := text[start:end]
+			if candidate == r.term {
+				continue
+			}
+			if method, suggestion := r.matchLatin(candidate); method != "" {
+				changes = append(changes, textChange{start: start, end: end, original: candidate, method: method, suggestion: suggestion})
+				i += size - 1
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// matchLatin 判断英文片段是否应纠正为该术语，suggestion为true时只作为建议
+func (r vocabularyRule) matchLatin(candidate string) (method string, suggestion bool) {
+	compact := compactLatin(candidate)
+	if strings.EqualFold(candidate, r.term) {
+		return CorrectionCase, false
+	}
+	if r.soundsLike != "" && compact == r.soundsLike {
+		return CorrectionFuzzy, false
+	}
+	if r.spelling == "" {
+		return "", false
+	}
+	if compact == r.spelling {
+		return CorrectionFuzzy, false
+	}
+	if len(r.spelling) >= fuzzySpellingMinLength && spellingSimilarity(compact, r.spelling) >= fuzzySpellingSimilarity {
+		return CorrectionFuzzy, true
+	}
+	return "", false
+}
+
+// findPinyin 查找与术语读音相同（或相近）的连续汉字
+// 只有标全声调且不少于pinyinRewriteMinLength个字的术语同音时直接纠正，其余命中作为建议
+func (r vocabularyRule) findPinyin(text string) []textChange {
+	// 各字符的字节位置，末尾追加文本长度便于取窗口结束位置
+	var offsets []int
+	for offset := range text {
+		offsets = append(offsets, offset)
+	}
+	offsets = append(offsets, len(text))
+
+	size := len(r.pinyin)
+	fuzzy := size >= pinyinRewriteMinLength
+	rewrite := size >= pinyinRewriteMinLength && tonedPinyin(r.pinyin)
+	var changes []textChange
+	for i := 0; i+size < len(offsets); i++ {
+		start, end := offsets[i], offsets[i+size]
+		candidate := text[start:end]
+		if candidate == r.term {
+			i += size - 1
+			continue
+		}
+		syllables, ok := textPinyin(candidate)
+		if !ok {
+			continue
+		}
+		change := textChange{start: start, end: end, original: candidate}
+		switch {
+		case samePinyin(syllables, r.pinyin, false):
+			change.method, change.suggestion = CorrectionPinyin, !rewrite
+		case fuzzy && samePinyin(syllables, r.pinyin, true):
+			change.method, change.suggestion = CorrectionPinyinFuzzy, true
+		default:
+			continue
+		}
+		changes = append(changes, change)
+		i += size - 1
+	}
+	return changes
+}
+
+// correctWords 将纠正同步到词汇列表：找到包含原文本的连续词并合并为一个词
+func correctWords(words []models.Word, original, corrected string) []models.Word {
+	separator := " "
+	if containsCJK(original) {
+		separator = ""
+	}
+	for i := range words {
+		joined := ""
+		for j := i; j < len(words) && j < i+8; j++ {
+			if j > i {
+				joined += separator
+			}
+			joined += words[j].Text
+			if !strings.Contains(joined, original) {
+				continue
+			}
+
+			merged := words[i]
+			merged.Text = strings.Replace(joined, original, corrected, 1)
+			merged.End = words[j].End
+			confidences := make([]float64, 0, j-i+1)
+			for _, word := range words[i : j+1] {
+				confidences = append(confidences, word.Confidence)
+			}
+			merged.Confidence = average(confidences)
+
+			result := make([]models.Word, 0, len(words)-(j-i))
+			result = append(result, words[:i]...)
+			result = append(result, merged)
+			return append(result, words[j+1:]...)
+		}
+	}
+	return words
+}
+
+// onlySpacesBetween 判断相邻单词之间是否只有空白
+func onlySpacesBetween(text string, window [][]int) bool {
+	for k := 1; k < len(window); k++ {
+		if strings.TrimSpace(text[window[k-1][1]:window[k][0]]) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// compactLatin 转小写并去掉空白和符号，用于比较英文拼写
+func compactLatin(text string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// spellingSimilarity 基于编辑距离的拼写相似度（0~1）
+func spellingSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein 编辑距离
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,303 @@
+package recognition
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"tingshengbianzi/backend/models"
+)
+
+// whisperJSONOutput whisper-cli -ojf 输出的完整JSON结构
+type whisperJSONOutput struct {
+	Params struct {
+		Language  string `json:"language"`
+		Translate bool   `json:"translate"`
+	} `json:"params"`
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []whisperJSONSegment `json:"transcription"`
+}
+
+// whisperJSONSegment JSON输出中的单个段落
+type whisperJSONSegment struct {
+	Offsets         whisperJSONOffsets `json:"offsets"`
+	Text            whisperRawText     `json:"text"`
+	Tokens          []whisperJSONToken `json:"tokens"`
+	Speaker         string             `json:"speaker"`           // -di 时的声道: "0" | "1" | "?"
+	SpeakerTurnNext bool               `json:"speaker_turn_next"` // -tdrz 时下一段是否换人
+}
+
+// whisperJSONToken JSON输出中的token（含真实时间偏移和概率）
+type whisperJSONToken struct {
+	Text    whisperRawText     `json:"text"`
+	Offsets whisperJSONOffsets `json:"offsets"`
+	ID      int                `json:"id"`
+	P       float64            `json:"p"`
+}
+
+// whisperJSONOffsets 时间偏移（毫秒）
+type whisperJSONOffsets struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// whisperRawText 保留原始字节的JSON字符串
+// whisper-cli 按token输出文本，中文等多字节字符可能被拆到相邻token中，
+// 标准解码会把不完整的UTF-8替换为U+FFFD，因此这里自行反转义并保留原始字节
+type whisperRawText []byte
+
+// UnmarshalJSON 反转义JSON字符串但不校验UTF-8
+func (t *whisperRawText) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = nil
+		return nil
+	}
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return fmt.Errorf("无效的JSON字符串: %s", string(data))
+	}
+
+	body := data[1 : len(data)-1]
+	out := make([]byte, 0, len(body))
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c != '\\' {
+			out = append(out, c)
+			continue
+		}
+		i++
+		if i >= len(body) {
+			return fmt.Errorf("JSON字符串转义不完整")
+		}
+		switch body[i] {
+		case '"', '\\', '/':
+			out = append(out, body[i])
+		case 'n':
+			out = append(out, '\n')
+		case 't':
+			out = append(out, '\t')
+		case 'r':
+			out = append(out, '\r')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'u':
+			if i+4 >= len(body) {
+				return fmt.Errorf("JSON字符串\\u转义不完整")
+			}
+			code, err := strconv.ParseUint(string(body[i+1:i+5]), 16, 32)
+			if err != nil {
+				return fmt.Errorf("无效的\\u转义: %v", err)
+			}
+			out = utf8.AppendRune(out, rune(code))
+			i += 4
+		default:
+			out = append(out, body[i])
+		}
+	}
+
+	*t = out
+	return nil
+}
+
+// parseWhisperJSON 解析whisper-cli -ojf 生成的JSON文件，返回带真实词级时间戳的段落
+func (s *WhisperService) parseWhisperJSON(jsonFile string) ([]models.RecognitionResultSegment, *whisperJSONOutput, error) {
+	content, err := os.ReadFile(jsonFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取JSON文件失败: %w", err)
+	}
+
+	var output whisperJSONOutput
+	if err := json.Unmarshal(content, &output); err != nil {
+		return nil, nil, fmt.Errorf("解析JSON文件失败: %w", err)
+	}
+
+	segments := make([]models.RecognitionResultSegment, 0, len(output.Transcription))
+	for _, seg := range output.Transcription {
+		text := strings.TrimSpace(strings.ToValidUTF8(string(seg.Text), ""))
+		if text == "" {
+			continue
+		}
+
+		words := s.buildWordsFromTokens(seg.Tokens)
+
+		segment := models.RecognitionResultSegment{
+			Start:      float64(seg.Offsets.From) / 1000.0,
+			End:        float64(seg.Offsets.To) / 1000.0,
+			Text:       s.convertToSimplified(text),
+			Confidence: averageWordConfidence(words),
+			Words:      words,
+			Metadata:   make(map[string]interface{}),
+		}
+		segment.Metadata["timestamp_source"] = "token"
+		if seg.Speaker != "" {
+			segment.Metadata[speakerChannelKey] = seg.Speaker
+		}
+		if seg.SpeakerTurnNext {
+			segment.Metadata[speakerTurnNextKey] = true
+		}
+		segments = append(segments, segment)
+	}
+
+	return segments, &output, nil
+}
+
+// buildWordsFromTokens 根据token的真实时间偏移组装词汇
+// 英文等以空格分词的语言：以空格开头的token开启新词，其余token视为词内子词
+// 中日韩文字：每个token单独成词；标点符号并入前一个词
+func (s *WhisperService) buildWordsFromTokens(tokens []whisperJSONToken) []models.Word {
+	var words []models.Word
+
+	var pending []byte
+	var pendingStart, pendingEnd int64
+	var pendingProbs []float64
+
+	var current *models.Word
+	var currentProbs []float64
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimSpace(current.Text)
+		if current.Text != "" {
+			current.Confidence = average(currentProbs)
+			words = append(words, *current)
+		}
+		current = nil
+		currentProbs = nil
+	}
+
+	addPiece := func(piece string, start, end float64, probs []float64) {
+		trimmed := strings.TrimSpace(piece)
+		if trimmed == "" {
+			return
+		}
+
+		switch {
+		case current != nil && isPunctuationOnly(trimmed):
+			// 标点并入前一个词
+			current.Text += trimmed
+			current.End = end
+			currentProbs = append(currentProbs, probs...)
+		case current == nil || strings.HasPrefix(piece, " ") || containsCJK(trimmed) || containsCJK(current.Text):
+			flush()
+			current = &models.Word{
+				Text:  s.convertToSimplified(trimmed),
+				Start: start,
+				End:   end,
+			}
+			currentProbs = append([]float64{}, probs...)
+		default:
+			// 词内子词
+			current.Text += trimmed
+			current.End = end
+			currentProbs = append(currentProbs, probs...)
+		}
+	}
+
+	// flushPending 输出已拼接的token，无法补全的多字节字符残片丢弃
+	flushPending := func() {
+		if len(pending) == 0 {
+			return
+		}
+		piece := strings.ToValidUTF8(string(pending), "")
+		addPiece(piece, float64(pendingStart)/1000.0, float64(pendingEnd)/1000.0, pendingProbs)
+		pending = nil
+		pendingProbs = nil
+	}
+
+	for _, token := range tokens {
+		if isSpecialWhisperToken(string(token.Text)) {
+			continue
+		}
+
+		// 新token以字符开头时，之前没拼完整的多字节字符不会再被补全
+		if len(pending) > 0 && len(token.Text) > 0 && utf8.RuneStart(token.Text[0]) {
+			flushPending()
+		}
+		if len(pending) == 0 {
+			pendingStart = token.Offsets.From
+		}
+		pending = append(pending, token.Text...)
+		pendingEnd = token.Offsets.To
+		pendingProbs = append(pendingProbs, token.P)
+
+		// 多字节字符尚未拼接完整，等待后续token
+		if hasIncompleteRune(pending) {
+			continue
+		}
+		flushPending()
+	}
+	flushPending()
+	flush()
+
+	return words
+}
+
+// hasIncompleteRune 判断末尾是否为尚未拼接完整的多字节字符（还可能被后续字节补全）
+func hasIncompleteRune(data []byte) bool {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			return !utf8.FullRune(data[i:])
+		}
+	}
+	return false
+}
+
+// isSpecialWhisperToken 判断是否为Whisper特殊token（如 [_BEG_]、[_TT_150]、<|endoftext|>）
+func isSpecialWhisperToken(text string) bool {
+	trimmed := strings.TrimSpace(text)
+	return strings.HasPrefix(trimmed, "[_") || strings.HasPrefix(trimmed, "<|")
+}
+
+// isPunctuationOnly 判断文本是否只包含标点符号
+func isPunctuationOnly(text string) bool {
+	for _, r := range text {
+		if !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			return false
+		}
+	}
+	return text != ""
+}
+
+// containsCJK 判断文本是否包含中日韩文字
+func containsCJK(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// averageWordConfidence 计算词汇平均置信度
+func averageWordConfidence(words []models.Word) float64 {
+	if len(words) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, word := range words {
+		total += word.Confidence
+	}
+	return total / float64(len(words))
+}
+
+// average 计算平均值
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
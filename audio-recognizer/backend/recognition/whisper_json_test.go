@@ -0,0 +1,104 @@
+package recognition
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"tingshengbianzi/backend/models"
+)
+
+// writeFakeWhisperScript 写入fakewhisper脚本并通过FAKE_WHISPER_SCRIPT指定
+func writeFakeWhisperScript(t *testing.T, script string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.json")
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAKE_WHISPER_SCRIPT", path)
+}
+
+// recognizeWithFakeWhisper 用fakewhisper识别一段测试音频
+func recognizeWithFakeWhisper(t *testing.T) (*models.RecognitionResult, error) {
+	t.Helper()
+	installFakeFFmpeg(t)
+	modelDir := writeTestModels(t, "ggml-base.bin")
+	service, err := NewWhisperServiceWithOptions(testConfig(modelDir), WhisperServiceOptions{WhisperPath: buildFakeWhisperCLI(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	audioPath := filepath.Join(t.TempDir(), "input.mp3")
+	writeTestWAV(t, audioPath, 16000)
+
+	return service.RecognizeFileWithOptions(context.Background(), audioPath, "zh-CN",
+		models.RecognitionOptions{SpecificModelFile: filepath.Join(modelDir, "ggml-base.bin")}, nil)
+}
+
+// wordTexts 提取词汇文本
+func wordTexts(words []models.Word) []string {
+	texts := make([]string, 0, len(words))
+	for _, word := range words {
+		texts = append(texts, word.Text)
+	}
+	return texts
+}
+
+// TestBuildWordsFromTokens token拼接成词，多字节字符被拆开或截断时不影响后续token
+func TestBuildWordsFromTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		want   []string
+	}{
+		{"英文子词", []string{" Hel", "lo", " world", "."}, []string{"Hello", "world."}},
+		{"中文逐字", []string{"你", "好", "，", "世", "界"}, []string{"你", "好，", "世", "界"}},
+		{"多字节字符拆到两个token", []string{"\xe4", "\xbd\xa0", "\xe5\xa5", "\xbd"}, []string{"你", "好"}},
+		{"截断的多字节字符", []string{"你", "\xe5\xa5", "世界", " hello"}, []string{"你", "世界", "hello"}},
+		{"多余的续字节", []string{"你", "\xa5\xa5", "好"}, []string{"你", "好"}},
+		{"结尾的残片", []string{" hello", "\xe5"}, []string{"hello"}},
+	}
+
+	service := &WhisperService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := []whisperJSONToken{{Text: whisperRawText("[_BEG_]")}}
+			for i, text := range tt.tokens {
+				tokens = append(tokens, whisperJSONToken{
+					Text:    whisperRawText(text),
+					Offsets: whisperJSONOffsets{From: int64(i * 100), To: int64((i + 1) * 100)},
+					P:       0.9,
+				})
+			}
+			if got := wordTexts(service.buildWordsFromTokens(tokens)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("词汇为 %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWhisperCLISplitMultibyteTokens whisper-cli把中文字符拆到两个token时，词汇仍逐字拼接完整
+func TestWhisperCLISplitMultibyteTokens(t *testing.T) {
+	writeFakeWhisperScript(t, `{
+		"segments": [{"start": 0, "end": 2, "text": "你好，世界。"}, {"start": 2, "end": 3, "text": "Hello world."}],
+		"splitMultibyte": true
+	}`)
+	result, err := recognizeWithFakeWhisper(t)
+	if err != nil {
+		t.Fatalf("识别失败: %v", err)
+	}
+
+	want := [][]string{{"你", "好，", "世", "界。"}, {"Hello", "world."}}
+	if len(result.Segments) != len(want) {
+		t.Fatalf("识别结果有 %d 个段落，期望 %d 个", len(result.Segments), len(want))
+	}
+	for i, segment := range result.Segments {
+		if got := wordTexts(segment.Words); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("第 %d 段的词汇为 %q，期望 %q", i+1, got, want[i])
+		}
+		if source := segment.Metadata["timestamp_source"]; source != "token" {
+			t.Errorf("第 %d 段的时间戳来源为 %v，期望token", i+1, source)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package recognition
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/utils"
+)
+
+var (
+	// whisperProgressPattern 匹配 -pp 输出: "whisper_print_progress_callback: progress =  40%"
+	whisperProgressPattern = regexp.MustCompile(`progress\s*=\s*(\d+)%`)
+	// whisperSegmentPattern 匹配实时段落输出: "[00:00:01.000 --> 00:00:03.500]   文本"
+	whisperSegmentPattern = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2}\.\d{3})\s*-->\s*(\d{2}:\d{2}:\d{2}\.\d{3})\]\s*(.*)$`)
+)
+
+// whisperProgressTracker 解析whisper-cli的实时输出，换算为识别进度并上报
+type whisperProgressTracker struct {
+	mu               sync.Mutex
+	totalTime        float64
+	startedAt        time.Time
+	currentTime      float64
+	percentage       int
+	wordCount        int
+	segmentCount     int
+	detectedLang     string  // 自动检测出的Whisper语言代码
+	detectedLangProb float64 // 检测语言的概率
+	progressCallback func(*models.RecognitionProgress)
+	convertText      func(string) string
+}
+
+// newWhisperProgressTracker 创建进度跟踪器
+func newWhisperProgressTracker(totalTime float64, progressCallback func(*models.RecognitionProgress), convertText func(string) string) *whisperProgressTracker {
+	return &whisperProgressTracker{
+		totalTime:        totalTime,
+		startedAt:        time.Now(),
+		progressCallback: progressCallback,
+		convertText:      convertText,
+	}
+}
+
+// handleLine 处理whisper-cli输出的一行
+func (t *whisperProgressTracker) handleLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	if match := whisperSegmentPattern.FindStringSubmatch(line); match != nil {
+		t.handleSegment(match[1], match[2], match[3])
+		return
+	}
+
+	if lang, probability, ok := parseDetectedLanguage(line); ok {
+		t.mu.Lock()
+		t.detectedLang = lang
+		t.detectedLangProb = probability
+		t.mu.Unlock()
+		return
+	}
+
+	if match := whisperProgressPattern.FindStringSubmatch(line); match != nil {
+		if percentage, err := strconv.Atoi(match[1]); err == nil {
+			t.handleProgress(percentage)
+		}
+	}
+}
+
+// handleProgress 处理解码进度百分比
+func (t *whisperProgressTracker) handleProgress(percentage int) {
+	t.mu.Lock()
+	if percentage > t.percentage {
+		t.percentage = min(percentage, 99) // 100% 留给结果解析完成后发送
+	}
+	if t.totalTime > 0 {
+		position := t.totalTime * float64(percentage) / 100.0
+		if position > t.currentTime {
+			t.currentTime = position
+		}
+	}
+	progress := t.snapshot("正在识别语音...")
+	t.mu.Unlock()
+
+	t.report(progress)
+}
+
+// handleSegment 处理实时解码出的段落
+func (t *whisperProgressTracker) handleSegment(startStr, endStr, text string) {
+	start, err := utils.ParseTimestamp(startStr)
+	if err != nil {
+		return
+	}
+	end, err := utils.ParseTimestamp(endStr)
+	if err != nil {
+		return
+	}
+
+	text = strings.TrimSpace(strings.ToValidUTF8(text, ""))
+	text, _, _ = stripSpeakerMarks(text) // 说话人分离时的 "(speaker 0)" 和 "[SPEAKER_TURN]" 标记
+	if t.convertText != nil {
+		text = t.convertText(text)
+	}
+
+	t.mu.Lock()
+	t.segmentCount++
+	t.wordCount += countWords(text)
+	if end > t.currentTime {
+		t.currentTime = end
+	}
+	if t.totalTime > 0 {
+		if byTime := int(t.currentTime / t.totalTime * 100); byTime > t.percentage {
+			t.percentage = min(byTime, 99)
+		}
+	}
+	progress := t.snapshot(fmt.Sprintf("正在识别语音... 已识别 %d 段", t.segmentCount))
+	t.mu.Unlock()
+
+	if text != "" {
+		progress.Partial = &models.RecognitionResultSegment{
+			Start:    start,
+			End:      end,
+			Text:     text,
+			Words:    []models.Word{},
+			Metadata: map[string]interface{}{"partial": true},
+		}
+	}
+	t.report(progress)
+}
+
+// detectedLanguage 获取自动检测出的语言及概率，未检测时返回空字符串
+func (t *whisperProgressTracker) detectedLanguage() (string, float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.detectedLang, t.detectedLangProb
+}
+
+// snapshot 生成当前进度快照（调用方需持有锁）
+func (t *whisperProgressTracker) snapshot(status string) *models.RecognitionProgress {
+	wordsPerSec := 0.0
+	if elapsed := time.Since(t.startedAt).Seconds(); elapsed > 0 {
+		wordsPerSec = float64(t.wordCount) / elapsed
+	}
+
+	return &models.RecognitionProgress{
+		CurrentTime: t.currentTime,
+		TotalTime:   t.totalTime,
+		Percentage:  t.percentage,
+		Status:      status,
+		WordsPerSec: wordsPerSec,
+	}
+}
+
+// report 上报进度
+func (t *whisperProgressTracker) report(progress *models.RecognitionProgress) {
+	if t.progressCallback != nil {
+		t.progressCallback(progress)
+	}
+}
+
+// runWhisperCommand 执行whisper-cli并逐行读取stdout/stderr，返回完整输出
+func runWhisperCommand(ctx context.Context, cmd *exec.Cmd, onLine func(string)) (string, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("获取whisper-cli标准输出失败: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", fmt.Errorf("获取whisper-cli错误输出失败: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("启动whisper-cli失败: %w", err)
+	}
+
+	var output bytes.Buffer
+	var outputMu sync.Mutex
+	var wg sync.WaitGroup
+
+	readLines := func(reader io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			outputMu.Lock()
+			output.WriteString(line)
+			output.WriteString("\n")
+			outputMu.Unlock()
+
+			if onLine != nil && ctx.Err() == nil {
+				onLine(line)
+			}
+		}
+		// 单行超过缓冲区上限时Scan提前结束，必须继续读空管道，否则whisper-cli写满管道后阻塞，Wait永远不返回
+		if err := scanner.Err(); err != nil {
+			utils.LogError("读取whisper-cli输出失败，丢弃剩余输出: %v", err)
+			io.Copy(io.Discard, reader)
+		}
+	}
+
+	wg.Add(2)
+	go readLines(stdout)
+	go readLines(stderr)
+
+	// 必须先读完管道再Wait，否则可能丢失输出
+	wg.Wait()
+	err = cmd.Wait()
+
+	return output.String(), err
+}
+
+// countWords 统计文本词数（中日韩文字按字计数，其余按空格分词）
+func countWords(text string) int {
+	count := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				count++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
@@ -0,0 +1,987 @@
+package recognition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/path"
+	"tingshengbianzi/backend/utils"
+)
+
+const (
+	whisperServerHost         = "127.0.0.1"
+	whisperServerStartTimeout = 3 * time.Minute // large模型加载可能较慢
+	whisperServerStopTimeout  = 5 * time.Second
+	whisperServerLogLimit     = 8 * 1024
+)
+
+func init() {
+	RegisterEngine(EngineDescriptor{
+		Name:        "whisper-server",
+		DisplayName: "Whisper Server",
+		Description: "常驻whisper-server进程，模型只加载一次，适合连续识别多个文件",
+		Priority:    20,
+		Capabilities: EngineCapabilities{
+			Languages:      languageCodes(),
+			WordTimestamps: true,
+			Diarization:    true,
+			Translation:    true,
+		},
+		ConfigSchema: whisperConfigSchema,
+		Factory: func(config *models.RecognitionConfig) (RecognitionService, error) {
+			return NewWhisperServerService(config)
+		},
+		Available: func() bool {
+			_, err := findWhisperServerPath()
+			return err == nil
+		},
+	})
+}
+
+// WhisperServerService 基于常驻whisper-server进程的语音识别服务
+// 模型只在服务启动或切换模型时加载一次，之后的识别请求直接发送到 /inference 接口
+// 多个识别任务共用一个服务进程：任务开始时登记，结束时注销，只有没有任务在使用时才切换模型或重启进程
+type WhisperServerService struct {
+	base       *WhisperService // 复用结果解析与组装逻辑，配置也由它保存
+	processor  *audio.Processor
+	serverPath string
+	httpClient *http.Client
+	ctx        context.Context // 服务的生命周期，Close时取消，取消后不再启动服务进程
+	cancel     context.CancelFunc
+
+	lifecycle     chan struct{} // 容量为1的信号量，串行化服务进程的启动与停止，等待时可被取消
+	stateMu       sync.RWMutex  // 保护以下进程状态
+	cmd           *exec.Cmd
+	exited        chan struct{}
+	baseURL       string
+	loadedModel   string
+	loadedThreads int // 启动时指定的线程数（-t），变化时需要重启进程
+	serverLog     *whisperServerLog
+	inFlight      int           // 正在使用服务进程的识别任务数
+	idle          chan struct{} // inFlight降为0时关闭
+	stale         bool          // 有任务取消后留下仍在解码的请求，没有任务在使用时停止进程
+}
+
+// whisperServerResponse /inference 接口 verbose_json 格式的响应
+type whisperServerResponse struct {
+	Error                       string                 `json:"error"`
+	Language                    string                 `json:"language"`
+	Text                        string                 `json:"text"`
+	DetectedLanguage            string                 `json:"detected_language"`
+	DetectedLanguageProbability float64                `json:"detected_language_probability"`
+	Segments                    []whisperServerSegment `json:"segments"`
+}
+
+// whisperServerSegment 响应中的段落（时间单位为秒）
+type whisperServerSegment struct {
+	Text         string              `json:"text"`
+	Start        float64             `json:"start"`
+	End          float64             `json:"end"`
+	Words        []whisperServerWord `json:"words"`
+	AvgLogprob   float64             `json:"avg_logprob"`
+	NoSpeechProb float64             `json:"no_speech_prob"`
+}
+
+// whisperServerWord 响应中的词（实际为token）
+type whisperServerWord struct {
+	Word        string  `json:"word"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Probability float64 `json:"probability"`
+}
+
+// whisperServerLog 保留whisper-server最近的输出，用于启动失败时的错误信息
+type whisperServerLog struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Write 追加输出，只保留末尾部分
+func (l *whisperServerLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf = append(l.buf, p...)
+	if len(l.buf) > whisperServerLogLimit {
+		l.buf = l.buf[len(l.buf)-whisperServerLogLimit:]
+	}
+	return len(p), nil
+}
+
+// String 获取保留的输出
+func (l *whisperServerLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.ToValidUTF8(string(l.buf), "")
+}
+
+// NewWhisperServerService 创建基于whisper-server的语音识别服务
+// 只查找可执行文件，服务进程在加载模型或首次识别时启动
+func NewWhisperServerService(config *models.RecognitionConfig) (*WhisperServerService, error) {
+	utils.LogInfo("开始初始化whisper-server语音识别服务")
+
+	serverPath, err := findWhisperServerPath()
+	if err != nil {
+		return nil, err
+	}
+
+	processor, err := audio.NewProcessor()
+	if err != nil {
+		utils.LogError("创建音频处理器失败: %v", err)
+		return nil, err
+	}
+	processor.SetFilterChain(audio.BuildFilterChain(config))
+
+	service := newWhisperServerService(config, processor, serverPath)
+	utils.LogInfo("whisper-server语音识别服务初始化完成: %s", serverPath)
+	return service, nil
+}
+
+// newWhisperServerService 使用指定的whisper-server可执行文件创建服务
+func newWhisperServerService(config *models.RecognitionConfig, processor *audio.Processor, serverPath string) *WhisperServerService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WhisperServerService{
+		base: &WhisperService{
+			processor: processor,
+			config:    config,
+			models:    make(map[string]bool),
+		},
+		processor:  processor,
+		serverPath: serverPath,
+		// 不设置整体超时，识别耗时取决于音频长度，由ctx控制取消
+		httpClient: &http.Client{},
+		ctx:        ctx,
+		cancel:     cancel,
+		lifecycle:  make(chan struct{}, 1),
+	}
+}
+
+// findWhisperServerPath 查找whisper-server可执行文件（与whisper-cli位于同一第三方依赖目录）
+func findWhisperServerPath() (string, error) {
+	var possiblePaths []string
+	if targetDir, err := path.NewDefaultTargetFinder().FindThirdPartyTargetDirectory(); err == nil {
+		possiblePaths = append(possiblePaths, filepath.Join(targetDir, "whisper-server"))
+	}
+	possiblePaths = append(possiblePaths,
+		filepath.Join(".", "third-party", "bin", "whisper-server"),
+		filepath.Join("backend", "recognition", "whisper-server"),
+	)
+
+	for _, candidate := range possiblePaths {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			if absPath, err := filepath.Abs(candidate); err == nil {
+				return absPath, nil
+			}
+			return candidate, nil
+		}
+		utils.LogDebug("路径不存在: %s", candidate)
+	}
+
+	// 系统PATH
+	if systemPath, err := exec.LookPath("whisper-server"); err == nil {
+		return systemPath, nil
+	}
+
+	return "", fmt.Errorf("未找到whisper-server可执行文件，尝试的路径: %v", possiblePaths)
+}
+
+// LoadModel 加载语音模型：在后台启动whisper-server并加载模型，识别时会等待加载完成
+func (s *WhisperServerService) LoadModel(language, modelPath string) error {
+	modelFile := s.resolveModelFile(modelPath)
+	if modelFile == "" {
+		return models.NewRecognitionError(
+			models.ErrorCodeModelNotFound,
+			"Whisper模型文件未找到",
+			"请确保在指定的模型目录中有有效的Whisper模型文件(.bin)",
+		)
+	}
+
+	// 预加载随服务关闭而取消，避免Close之后再启动服务进程
+	threads := s.base.currentConfig().Decoding.Threads
+	go func() {
+		if err := s.acquireServer(s.ctx, modelFile, threads); err != nil {
+			utils.LogError("whisper-server预加载模型失败: %v", err)
+			return
+		}
+		s.releaseServer(false)
+	}()
+	return nil
+}
+
+// resolveModelFile 根据模型路径（文件或目录）确定模型文件
+func (s *WhisperServerService) resolveModelFile(modelPath string) string {
+	if info, err := os.Stat(modelPath); err == nil && !info.IsDir() {
+		return modelPath
+	}
+	config := s.base.currentConfig()
+	modelDir := modelPath
+	if modelDir == "" {
+		modelDir = config.ModelPath
+	}
+	return findWhisperModelFile(modelDir, config.SpecificModelFile)
+}
+
+// RecognizeFile 识别音频文件
+func (s *WhisperServerService) RecognizeFile(ctx context.Context, audioPath string, language string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	return s.RecognizeFileWithOptions(ctx, audioPath, language, models.RecognitionOptions{}, progressCallback)
+}
+
+// RecognizeFileWithModel 使用指定模型文件识别音频文件，模型不同时重启服务进程
+func (s *WhisperServerService) RecognizeFileWithModel(ctx context.Context, audioPath string, language string, specificModelFile string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	return s.RecognizeFileWithOptions(ctx, audioPath, language, models.RecognitionOptions{SpecificModelFile: specificModelFile}, progressCallback)
+}
+
+// RecognizeFileWithOptions 按识别选项识别音频文件
+func (s *WhisperServerService) RecognizeFileWithOptions(ctx context.Context, audioPath string, language string, options models.RecognitionOptions, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	job, err := resolveJobOptions(s.base.currentConfig(), options)
+	if err != nil {
+		return nil, err
+	}
+	specificModelFile := options.SpecificModelFile
+	if specificModelFile == "" {
+		specificModelFile = job.config.SpecificModelFile
+	}
+	return s.recognize(ctx, audioPath, language, specificModelFile, job, progressCallback)
+}
+
+// recognize 转换音频并发送到whisper-server识别
+func (s *WhisperServerService) recognize(ctx context.Context, audioPath, language, specificModelFile string, job jobOptions, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	utils.LogInfo("开始语音识别(whisper-server)，音频文件: %s, 语言: %s, 任务: %s", audioPath, language, job.task)
+
+	if _, err := os.Stat(audioPath); err != nil {
+		utils.LogError("音频文件不存在: %s, 错误: %v", audioPath, err)
+		return nil, fmt.Errorf("音频文件不存在: %s", audioPath)
+	}
+
+	modelFile := findWhisperModelFile(job.config.ModelPath, specificModelFile)
+	if modelFile == "" {
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeModelNotFound,
+			"Whisper模型文件未找到",
+			"请确保ggml-base.bin模型文件在models/whisper/目录中",
+		)
+	}
+
+	reportProgress := func(percentage int, status string) {
+		if progressCallback != nil {
+			progressCallback(&models.RecognitionProgress{
+				Status:     status,
+				Percentage: percentage,
+			})
+		}
+	}
+
+	// whisper-server的verbose_json不输出说话人信息，stereo方式由本地比较声道功率，tinydiarize改用pause
+	convert := job.convertOptions(language)
+	diarization := planDiarization(job.diarization, modelFile,
+		probeSourceChannels(ctx, s.processor, audioPath, job.diarization.Mode, convert), false)
+
+	reportProgress(0, "正在转换音频格式...")
+	convert.Channels = diarization.channels
+	wavPath, audioInfo, err := s.processor.ConvertToWAVWithOptions(ctx, audioPath, convert)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(wavPath)
+
+	if !s.isModelReady(modelFile, job.decoding.Threads) {
+		reportProgress(5, "正在加载Whisper模型...")
+	}
+	if err := s.acquireServer(ctx, modelFile, job.decoding.Threads); err != nil {
+		if ctx.Err() != nil {
+			return nil, newCancelledError(ctx)
+		}
+		return nil, err
+	}
+	defer func() {
+		// 取消时服务端可能仍在解码被放弃的请求
+		s.releaseServer(ctx.Err() != nil)
+	}()
+
+	whisperLang := s.base.mapLanguageToWhisper(language)
+	reportProgress(10, "正在识别语音...")
+
+	// 长音频在静音处切分后逐块识别（服务端串行解码，不并行），切分失败时整段识别
+	chunks, err := splitForChunking(ctx, s.processor, wavPath, job.config)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, newCancelledError(ctx)
+		}
+		fmt.Printf("⚠️ 音频切分失败，整段识别: %v\n", err)
+		chunks = nil
+	}
+	defer audio.RemoveChunks(chunks)
+
+	recognizePass := func(ctx context.Context, translate bool, passProgress func(*models.RecognitionProgress)) (*whisperRun, error) {
+		params := whisperParams{modelPath: modelFile, language: whisperLang, translate: translate, decoding: job.decoding}
+		if len(chunks) == 0 {
+			return s.inferWAV(ctx, wavPath, params)
+		}
+		return recognizeChunks(ctx, chunks, audioInfo.ConvertedDuration, 1,
+			func(ctx context.Context, chunk audio.AudioChunk, _ func(*models.RecognitionProgress)) (*whisperRun, error) {
+				return s.inferWAV(ctx, chunk.Path, params)
+			}, passProgress)
+	}
+
+	run, translation, err := runTask(ctx, job.task, recognizePass, progressCallback)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("✅ whisper-server识别完成，段落数: %d\n", len(run.segments))
+
+	report := s.base.applyHallucinationFilter(ctx, job.config.HallucinationFilter, run, wavPath, whisperLang, job.task == models.TaskTranslate)
+	if translation != nil {
+		s.base.applyHallucinationFilter(ctx, job.config.HallucinationFilter, translation, wavPath, whisperLang, true)
+		alignTranslations(run.segments, translation.segments)
+	}
+	vocabularyReport := job.vocabulary.correct(run.segments, job.promptTerms)
+	diarizationReport := diarize(run.segments, diarization, job.diarization, analyzeVoice(ctx, wavPath, diarization))
+
+	// 只识别了部分时间范围时，把时间戳换算回原始文件
+	mapper := newTimeRangeMapper(audioInfo.TimeRanges)
+	mapper.mapSegments(run.segments)
+	mapper.mapReport(report)
+
+	result := s.base.buildRecognitionResult(run.segments, audioInfo, language)
+	report.apply(result)
+	vocabularyReport.apply(result)
+	diarizationReport.apply(result)
+	mapper.apply(result)
+	result.Metadata["recognition_type"] = "whisper_server"
+	result.Metadata["timestamp_source"] = run.timestampSource
+	result.Metadata["model_file"] = modelFile
+	if len(chunks) > 0 {
+		result.Metadata["chunk_count"] = len(chunks)
+		result.Metadata["chunk_workers"] = 1
+	}
+	s.base.applyLanguageInfo(result, language, whisperLang, run.detected, run.probability)
+	applyTaskInfo(result, job.task)
+	result.Metadata["decoding"] = job.decoding
+
+	reportProgress(100, "语音识别完成")
+	return result, nil
+}
+
+// inferWAV 识别一个WAV文件，服务进程崩溃时重启后重试一次
+// 取消时只断开本次请求，服务进程由其他任务继续使用（调用方需已通过acquireServer登记）
+func (s *WhisperServerService) inferWAV(ctx context.Context, wavPath string, params whisperParams) (*whisperRun, error) {
+	response, err := s.inference(ctx, wavPath, params)
+	if ctx.Err() != nil {
+		fmt.Printf("⏹️ whisper-server识别已取消\n")
+		return nil, newCancelledError(ctx)
+	}
+	if err != nil && s.serverExited(time.Second) {
+		// 服务进程崩溃，重启后重试一次
+		utils.LogWarn("whisper-server进程已退出，重新启动后重试: %v", err)
+		if startErr := s.ensureServer(ctx, params.modelPath, params.decoding.Threads); startErr != nil {
+			return nil, startErr
+		}
+		response, err = s.inference(ctx, wavPath, params)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, newCancelledError(ctx)
+		}
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeRecognitionFailed,
+			"Whisper语音识别失败",
+			err.Error(),
+		)
+	}
+
+	detected := response.DetectedLanguage
+	if detected == "" {
+		detected = response.Language
+	}
+	return &whisperRun{
+		segments:        s.buildSegments(response),
+		detected:        normalizeWhisperLanguage(detected),
+		probability:     response.DetectedLanguageProbability,
+		timestampSource: "token",
+	}, nil
+}
+
+// inference 以multipart形式上传WAV文件到 /inference 接口
+func (s *WhisperServerService) inference(ctx context.Context, wavPath string, params whisperParams) (*whisperServerResponse, error) {
+	s.stateMu.RLock()
+	baseURL := s.baseURL
+	s.stateMu.RUnlock()
+	if baseURL == "" {
+		return nil, fmt.Errorf("whisper-server未运行")
+	}
+
+	// 通过管道流式上传，避免将长音频整体读入内存
+	bodyReader, bodyWriter := io.Pipe()
+	writer := multipart.NewWriter(bodyWriter)
+	go func() {
+		bodyWriter.CloseWithError(writeInferenceForm(writer, wavPath, params))
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/inference", bodyReader)
+	if err != nil {
+		bodyReader.Close()
+		return nil, fmt.Errorf("创建识别请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求whisper-server失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取whisper-server响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("whisper-server返回错误状态 %d: %s", resp.StatusCode, strings.TrimSpace(string(content)))
+	}
+
+	var response whisperServerResponse
+	if err := json.Unmarshal(content, &response); err != nil {
+		return nil, fmt.Errorf("解析whisper-server响应失败: %w", err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("whisper-server识别失败: %s", response.Error)
+	}
+
+	return &response, nil
+}
+
+// writeInferenceForm 写入识别请求的表单内容
+func writeInferenceForm(writer *multipart.Writer, wavPath string, params whisperParams) error {
+	file, err := os.Open(wavPath)
+	if err != nil {
+		return fmt.Errorf("打开音频文件失败: %w", err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile("file", filepath.Base(wavPath))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("上传音频数据失败: %w", err)
+	}
+
+	fields := map[string]string{
+		"response_format": "verbose_json",
+		"language":        params.language,
+	}
+	if params.translate {
+		fields["translate"] = "true"
+	}
+	for name, value := range decodingFormFields(params.decoding) {
+		fields[name] = value
+	}
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// buildSegments 将服务端响应转换为识别段落
+func (s *WhisperServerService) buildSegments(response *whisperServerResponse) []models.RecognitionResultSegment {
+	segments := make([]models.RecognitionResultSegment, 0, len(response.Segments))
+	for _, seg := range response.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+
+		words := s.base.buildWordsFromTokens(serverWordsToTokens(seg.Words, seg.Text))
+
+		segment := models.RecognitionResultSegment{
+			Start:      seg.Start,
+			End:        seg.End,
+			Text:       s.base.convertToSimplified(text),
+			Confidence: averageWordConfidence(words),
+			Words:      words,
+			Metadata:   make(map[string]interface{}),
+		}
+		segment.Metadata["timestamp_source"] = "token"
+		if seg.NoSpeechProb > 0 {
+			segment.Metadata["no_speech_prob"] = seg.NoSpeechProb
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// serverWordsToTokens 将whisper-server返回的词转换为token
+// 服务端按token输出词文本，被拆分到多个token的多字节字符会变成U+FFFD，这里按段落文本对齐还原
+func serverWordsToTokens(words []whisperServerWord, segmentText string) []whisperJSONToken {
+	tokens := make([]whisperJSONToken, 0, len(words))
+	var broken []whisperServerWord
+	pos := 0
+
+	// flushBroken 将连续的损坏token合并为一个，文本取段落中对应的位置
+	flushBroken := func(text string) {
+		if len(broken) == 0 {
+			return
+		}
+		if strings.TrimSpace(text) != "" {
+			probs := make([]float64, 0, len(broken))
+			for _, word := range broken {
+				probs = append(probs, word.Probability)
+			}
+			tokens = append(tokens, whisperJSONToken{
+				Text: whisperRawText(text),
+				Offsets: whisperJSONOffsets{
+					From: secondsToMillis(broken[0].Start),
+					To:   secondsToMillis(broken[len(broken)-1].End),
+				},
+				P: average(probs),
+			})
+		}
+		broken = nil
+	}
+
+	for _, word := range words {
+		if strings.ContainsRune(word.Word, utf8.RuneError) {
+			broken = append(broken, word)
+			continue
+		}
+
+		token := whisperJSONToken{
+			Text:    whisperRawText(word.Word),
+			Offsets: whisperJSONOffsets{From: secondsToMillis(word.Start), To: secondsToMillis(word.End)},
+			P:       word.Probability,
+		}
+
+		trimmed := strings.TrimSpace(word.Word)
+		idx := strings.Index(segmentText[pos:], trimmed)
+		if idx < 0 {
+			// 无法对齐时放弃还原
+			broken = nil
+			tokens = append(tokens, token)
+			continue
+		}
+		flushBroken(segmentText[pos : pos+idx])
+		tokens = append(tokens, token)
+		pos += idx + len(trimmed)
+	}
+	flushBroken(segmentText[pos:])
+
+	return tokens
+}
+
+// secondsToMillis 秒转换为毫秒
+func secondsToMillis(seconds float64) int64 {
+	return int64(math.Round(seconds * 1000))
+}
+
+// acquireServer 确保whisper-server以指定模型和线程数运行并登记一个使用中的识别任务，任务结束时需调用releaseServer
+// 需要切换模型、线程数或重启进程而其他任务仍在使用时，等待这些任务结束；ctx取消或服务关闭时放弃等待
+func (s *WhisperServerService) acquireServer(ctx context.Context, modelFile string, threads int) error {
+	for {
+		if err := s.lockLifecycle(ctx); err != nil {
+			return err
+		}
+		s.stateMu.Lock()
+		running := s.cmd != nil && !isClosed(s.exited)
+		if running && !s.stale && s.loadedModel == modelFile && s.loadedThreads == threads {
+			s.addInFlightLocked()
+			s.stateMu.Unlock()
+			s.unlockLifecycle()
+			return nil
+		}
+		// 进程已退出时其他任务的请求也已失败，不必等待
+		if running && s.inFlight > 0 {
+			idle := s.idle
+			s.stateMu.Unlock()
+			s.unlockLifecycle()
+			fmt.Printf("⏳ whisper-server正被其他任务使用，等待后切换模型 %s\n", filepath.Base(modelFile))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-s.ctx.Done():
+				return newServerClosedError()
+			case <-idle:
+			}
+			continue
+		}
+		s.stateMu.Unlock()
+
+		err := s.restartLocked(ctx, modelFile, threads)
+		if err == nil {
+			s.stateMu.Lock()
+			s.addInFlightLocked()
+			s.stateMu.Unlock()
+		}
+		s.unlockLifecycle()
+		return err
+	}
+}
+
+// lockLifecycle 获取服务进程的启停权，ctx取消或服务关闭时放弃等待
+func (s *WhisperServerService) lockLifecycle(ctx context.Context) error {
+	select {
+	case s.lifecycle <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.ctx.Done():
+		return newServerClosedError()
+	}
+	// 等待期间服务可能已关闭，关闭后不再启动进程
+	if s.ctx.Err() != nil {
+		s.unlockLifecycle()
+		return newServerClosedError()
+	}
+	return nil
+}
+
+// unlockLifecycle 释放服务进程的启停权
+func (s *WhisperServerService) unlockLifecycle() {
+	<-s.lifecycle
+}
+
+// addInFlightLocked 登记一个使用中的任务（调用方需持有stateMu）
+func (s *WhisperServerService) addInFlightLocked() {
+	if s.inFlight == 0 {
+		s.idle = make(chan struct{})
+	}
+	s.inFlight++
+}
+
+// releaseServer 注销使用中的任务，cancelled为true时服务端可能仍在解码被放弃的请求
+// 没有任务在使用时停止这样的进程，避免被放弃的解码阻塞后续识别，下次识别时重新启动
+func (s *WhisperServerService) releaseServer(cancelled bool) {
+	s.stateMu.Lock()
+	if cancelled {
+		s.stale = true
+	}
+	s.inFlight--
+	stop := s.inFlight == 0 && s.stale
+	if s.inFlight == 0 {
+		close(s.idle)
+	}
+	s.stateMu.Unlock()
+
+	if !stop {
+		return
+	}
+	go func() {
+		// 服务已关闭时进程由Close停止
+		if s.lockLifecycle(s.ctx) != nil {
+			return
+		}
+		defer s.unlockLifecycle()
+		s.stateMu.RLock()
+		stop := s.inFlight == 0 && s.stale
+		s.stateMu.RUnlock()
+		if stop {
+			fmt.Printf("⏹️ 停止仍在解码已取消请求的whisper-server，下次识别时重新启动\n")
+			s.stopLocked()
+		}
+	}()
+}
+
+// isClosed 判断通道是否已关闭，nil视为已关闭
+func isClosed(ch chan struct{}) bool {
+	if ch == nil {
+		return true
+	}
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// ensureServer 确保whisper-server以指定模型和线程数运行，进程退出或模型变化时重新启动
+// 只用于已登记的任务在进程崩溃后重启，其他情况使用acquireServer
+func (s *WhisperServerService) ensureServer(ctx context.Context, modelFile string, threads int) error {
+	if err := s.lockLifecycle(ctx); err != nil {
+		return err
+	}
+	defer s.unlockLifecycle()
+
+	if s.isModelReady(modelFile, threads) {
+		return nil
+	}
+	return s.restartLocked(ctx, modelFile, threads)
+}
+
+// restartLocked 停止现有进程后以指定模型和线程数启动（调用方需持有lifecycle）
+func (s *WhisperServerService) restartLocked(ctx context.Context, modelFile string, threads int) error {
+	s.stateMu.RLock()
+	previousModel, previousThreads, hadProcess, stale := s.loadedModel, s.loadedThreads, s.cmd != nil, s.stale
+	s.stateMu.RUnlock()
+	if hadProcess {
+		switch {
+		case stale:
+			fmt.Printf("🔄 whisper-server仍在解码已取消的请求，重新启动\n")
+		case previousModel != modelFile:
+			fmt.Printf("🔄 模型切换 %s -> %s，重启whisper-server\n", filepath.Base(previousModel), filepath.Base(modelFile))
+		case previousThreads != threads:
+			fmt.Printf("🔄 线程数变化 %d -> %d，重启whisper-server\n", previousThreads, threads)
+		default:
+			fmt.Printf("🔄 whisper-server已退出，重新启动\n")
+		}
+		s.stopLocked()
+	}
+
+	return s.startLocked(ctx, modelFile, threads)
+}
+
+// startLocked 启动whisper-server并等待模型加载完成（调用方需持有lifecycle）
+func (s *WhisperServerService) startLocked(ctx context.Context, modelFile string, threads int) error {
+	port, err := findFreePort()
+	if err != nil {
+		return fmt.Errorf("分配whisper-server端口失败: %w", err)
+	}
+	baseURL := fmt.Sprintf("http://%s:%d", whisperServerHost, port)
+
+	// 服务进程生命周期独立于单次识别请求，不使用CommandContext
+	args := []string{
+		"-m", modelFile,
+		"--host", whisperServerHost,
+		"--port", strconv.Itoa(port),
+	}
+	if threads > 0 {
+		args = append(args, "-t", strconv.Itoa(threads)) // 线程数只能在启动时指定
+	}
+	cmd := exec.Command(s.serverPath, args...)
+	serverLog := &whisperServerLog{}
+	cmd.Stdout = serverLog
+	cmd.Stderr = serverLog
+
+	fmt.Printf("🚀 启动whisper-server: %s\n", cmd.String())
+	if err := cmd.Start(); err != nil {
+		return models.NewRecognitionError(
+			models.ErrorCodeModelLoadFailed,
+			"启动whisper-server失败",
+			err.Error(),
+		)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		utils.LogWarn("whisper-server进程已退出: %v", err)
+		close(exited)
+	}()
+
+	s.stateMu.Lock()
+	s.cmd = cmd
+	s.exited = exited
+	s.baseURL = baseURL
+	s.loadedModel = ""
+	s.serverLog = serverLog
+	s.stateMu.Unlock()
+
+	if err := s.waitReady(ctx, baseURL, exited); err != nil {
+		s.stopLocked()
+		if ctx.Err() != nil || s.ctx.Err() != nil {
+			return err
+		}
+		return models.NewRecognitionError(
+			models.ErrorCodeModelLoadFailed,
+			"whisper-server加载模型失败",
+			fmt.Sprintf("%v\n输出: %s", err, serverLog.String()),
+		)
+	}
+
+	s.stateMu.Lock()
+	s.loadedModel = modelFile
+	s.loadedThreads = threads
+	s.stateMu.Unlock()
+
+	utils.LogInfo("whisper-server已就绪: %s, 模型: %s", baseURL, modelFile)
+	return nil
+}
+
+// waitReady 轮询健康检查接口直到模型加载完成
+func (s *WhisperServerService) waitReady(ctx context.Context, baseURL string, exited <-chan struct{}) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.NewTimer(whisperServerStartTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.ctx.Done():
+			return newServerClosedError()
+		case <-exited:
+			return fmt.Errorf("whisper-server启动后立即退出")
+		case <-deadline.C:
+			return fmt.Errorf("等待whisper-server就绪超时(%v)", whisperServerStartTimeout)
+		case <-ticker.C:
+			resp, err := client.Get(baseURL + "/health")
+			if err != nil {
+				continue // 模型加载完成前端口尚未监听
+			}
+			resp.Body.Close()
+			// 旧版本没有 /health 接口，但同样在模型加载完成后才开始监听
+			if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNotFound {
+				return nil
+			}
+		}
+	}
+}
+
+// stopServer 停止whisper-server进程，等待进行中的启动结束
+func (s *WhisperServerService) stopServer() {
+	s.lifecycle <- struct{}{}
+	defer s.unlockLifecycle()
+	s.stopLocked()
+}
+
+// stopLocked 终止进程并清空状态（调用方需持有lifecycle）
+func (s *WhisperServerService) stopLocked() {
+	s.stateMu.Lock()
+	cmd, exited := s.cmd, s.exited
+	s.cmd = nil
+	s.exited = nil
+	s.baseURL = ""
+	s.loadedModel = ""
+	s.loadedThreads = 0
+	s.stale = false
+	s.stateMu.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+
+	if err := cmd.Process.Kill(); err != nil {
+		utils.LogDebug("终止whisper-server进程失败: %v", err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(whisperServerStopTimeout):
+		utils.LogWarn("等待whisper-server退出超时")
+	}
+}
+
+// isRunning 检查服务进程是否在运行
+func (s *WhisperServerService) isRunning() bool {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	if s.cmd == nil {
+		return false
+	}
+	select {
+	case <-s.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// serverExited 等待服务进程退出，用于判断请求失败是否由进程崩溃导致
+func (s *WhisperServerService) serverExited(timeout time.Duration) bool {
+	s.stateMu.RLock()
+	exited := s.exited
+	s.stateMu.RUnlock()
+	if exited == nil {
+		return true
+	}
+	select {
+	case <-exited:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// isModelReady 检查服务进程是否在运行且以指定线程数加载了指定模型
+func (s *WhisperServerService) isModelReady(modelFile string, threads int) bool {
+	if !s.isRunning() {
+		return false
+	}
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.loadedModel != "" && s.loadedModel == modelFile && s.loadedThreads == threads
+}
+
+// findFreePort 获取本机可用端口
+func findFreePort() (int, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(whisperServerHost, "0"))
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// newServerClosedError 创建服务已关闭错误
+func newServerClosedError() error {
+	return models.NewRecognitionError(
+		models.ErrorCodeRecognitionFailed,
+		"whisper-server识别服务已关闭",
+		"",
+	)
+}
+
+// newCancelledError 创建识别取消错误
+func newCancelledError(ctx context.Context) error {
+	return models.NewRecognitionError(
+		models.ErrorCodeRecognitionCancelled,
+		"语音识别已取消",
+		ctx.Err().Error(),
+	)
+}
+
+// GetLoadedModel 获取当前已加载的模型文件，未加载时返回空字符串
+func (s *WhisperServerService) GetLoadedModel() string {
+	if !s.isRunning() {
+		return ""
+	}
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.loadedModel
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (s *WhisperServerService) GetSupportedLanguages() []string {
+	return s.base.GetSupportedLanguages()
+}
+
+// IsModelLoaded 检查模型是否已加载（服务进程在运行且模型加载完成）
+func (s *WhisperServerService) IsModelLoaded(language string) bool {
+	return s.GetLoadedModel() != ""
+}
+
+// UnloadModel 卸载语音模型（停止服务进程释放内存）
+func (s *WhisperServerService) UnloadModel(language string) error {
+	s.stopServer()
+	return nil
+}
+
+// UpdateConfig 更新配置，模型或线程数变化会在下次识别时触发重启，进行中的识别仍使用开始时的配置
+func (s *WhisperServerService) UpdateConfig(config *models.RecognitionConfig) {
+	s.base.UpdateConfig(config)
+}
+
+// Close 关闭服务：取消预加载和等待中的启动，停止服务进程，之后不再启动
+func (s *WhisperServerService) Close() error {
+	s.cancel()
+	s.stopServer()
+	if s.processor != nil {
+		return s.processor.Cleanup()
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package recognition
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+)
+
+// newTestWhisperServerService 使用测试程序自身作为whisper-server替身
+func newTestWhisperServerService(t *testing.T, config *models.RecognitionConfig) *WhisperServerService {
+	t.Helper()
+	t.Setenv("FAKE_WHISPER_SERVER", "1")
+	processor, err := audio.NewProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := newWhisperServerService(config, processor, os.Args[0])
+	t.Cleanup(func() { service.Close() })
+	return service
+}
+
+// waitFor 轮询直到条件成立
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待超时: %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// TestWhisperServerCancelKeepsSharedServer 取消一个任务只断开它的请求，共用服务进程的其他任务正常完成
+// 没有任务在使用后，仍在解码被放弃请求的进程被停止，下次识别时重新启动
+func TestWhisperServerCancelKeepsSharedServer(t *testing.T) {
+	installFakeFFmpeg(t)
+	modelDir := writeTestModels(t, "ggml-base.bin")
+	service := newTestWhisperServerService(t, testConfig(modelDir))
+	audioPath := filepath.Join(t.TempDir(), "input.mp3")
+	writeTestWAV(t, audioPath, 16000)
+
+	recognize := func(ctx context.Context, language string) (*models.RecognitionResult, error) {
+		return service.RecognizeFileWithOptions(ctx, audioPath, language, models.RecognitionOptions{}, nil)
+	}
+	inFlight := func() int {
+		service.stateMu.RLock()
+		defer service.stateMu.RUnlock()
+		return service.inFlight
+	}
+
+	// 英文请求解码较慢，中途取消
+	cancelled := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_, err := recognize(ctx, "en")
+		cancelled <- err
+	}()
+	waitFor(t, "第一个任务开始识别", func() bool { return inFlight() == 1 })
+
+	completed := make(chan error, 1)
+	go func() {
+		_, err := recognize(context.Background(), "zh-CN")
+		completed <- err
+	}()
+	waitFor(t, "第二个任务开始识别", func() bool { return inFlight() == 2 })
+	time.Sleep(100 * time.Millisecond) // 等两个请求都发到服务端
+	cancel()
+
+	if err := <-cancelled; !isRecognitionCancelled(err) {
+		t.Fatalf("第一个任务应返回取消错误，实际: %v", err)
+	}
+	if !service.isRunning() {
+		t.Fatal("取消一个任务后服务进程不应停止")
+	}
+	if err := <-completed; err != nil {
+		t.Fatalf("第二个任务识别失败: %v", err)
+	}
+
+	// 两个任务都结束后，停止仍在解码已取消请求的进程
+	waitFor(t, "停止服务进程", func() bool { return !service.isRunning() })
+
+	result, err := recognize(context.Background(), "zh-CN")
+	if err != nil {
+		t.Fatalf("重新启动后识别失败: %v", err)
+	}
+	if len(result.Segments) != 1 {
+		t.Errorf("段落数为 %d，期望 1", len(result.Segments))
+	}
+	if inFlight() != 0 {
+		t.Errorf("识别结束后仍登记了 %d 个任务", inFlight())
+	}
+}
+
+// isRecognitionCancelled 判断错误是否为识别取消
+func isRecognitionCancelled(err error) bool {
+	recognitionErr, ok := err.(*models.RecognitionError)
+	return ok && recognitionErr.Code == models.ErrorCodeRecognitionCancelled
+}
+
+// serverThreads 服务进程启动参数中的线程数，未指定时为空字符串
+func serverThreads(service *WhisperServerService) string {
+	service.stateMu.RLock()
+	defer service.stateMu.RUnlock()
+	if service.cmd == nil {
+		return ""
+	}
+	for i, arg := range service.cmd.Args {
+		if arg == "-t" && i+1 < len(service.cmd.Args) {
+			return service.cmd.Args[i+1]
+		}
+	}
+	return ""
+}
+
+// TestWhisperServerThreadsChangeRestarts 线程数只能在启动时指定，全局配置或单个任务的线程数变化时重启服务进程
+func TestWhisperServerThreadsChangeRestarts(t *testing.T) {
+	installFakeFFmpeg(t)
+	modelDir := writeTestModels(t, "ggml-base.bin")
+	config := testConfig(modelDir)
+	config.Decoding.Threads = 2
+	service := newTestWhisperServerService(t, config)
+	audioPath := filepath.Join(t.TempDir(), "input.mp3")
+	writeTestWAV(t, audioPath, 16000)
+
+	recognize := func(options models.RecognitionOptions) *models.RecognitionResult {
+		t.Helper()
+		result, err := service.RecognizeFileWithOptions(context.Background(), audioPath, "zh-CN", options, nil)
+		if err != nil {
+			t.Fatalf("识别失败: %v", err)
+		}
+		return result
+	}
+
+	recognize(models.RecognitionOptions{})
+	if threads := serverThreads(service); threads != "2" {
+		t.Fatalf("服务进程线程数为 %q，期望 2", threads)
+	}
+
+	result := recognize(models.RecognitionOptions{Decoding: &models.DecodingOptions{Threads: 4}})
+	if threads := serverThreads(service); threads != "4" {
+		t.Errorf("任务指定线程数后服务进程线程数为 %q，期望 4", threads)
+	}
+	if decoding, ok := result.Metadata["decoding"].(models.DecodingOptions); !ok || decoding.Threads != 4 {
+		t.Errorf("结果中的解码参数为 %+v，期望线程数 4", result.Metadata["decoding"])
+	}
+
+	updated := testConfig(modelDir)
+	updated.Decoding.Threads = 3
+	service.UpdateConfig(updated)
+	recognize(models.RecognitionOptions{})
+	if threads := serverThreads(service); threads != "3" {
+		t.Errorf("更新配置后服务进程线程数为 %q，期望 3", threads)
+	}
+}
+
+// TestWhisperServerCloseStopsPreload Close之后预加载不再启动服务进程，之后的识别直接失败
+func TestWhisperServerCloseStopsPreload(t *testing.T) {
+	installFakeFFmpeg(t)
+	modelDir := writeTestModels(t, "ggml-base.bin")
+	service := newTestWhisperServerService(t, testConfig(modelDir))
+
+	if err := service.LoadModel("zh-CN", modelDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Close(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond) // 给预加载足够的时间启动进程
+	if service.isRunning() {
+		t.Fatal("Close之后预加载仍启动了服务进程")
+	}
+
+	err := service.acquireServer(context.Background(), filepath.Join(modelDir, "ggml-base.bin"), 0)
+	if err == nil {
+		t.Fatal("Close之后仍能启动服务进程")
+	}
+	if service.isRunning() {
+		t.Error("Close之后不应启动服务进程")
+	}
+}
+
+// TestWhisperServerAcquireCancelWhileWaiting 等待其他任务启动服务进程时，取消ctx立即返回
+func TestWhisperServerAcquireCancelWhileWaiting(t *testing.T) {
+	installFakeFFmpeg(t)
+	modelDir := writeTestModels(t, "ggml-base.bin")
+	service := newTestWhisperServerService(t, testConfig(modelDir))
+
+	// 模拟另一个任务正在启动服务进程
+	service.lifecycle <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- service.acquireServer(ctx, filepath.Join(modelDir, "ggml-base.bin"), 0)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-acquired:
+		if err != context.Canceled {
+			t.Errorf("取消后返回 %v，期望 context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消后仍在等待启动服务进程")
+	}
+	service.unlockLifecycle()
+	if service.isRunning() {
+		t.Error("取消的任务不应启动服务进程")
+	}
+}
@@ -1,11 +1,12 @@
 package recognition
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,6 +17,30 @@ import (
 	"tingshengbianzi/backend/utils"
 )
 
+func init() {
+	RegisterEngine(EngineDescriptor{
+		Name:        "whisper-cli",
+		DisplayName: "Whisper CLI",
+		Description: "每个文件启动一次whisper-cli进程，实时输出识别进度和段落",
+		Priority:    10,
+		Capabilities: EngineCapabilities{
+			Languages:      languageCodes(),
+			WordTimestamps: true,
+			Diarization:    true,
+			Streaming:      true,
+			Translation:    true,
+		},
+		ConfigSchema: whisperConfigSchema,
+		Factory: func(config *models.RecognitionConfig) (RecognitionService, error) {
+			return NewWhisperService(config)
+		},
+		Available: func() bool {
+			_, err := findWhisperCLIPath()
+			return err == nil
+		},
+	})
+}
+
 // formatFileSize 格式化文件大小
 func formatFileSize(bytes int64) string {
 	if bytes == 0 {
@@ -40,15 +65,26 @@ func formatFileSize(bytes int64) string {
 // WhisperService Whisper语音识别服务
 type WhisperService struct {
 	processor     *audio.Processor
-	config        *models.RecognitionConfig
+	configMu      sync.RWMutex
+	config        *models.RecognitionConfig // 只整体替换，不修改字段（识别任务持有的是开始时的配置）
 	models        map[string]bool
 	modelsLock    sync.RWMutex
 	hasRealModel  bool
 	whisperPath   string
 }
 
+// WhisperServiceOptions Whisper服务创建选项
+type WhisperServiceOptions struct {
+	WhisperPath string // whisper-cli可执行文件路径，留空时自动查找（测试时可指定替身程序）
+}
+
 // NewWhisperService 创建新的Whisper语音识别服务
 func NewWhisperService(config *models.RecognitionConfig) (*WhisperService, error) {
+	return NewWhisperServiceWithOptions(config, WhisperServiceOptions{})
+}
+
+// NewWhisperServiceWithOptions 按选项创建Whisper语音识别服务
+func NewWhisperServiceWithOptions(config *models.RecognitionConfig, options WhisperServiceOptions) (*WhisperService, error) {
 	utils.LogInfo("开始初始化Whisper语音识别服务")
 
 	// 创建音频处理器
@@ -57,8 +93,47 @@ func NewWhisperService(config *models.RecognitionConfig) (*WhisperService, error
 		utils.LogError("创建音频处理器失败: %v", err)
 		return nil, err
 	}
+	processor.SetFilterChain(audio.BuildFilterChain(config))
 	utils.LogInfo("音频处理器创建成功")
 
+	whisperPath := options.WhisperPath
+	if whisperPath != "" {
+		if _, err := os.Stat(whisperPath); err != nil {
+			return nil, fmt.Errorf("指定的whisper-cli不存在: %s", whisperPath)
+		}
+		utils.LogInfo("使用指定的Whisper CLI: %s", whisperPath)
+	} else {
+		whisperPath, err = findWhisperCLIPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	service := &WhisperService{
+		processor:    processor,
+		config:       config,
+		models:       make(map[string]bool),
+		hasRealModel: false,
+		whisperPath:  whisperPath,
+	}
+	utils.LogInfo("WhisperService结构体创建成功")
+
+	// 检查是否有真实模型文件
+	utils.LogDebug("开始检查Whisper模型文件，模型路径: %s", config.ModelPath)
+	if service.checkWhisperModel(config.ModelPath, config.SpecificModelFile) {
+		utils.LogInfo("检测到Whisper模型文件，将使用真实语音识别")
+		service.hasRealModel = true
+		service.models["default"] = true
+	} else {
+		utils.LogError("未检测到Whisper模型文件，无法进行语音识别")
+	}
+
+	utils.LogInfo("Whisper语音识别服务初始化完成")
+	return service, nil
+}
+
+// findWhisperCLIPath 查找whisper-cli可执行文件
+func findWhisperCLIPath() (string, error) {
 	// 获取可执行文件所在目录
 	exePath, err := os.Executable()
 	if err != nil {
@@ -113,50 +188,30 @@ func NewWhisperService(config *models.RecognitionConfig) (*WhisperService, error
 
 	if whisperPath == "" {
 		utils.LogError("未找到whisper-cli可执行文件，尝试的路径: %v", possiblePaths)
-		return nil, fmt.Errorf("未找到whisper-cli可执行文件，请确保文件存在于backend/recognition/目录中")
+		return "", fmt.Errorf("未找到whisper-cli可执行文件，请确保文件存在于backend/recognition/目录中")
 	}
 
-	service := &WhisperService{
-		processor:    processor,
-		config:       config,
-		models:       make(map[string]bool),
-		hasRealModel: false,
-		whisperPath:  whisperPath,
-	}
-	utils.LogInfo("WhisperService结构体创建成功")
-
-	// 检查是否有真实模型文件
-	utils.LogDebug("开始检查Whisper模型文件，模型路径: %s", config.ModelPath)
-	if service.checkWhisperModel() {
-		utils.LogInfo("检测到Whisper模型文件，将使用真实语音识别")
-		service.hasRealModel = true
-		service.models["default"] = true
-	} else {
-		utils.LogError("未检测到Whisper模型文件，无法进行语音识别")
-	}
-
-	utils.LogInfo("Whisper语音识别服务初始化完成")
-	return service, nil
+	return whisperPath, nil
 }
 
 // checkWhisperModel 检查Whisper模型文件是否存在
-func (s *WhisperService) checkWhisperModel() bool {
+func (s *WhisperService) checkWhisperModel(modelDir, specificModelFile string) bool {
 	utils.LogDebug("检查模型文件是否存在")
 
 	// 首先检查是否指定了具体的模型文件
-	if s.config.SpecificModelFile != "" {
-		utils.LogDebug("检查指定的模型文件: %s", s.config.SpecificModelFile)
-		if _, err := os.Stat(s.config.SpecificModelFile); err == nil {
-			utils.LogInfo("找到指定模型文件: %s", s.config.SpecificModelFile)
+	if specificModelFile != "" {
+		utils.LogDebug("检查指定的模型文件: %s", specificModelFile)
+		if _, err := os.Stat(specificModelFile); err == nil {
+			utils.LogInfo("找到指定模型文件: %s", specificModelFile)
 			return true
 		} else {
-			utils.LogWarn("指定的模型文件不存在: %s", s.config.SpecificModelFile)
+			utils.LogWarn("指定的模型文件不存在: %s", specificModelFile)
 		}
 	}
 
 	// 扫描模型目录中的所有可用模型
-	utils.LogDebug("扫描模型目录: %s", s.config.ModelPath)
-	if entries, err := os.ReadDir(s.config.ModelPath); err == nil {
+	utils.LogDebug("扫描模型目录: %s", modelDir)
+	if entries, err := os.ReadDir(modelDir); err == nil {
 		modelCount := 0
 		var foundModels []string
 		for _, entry := range entries {
@@ -166,16 +221,16 @@ func (s *WhisperService) checkWhisperModel() bool {
 			}
 		}
 		if modelCount > 0 {
-			utils.LogInfo("找到 %d 个有效模型文件在目录: %s", modelCount, s.config.ModelPath)
+			utils.LogInfo("找到 %d 个有效模型文件在目录: %s", modelCount, modelDir)
 			for _, model := range foundModels {
 				utils.LogDebug("发现模型: %s", model)
 			}
 			return true
 		} else {
-			utils.LogWarn("模型目录存在但未找到有效的.bin模型文件: %s", s.config.ModelPath)
+			utils.LogWarn("模型目录存在但未找到有效的.bin模型文件: %s", modelDir)
 		}
 	} else {
-		utils.LogError("无法读取模型目录: %s, 错误: %v", s.config.ModelPath, err)
+		utils.LogError("无法读取模型目录: %s, 错误: %v", modelDir, err)
 	}
 
 	return false
@@ -274,11 +329,8 @@ func (s *WhisperService) isValidWhisperModelName(fileName string) bool {
 
 // LoadModel 加载语音模型（Whisper使用统一模型）
 func (s *WhisperService) LoadModel(language, modelPath string) error {
-	// 更新配置中的模型路径
-	s.config.ModelPath = modelPath
-
 	// 检查是否有可用的模型文件
-	if !s.checkWhisperModel() {
+	if !s.checkWhisperModel(modelPath, s.currentConfig().SpecificModelFile) {
 		return models.NewRecognitionError(
 			models.ErrorCodeModelNotFound,
 			"Whisper模型文件未找到",
@@ -286,6 +338,13 @@ func (s *WhisperService) LoadModel(language, modelPath string) error {
 		)
 	}
 
+	// 复制配置后更新模型路径，进行中的识别仍使用原来的配置
+	s.configMu.Lock()
+	updated := *s.config
+	updated.ModelPath = modelPath
+	s.config = &updated
+	s.configMu.Unlock()
+
 	s.modelsLock.Lock()
 	defer s.modelsLock.Unlock()
 
@@ -295,8 +354,24 @@ func (s *WhisperService) LoadModel(language, modelPath string) error {
 }
 
 // RecognizeFile 识别音频文件
-func (s *WhisperService) RecognizeFile(audioPath string, language string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
-	utils.LogInfo("开始语音识别，音频文件: %s, 语言: %s", audioPath, language)
+func (s *WhisperService) RecognizeFile(ctx context.Context, audioPath string, language string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	return s.RecognizeFileWithOptions(ctx, audioPath, language, models.RecognitionOptions{}, progressCallback)
+}
+
+// RecognizeFileWithOptions 按识别选项识别音频文件
+func (s *WhisperService) RecognizeFileWithOptions(ctx context.Context, audioPath string, language string, options models.RecognitionOptions, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	job, err := resolveJobOptions(s.currentConfig(), options)
+	if err != nil {
+		return nil, err
+	}
+	utils.LogInfo("开始语音识别，音频文件: %s, 语言: %s, 任务: %s", audioPath, language, job.task)
+
+	// 本次识别使用的模型文件作为参数传递，不修改共享的配置（多个识别任务可能同时进行）
+	specificModelFile := job.config.SpecificModelFile
+	if options.SpecificModelFile != "" {
+		specificModelFile = options.SpecificModelFile
+		fmt.Printf("🎯 使用用户指定的模型文件: %s\n", options.SpecificModelFile)
+	}
 
 	// 检查音频文件是否存在
 	if _, err := os.Stat(audioPath); err != nil {
@@ -309,7 +384,10 @@ func (s *WhisperService) RecognizeFile(audioPath string, language string, progre
 		utils.LogDebug("音频文件信息: 大小=%d bytes", info.Size())
 	}
 
-	if !s.hasRealModel {
+	s.modelsLock.RLock()
+	hasRealModel := s.hasRealModel
+	s.modelsLock.RUnlock()
+	if !hasRealModel {
 		utils.LogError("没有真实Whisper模型，无法进行语音识别")
 		return nil, models.NewRecognitionError(
 			models.ErrorCodeModelNotFound,
@@ -320,7 +398,7 @@ func (s *WhisperService) RecognizeFile(audioPath string, language string, progre
 
 	utils.LogInfo("使用真实Whisper CLI进行识别")
 	// 使用真实的Whisper CLI进行识别
-	result, err := s.realWhisperRecognition(audioPath, language, progressCallback)
+	result, err := s.realWhisperRecognition(ctx, audioPath, language, specificModelFile, job, progressCallback)
 	if err != nil {
 		utils.LogError("真实Whisper识别失败: %v", err)
 	} else {
@@ -333,125 +411,197 @@ func (s *WhisperService) RecognizeFile(audioPath string, language string, progre
 }
 
 // realWhisperRecognition 使用真实的Whisper CLI进行语音识别
-func (s *WhisperService) realWhisperRecognition(audioPath string, language string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
-	// 获取音频文件信息
-	wavPath, audioInfo, err := s.processor.ConvertToWAV(audioPath)
+func (s *WhisperService) realWhisperRecognition(ctx context.Context, audioPath string, language string, specificModelFile string, job jobOptions, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	// 查找Whisper模型文件
+	modelPath := findWhisperModelFile(job.config.ModelPath, specificModelFile)
+
+	if modelPath == "" {
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeModelNotFound,
+			"Whisper模型文件未找到",
+			"请确保ggml-base.bin模型文件在models/whisper/目录中",
+		)
+	}
+
+	// 确定说话人分离方式（tinydiarize可能换用tdrz模型，stereo需要保留双声道）
+	convert := job.convertOptions(language)
+	diarization := planDiarization(job.diarization, modelPath,
+		probeSourceChannels(ctx, s.processor, audioPath, job.diarization.Mode, convert), true)
+	modelPath = diarization.modelPath
+
+	// 获取音频文件信息（视频文件只提取选中的音轨）
+	convert.Channels = diarization.channels
+	wavPath, audioInfo, err := s.processor.ConvertToWAVWithOptions(ctx, audioPath, convert)
 	if err != nil {
 		return nil, err
 	}
 	defer os.Remove(wavPath) // 清理临时文件
 
-	// 查找Whisper模型文件
-	modelPath := ""
+	// 映射语言代码
+	whisperLang := s.mapLanguageToWhisper(language)
 
-	// 首先检查是否指定了具体的模型文件
-	if s.config.SpecificModelFile != "" {
-		if _, err := os.Stat(s.config.SpecificModelFile); err == nil {
-			modelPath = s.config.SpecificModelFile
-			fmt.Printf("✅ 使用指定的模型文件: %s\n", modelPath)
-		} else {
-			fmt.Printf("⚠️ 指定的模型文件不存在: %s，将使用默认查找逻辑\n", s.config.SpecificModelFile)
-		}
+	// 发送初始进度
+	if progressCallback != nil {
+		progressCallback(&models.RecognitionProgress{
+			Status:     "正在初始化Whisper引擎...",
+			Percentage: 0,
+		})
 	}
 
-	// 如果指定的模型文件不存在，则使用智能查找逻辑
-	if modelPath == "" {
-		// 首先尝试在指定目录中查找所有可用的模型文件
-		modelDir := s.config.ModelPath
-		if s.config.SpecificModelFile != "" {
-			// 如果用户指定了具体文件但不存在，只在该目录下查找
-			modelDir = filepath.Dir(s.config.SpecificModelFile)
-		}
-
-		// 获取模型目录下的所有.bin文件
-		var availableModels []string
-		if files, err := os.ReadDir(modelDir); err == nil {
-			for _, file := range files {
-				if strings.HasSuffix(file.Name(), ".bin") {
-					fullPath := filepath.Join(modelDir, file.Name())
-					if info, err := os.Stat(fullPath); err == nil && !info.IsDir() {
-						availableModels = append(availableModels, fullPath)
-						fmt.Printf("🔍 发现可用模型: %s (%s)\n", file.Name(), formatFileSize(info.Size()))
-					}
-				}
-			}
+	// 长音频在静音处切分后并行识别，切分失败时整段识别
+	chunks, err := splitForChunking(ctx, s.processor, wavPath, job.config)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, newCancelledError(ctx)
 		}
+		fmt.Printf("⚠️ 音频切分失败，整段识别: %v\n", err)
+		chunks = nil
+	}
+	defer audio.RemoveChunks(chunks)
 
-		// 优先级选择模型（按质量和大小排序）
-		preferredOrder := []string{
-			"ggml-large-v3.bin", "ggml-large-v2.bin", "ggml-large-v1.bin", "ggml-large.bin",
-			"ggml-medium.bin",
-			"ggml-small.bin",
-			"ggml-base.bin",
-			"ggml-tiny.bin",
+	workers := 0
+	if len(chunks) > 0 {
+		workers = min(chunkWorkersFromConfig(job.config), len(chunks))
+	}
+	recognizePass := func(ctx context.Context, translate bool, passProgress func(*models.RecognitionProgress)) (*whisperRun, error) {
+		params := whisperParams{
+			modelPath:   modelPath,
+			language:    whisperLang,
+			threads:     job.decoding.Threads,
+			translate:   translate,
+			decoding:    job.decoding,
+			diarization: diarization.method,
 		}
-
-		// 按优先级查找模型
-		for _, preferred := range preferredOrder {
-			for _, available := range availableModels {
-				if strings.HasSuffix(available, preferred) {
-					modelPath = available
-					fmt.Printf("✅ 选择模型文件: %s\n", modelPath)
-					break
-				}
-			}
-			if modelPath != "" {
-				break
-			}
+		if len(chunks) == 0 {
+			return s.runWhisperCLI(ctx, wavPath, audioInfo.ConvertedDuration, params, passProgress)
 		}
-
-		// 如果没有找到优先模型，使用第一个可用的模型
-		if modelPath == "" && len(availableModels) > 0 {
-			modelPath = availableModels[0]
-			fmt.Printf("✅ 使用第一个可用模型: %s\n", modelPath)
+		if params.threads == 0 {
+			params.threads = max(1, runtime.NumCPU()/workers)
 		}
+		return recognizeChunks(ctx, chunks, audioInfo.ConvertedDuration, workers,
+			func(ctx context.Context, chunk audio.AudioChunk, chunkProgress func(*models.RecognitionProgress)) (*whisperRun, error) {
+				return s.runWhisperCLI(ctx, chunk.Path, chunk.Duration(), params, chunkProgress)
+			}, passProgress)
 	}
 
-	if modelPath == "" {
-		return nil, models.NewRecognitionError(
-			models.ErrorCodeModelNotFound,
-			"Whisper模型文件未找到",
-			"请确保ggml-base.bin模型文件在models/whisper/目录中",
-		)
+	run, translation, err := runTask(ctx, job.task, recognizePass, progressCallback)
+	if err != nil {
+		return nil, err
 	}
 
-	// 映射语言代码
-	whisperLang := s.mapLanguageToWhisper(language)
+	report := s.applyHallucinationFilter(ctx, job.config.HallucinationFilter, run, wavPath, whisperLang, job.task == models.TaskTranslate)
+	if translation != nil {
+		s.applyHallucinationFilter(ctx, job.config.HallucinationFilter, translation, wavPath, whisperLang, true)
+		alignTranslations(run.segments, translation.segments)
+	}
+	vocabularyReport := job.vocabulary.correct(run.segments, job.promptTerms)
+	diarizationReport := diarize(run.segments, diarization, job.diarization, analyzeVoice(ctx, wavPath, diarization))
+
+	// 只识别了部分时间范围时，把时间戳换算回原始文件
+	mapper := newTimeRangeMapper(audioInfo.TimeRanges)
+	mapper.mapSegments(run.segments)
+	mapper.mapReport(report)
+
+	result := s.buildRecognitionResult(run.segments, audioInfo, language)
+	report.apply(result)
+	vocabularyReport.apply(result)
+	diarizationReport.apply(result)
+	mapper.apply(result)
+	result.Metadata["timestamp_source"] = run.timestampSource
+	result.Metadata["model_file"] = modelPath
+	if len(chunks) > 0 {
+		result.Metadata["chunk_count"] = len(chunks)
+		result.Metadata["chunk_workers"] = workers
+	}
+	s.applyLanguageInfo(result, language, whisperLang, run.detected, run.probability)
+	applyTaskInfo(result, job.task)
+	result.Metadata["decoding"] = job.decoding
 
-	// 发送初始进度
+	// 发送完成进度
 	if progressCallback != nil {
 		progressCallback(&models.RecognitionProgress{
-			Status:     "正在初始化Whisper引擎...",
-			Percentage: 0,
+			Status:     "语音识别完成",
+			Percentage: 100,
 		})
 	}
 
+	return result, nil
+}
+
+// whisperParams 一次whisper-cli识别的参数
+type whisperParams struct {
+	modelPath   string
+	language    string // Whisper语言代码
+	threads     int    // 线程数，0时使用whisper-cli默认值
+	translate   bool   // 是否翻译为英文
+	decoding    models.DecodingOptions
+	diarization string // 说话人分离方式，tinydiarize和stereo时分别传入 -tdrz、-di
+}
+
+// runWhisperCLI 对WAV文件执行一次whisper-cli并解析结果
+func (s *WhisperService) runWhisperCLI(ctx context.Context, wavPath string, duration float64, params whisperParams, progressCallback func(*models.RecognitionProgress)) (*whisperRun, error) {
 	// 准备Whisper CLI命令
 	outputBase := strings.TrimSuffix(wavPath, filepath.Ext(wavPath))
 
 	// 输出调试信息
 	fmt.Printf("🎯 开始Whisper识别:\n")
-	fmt.Printf("   模型文件: %s\n", modelPath)
+	fmt.Printf("   模型文件: %s\n", params.modelPath)
 	fmt.Printf("   音频文件: %s\n", wavPath)
-	fmt.Printf("   识别语言: %s\n", whisperLang)
+	fmt.Printf("   识别语言: %s\n", params.language)
+	fmt.Printf("   翻译为英文: %v\n", params.translate)
+	if params.diarization != "" {
+		fmt.Printf("   说话人分离: %s\n", params.diarization)
+	}
 	fmt.Printf("   Whisper CLI: %s\n", s.whisperPath)
 
-	cmd := exec.Command(s.whisperPath,
-		"-m", modelPath,
+	args := []string{
+		"-m", params.modelPath,
 		"-f", wavPath,
-		"-l", whisperLang,
-		"-osrt", // 输出为SRT格式（包含时间戳）
+		"-l", params.language,
+		"-ojf",  // 输出完整JSON（包含token级时间戳和概率）
+		"-osrt", // 同时输出SRT，作为JSON解析失败时的备选
+		"-pp",   // 输出解码进度，用于实时进度上报
 		"-of", outputBase,
-	)
+	}
+	if params.threads > 0 {
+		args = append(args, "-t", strconv.Itoa(params.threads)) // 并行识别多个分块时平分CPU
+	}
+	if params.translate {
+		args = append(args, "-tr")
+	}
+	switch params.diarization {
+	case DiarizationTinydiarize:
+		args = append(args, "-tdrz")
+	case DiarizationStereo:
+		args = append(args, "-di")
+	}
+	args = append(args, decodingCLIArgs(params.decoding)...)
+	cmd := exec.CommandContext(ctx, s.whisperPath, args...)
+
+	// 生成的结果文件，无论成功与否都需要清理
+	jsonFile := outputBase + ".json"
+	srtFile := outputBase + ".srt"
+	defer os.Remove(jsonFile)
+	defer os.Remove(srtFile)
 
 	// 不设置工作目录，使用绝对路径来避免路径问题
 
-	// 执行Whisper识别
-	output, err := cmd.CombinedOutput()
-	fmt.Printf("🔍 Whisper CLI 输出: %s\n", string(output))
+	// 执行Whisper识别，逐行解析输出以上报真实进度和实时段落
+	tracker := newWhisperProgressTracker(duration, progressCallback, s.convertToSimplified)
+	output, err := runWhisperCommand(ctx, cmd, tracker.handleLine)
+	fmt.Printf("🔍 Whisper CLI 输出: %s\n", output)
+
+	if ctx.Err() != nil {
+		fmt.Printf("⏹️ Whisper识别已取消: %v\n", ctx.Err())
+		return nil, models.NewRecognitionError(
+			models.ErrorCodeRecognitionCancelled,
+			"语音识别已取消",
+			ctx.Err().Error(),
+		)
+	}
 
 	if err != nil {
-		errorMsg := fmt.Sprintf("Whisper CLI执行失败: %v\n输出: %s", err, string(output))
+		errorMsg := fmt.Sprintf("Whisper CLI执行失败: %v\n输出: %s", err, output)
 		fmt.Printf("❌ Whisper CLI错误: %s\n", errorMsg)
 		// 返回具体的错误信息而不是回退到模拟数据
 		return nil, models.NewRecognitionError(
@@ -463,13 +613,31 @@ func (s *WhisperService) realWhisperRecognition(audioPath string, language strin
 
 	fmt.Printf("✅ Whisper CLI 执行成功，输出长度: %d\n", len(output))
 
-	// 解析生成的SRT文件以获取时间戳信息
-	srtFile := strings.TrimSuffix(wavPath, filepath.Ext(wavPath)) + ".srt"
-	defer os.Remove(srtFile) // 清理临时文件
+	detected, probability := tracker.detectedLanguage()
+
+	// 优先使用JSON输出中的token级真实时间戳
+	if _, err := os.Stat(jsonFile); err == nil {
+		segments, jsonOutput, err := s.parseWhisperJSON(jsonFile)
+		if err == nil {
+			fmt.Printf("✅ 使用JSON输出构建词级时间戳，段落数: %d\n", len(segments))
+			if detected == "" {
+				detected = normalizeWhisperLanguage(jsonOutput.Result.Language)
+			}
+			return &whisperRun{
+				segments:        segments,
+				detected:        detected,
+				probability:     probability,
+				timestampSource: "token",
+			}, nil
+		}
+		fmt.Printf("⚠️ 解析JSON输出失败，回退到SRT: %v\n", err)
+	} else {
+		fmt.Printf("⚠️ Whisper CLI未生成JSON文件，回退到SRT: %s\n", jsonFile)
+	}
 
 	// 检查SRT文件是否存在
 	if _, err := os.Stat(srtFile); os.IsNotExist(err) {
-		errorMsg := fmt.Sprintf("Whisper CLI未生成SRT文件: %s\n命令输出: %s", srtFile, string(output))
+		errorMsg := fmt.Sprintf("Whisper CLI未生成SRT文件: %s\n命令输出: %s", srtFile, output)
 		fmt.Printf("❌ SRT文件错误: %s\n", errorMsg)
 		return nil, models.NewRecognitionError(
 			models.ErrorCodeRecognitionFailed,
@@ -489,7 +657,7 @@ func (s *WhisperService) realWhisperRecognition(audioPath string, language strin
 		fmt.Printf("📄 SRT文件内容预览: %s\n", string(srtContent[:previewLen]))
 	}
 
-	result, err := s.parseWhisperOutput(srtFile, audioInfo, language)
+	segments, err := s.parseSRTSegments(srtFile)
 	if err != nil {
 		errorMsg := fmt.Sprintf("解析Whisper输出失败: %v\nSRT文件: %s", err, srtFile)
 		fmt.Printf("❌ 解析错误: %s\n", errorMsg)
@@ -500,68 +668,134 @@ func (s *WhisperService) realWhisperRecognition(audioPath string, language strin
 		)
 	}
 
-	// 发送完成进度
-	if progressCallback != nil {
-		progressCallback(&models.RecognitionProgress{
-			Status:     "语音识别完成",
-			Percentage: 100,
-		})
-	}
-
-	return result, nil
+	return &whisperRun{
+		segments:        segments,
+		detected:        detected,
+		probability:     probability,
+		timestampSource: "srt",
+	}, nil
 }
 
-// RecognizeFileWithModel 使用指定模型文件识别音频文件
-func (s *WhisperService) RecognizeFileWithModel(audioPath string, language string, specificModelFile string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+// findWhisperModelFile 查找要使用的Whisper模型文件，未找到时返回空字符串
+// 优先使用指定的模型文件，否则在模型目录中按质量优先级选择
+func findWhisperModelFile(modelDir, specificModelFile string) string {
+	modelPath := ""
+
+
+	// 首先检查是否指定了具体的模型文件
 	if specificModelFile != "" {
-		// 临时更新配置使用指定的模型文件
-		originalModelFile := s.config.SpecificModelFile
-		s.config.SpecificModelFile = specificModelFile
-		defer func() {
-			// 恢复原始配置
-			s.config.SpecificModelFile = originalModelFile
-		}()
+		if _, err := os.Stat(specificModelFile); err == nil {
+			modelPath = specificModelFile
+			fmt.Printf("✅ 使用指定的模型文件: %s\n", modelPath)
+		} else {
+			fmt.Printf("⚠️ 指定的模型文件不存在: %s，将使用默认查找逻辑\n", specificModelFile)
+		}
+	}
+
+	// 如果指定的模型文件不存在，则使用智能查找逻辑
+	if modelPath == "" {
+		// 首先尝试在指定目录中查找所有可用的模型文件
+		if specificModelFile != "" {
+			// 如果用户指定了具体文件但不存在，只在该目录下查找
+			modelDir = filepath.Dir(specificModelFile)
+		}
+
+		// 获取模型目录下的所有.bin文件
+		var availableModels []string
+		if files, err := os.ReadDir(modelDir); err == nil {
+			for _, file := range files {
+				if strings.HasSuffix(file.Name(), ".bin") {
+					fullPath := filepath.Join(modelDir, file.Name())
+					if info, err := os.Stat(fullPath); err == nil && !info.IsDir() {
+						availableModels = append(availableModels, fullPath)
+						fmt.Printf("🔍 发现可用模型: %s (%s)\n", file.Name(), formatFileSize(info.Size()))
+					}
+				}
+			}
+		}
 
-		fmt.Printf("🎯 使用用户指定的模型文件: %s\n", specificModelFile)
+		// 优先级选择模型（按质量和大小排序）
+		preferredOrder := []string{
+			"ggml-large-v3.bin", "ggml-large-v2.bin", "ggml-large-v1.bin", "ggml-large.bin",
+			"ggml-medium.bin",
+			"ggml-small.bin",
+			"ggml-base.bin",
+			"ggml-tiny.bin",
+		}
+
+		// 按优先级查找模型
+		for _, preferred := range preferredOrder {
+			for _, available := range availableModels {
+				if strings.HasSuffix(available, preferred) {
+					modelPath = available
+					fmt.Printf("✅ 选择模型文件: %s\n", modelPath)
+					break
+				}
+			}
+			if modelPath != "" {
+				break
+			}
+		}
+
+		// 如果没有找到优先模型，使用第一个可用的模型
+		if modelPath == "" && len(availableModels) > 0 {
+			modelPath = availableModels[0]
+			fmt.Printf("✅ 使用第一个可用模型: %s\n", modelPath)
+		}
 	}
 
-	// 调用原有的识别方法
-	return s.RecognizeFile(audioPath, language, progressCallback)
+	return modelPath
+}
+
+// RecognizeFileWithModel 使用指定模型文件识别音频文件
+func (s *WhisperService) RecognizeFileWithModel(ctx context.Context, audioPath string, language string, specificModelFile string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	return s.RecognizeFileWithOptions(ctx, audioPath, language, models.RecognitionOptions{SpecificModelFile: specificModelFile}, progressCallback)
 }
 
 // mapLanguageToWhisper 将语言代码映射到Whisper支持的语言代码
 func (s *WhisperService) mapLanguageToWhisper(language string) string {
-	// 使用自动语言检测，让Whisper自己识别语言
-	// 这样可以避免语言代码不兼容的问题
-	return "auto"
+	whisperLang, ok := MapLanguageToWhisper(language)
+	if !ok {
+		utils.LogWarn("不支持的识别语言 %s，改为自动检测", language)
+	}
+	return whisperLang
 }
 
-// parseWhisperOutput 解析Whisper CLI的输出文件
-func (s *WhisperService) parseWhisperOutput(srtFile string, audioInfo *models.AudioFile, language string) (*models.RecognitionResult, error) {
-	content, err := os.ReadFile(srtFile)
-	if err != nil {
-		return nil, fmt.Errorf("读取SRT文件失败: %w", err)
+// applyLanguageInfo 将请求语言与检测语言写入识别结果
+// detected 为Whisper自动检测出的语言代码（未检测时为空），仅在自动检测模式下生效
+func (s *WhisperService) applyLanguageInfo(result *models.RecognitionResult, requestedLanguage, whisperLang, detected string, probability float64) {
+	result.Metadata["requested_language"] = requestedLanguage
+	result.Metadata["whisper_language"] = whisperLang
+
+	if whisperLang != LanguageAuto {
+		result.Language = requestedLanguage
+		return
 	}
 
-	// 生成唯一ID
-	resultID := fmt.Sprintf("whisper_%d_%d", time.Now().Unix(), time.Now().UnixNano()%1000)
+	if detected == "" {
+		result.Language = LanguageAuto
+		return
+	}
 
-	result := &models.RecognitionResult{
-		ID:          resultID,
-		Language:    language,
-		Duration:    audioInfo.Duration,
-		ProcessedAt: s.getCurrentTime(),
-		Metadata:    make(map[string]interface{}),
-		Words:       []models.Word{},
-		Segments:    []models.RecognitionResultSegment{},
+	result.Language = MapWhisperToLanguage(detected)
+	result.Metadata["detected_language"] = detected
+	if probability > 0 {
+		result.Metadata["language_probability"] = probability
+	}
+	fmt.Printf("🌐 自动检测语言: %s (p = %.3f)\n", detected, probability)
+}
+
+// parseSRTSegments 解析SRT文件为段落（SRT只有段落级时间，每段作为一个词）
+func (s *WhisperService) parseSRTSegments(srtFile string) ([]models.RecognitionResultSegment, error) {
+	content, err := os.ReadFile(srtFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取SRT文件失败: %w", err)
 	}
 
 	// 解析SRT格式
 	srtContent := string(content)
 	lines := strings.Split(srtContent, "\n")
 
-	var fullText strings.Builder
-	var wordSegments []models.Word
 	var segments []models.RecognitionResultSegment
 
 	i := 0
@@ -578,7 +812,8 @@ func (s *WhisperService) parseWhisperOutput(srtFile string, audioInfo *models.Au
 			timestampLine := strings.TrimSpace(lines[i+1])
 			if strings.Contains(timestampLine, "-->") {
 				// 解析文本行
-				textLine := strings.TrimSpace(lines[i+2])
+				// -di 时whisper-cli在文本前添加 "(speaker 0)" 标记
+				textLine, channel, _ := stripSpeakerMarks(strings.TrimSpace(lines[i+2]))
 				if textLine != "" {
 					// 转换为简体中文
 					simplifiedText := s.convertToSimplified(textLine)
@@ -586,34 +821,26 @@ func (s *WhisperService) parseWhisperOutput(srtFile string, audioInfo *models.Au
 					// 解析时间戳
 					startTime, endTime := s.parseSRTPair(timestampLine)
 
-					// 添加到词汇结果（使用新的Word结构）
-					wordSegments = append(wordSegments, models.Word{
-						Text:       simplifiedText,
-						Start:      startTime,
-						End:        endTime,
-						Confidence: 0.8, // Whisper CLI不提供置信度，使用默认值
-					})
-
 					// 添加到段落结果
 					segment := models.RecognitionResultSegment{
 						Start:      startTime, // 直接使用秒数
 						End:        endTime,
 						Text:       simplifiedText,
-						Confidence: 0.8,
-						Words:      []models.Word{{
+						Confidence: 0.8, // SRT不提供置信度，使用默认值
+						Words: []models.Word{{
 							Text:       simplifiedText,
 							Start:      startTime,
 							End:        endTime,
 							Confidence: 0.8,
 						}},
-						Metadata: make(map[string]interface{}),
+						Metadata: map[string]interface{}{
+							"timestamp_source": "srt",
+						},
 					}
-					segments = append(segments, segment)
-
-					if fullText.Len() > 0 {
-						fullText.WriteString(" ")
+					if channel != "" {
+						segment.Metadata[speakerChannelKey] = channel
 					}
-					fullText.WriteString(simplifiedText)
+					segments = append(segments, segment)
 				}
 				i += 3
 			} else {
@@ -624,43 +851,63 @@ func (s *WhisperService) parseWhisperOutput(srtFile string, audioInfo *models.Au
 		}
 	}
 
+	return segments, nil
+}
+
+// buildRecognitionResult 根据解析出的段落构建识别结果
+func (s *WhisperService) buildRecognitionResult(segments []models.RecognitionResultSegment, audioInfo *models.AudioFile, language string) *models.RecognitionResult {
+	// 生成唯一ID
+	resultID := fmt.Sprintf("whisper_%d_%d", time.Now().Unix(), time.Now().UnixNano()%1000)
+
+	result := &models.RecognitionResult{
+		ID:          resultID,
+		Language:    language,
+		Duration:    audioInfo.Duration,
+		ProcessedAt: s.getCurrentTime(),
+		Metadata:    make(map[string]interface{}),
+		Words:       []models.Word{},
+		Segments:    []models.RecognitionResultSegment{},
+	}
+
+	var fullText strings.Builder
+	var allWords []models.Word
+	for _, segment := range segments {
+		allWords = append(allWords, segment.Words...)
+		if fullText.Len() > 0 {
+			fullText.WriteString(" ")
+		}
+		fullText.WriteString(segment.Text)
+	}
+
 	fmt.Printf("🔍 调试信息:\n")
-	fmt.Printf("   fullText长度: %d\n", len(fullText.String()))
-	fmt.Printf("   wordSegments数量: %d\n", len(wordSegments))
+	fmt.Printf("   fullText长度: %d\n", fullText.Len())
+	fmt.Printf("   words数量: %d\n", len(allWords))
 	fmt.Printf("   segments数量: %d\n", len(segments))
-	previewLen := 200
-	if len(fullText.String()) < previewLen {
-		previewLen = len(fullText.String())
-	}
-	fmt.Printf("   fullText预览: %s\n", fullText.String()[:previewLen])
 
-	// 从 SRT 内容计算实际音频时长（如果 audioInfo.Duration 为 0）
-	actualDuration := audioInfo.Duration
-	if actualDuration <= 0 && len(segments) > 0 {
+	// 从段落计算实际音频时长（如果 audioInfo.Duration 为 0）
+	if result.Duration <= 0 && len(segments) > 0 {
 		// 使用最后一个segment的结束时间作为音频时长
-		actualDuration = segments[len(segments)-1].End
-		fmt.Printf("🎯 从SRT计算得到音频时长: %.2f 秒\n", actualDuration)
+		result.Duration = segments[len(segments)-1].End
+		fmt.Printf("🎯 从识别结果计算得到音频时长: %.2f 秒\n", result.Duration)
 	}
 
-	// 重新构建 result.Text 使用正确的音频时长
-	if actualDuration > 0 {
-		result.Text = s.addTimestampsToText(fullText.String(), wordSegments, actualDuration)
-	} else {
-		result.Text = s.addTimestampsToText(fullText.String(), wordSegments, audioInfo.Duration)
+	// 使用真实时间构建带时间戳的文本
+	result.Text = s.addTimestampsToText(segments, result.Duration)
+	if allWords != nil {
+		result.Words = allWords
+	}
+	if segments != nil {
+		result.Segments = segments
 	}
-
-	result.Words = wordSegments
-	result.Segments = segments
 
 	// 设置带时间戳的文本字段（用于前端细颗粒度处理）
 	result.TimestampedText = result.Text
 
 	fmt.Printf("   最终result.Text长度: %d\n", len(result.Text))
-	fmt.Printf("   最终result.TimestampedText长度: %d\n", len(result.TimestampedText))
 
 	// 计算整体置信度
-	if len(wordSegments) > 0 {
-		result.Confidence = 0.8 // Whisper CLI不提供置信度，使用默认值
+	if len(allWords) > 0 {
+		result.Confidence = averageWordConfidence(allWords)
 	}
 
 	// 添加元数据
@@ -668,12 +915,51 @@ func (s *WhisperService) parseWhisperOutput(srtFile string, audioInfo *models.Au
 	result.Metadata["audio_format"] = audioInfo.Format
 	result.Metadata["sample_rate"] = audioInfo.SampleRate
 	result.Metadata["channels"] = audioInfo.Channels
-	result.Metadata["total_words"] = len(wordSegments)
+	result.Metadata["output_sample_rate"] = audioInfo.OutputSampleRate
+	result.Metadata["output_channels"] = audioInfo.OutputChannels
+	if audioInfo.Codec != "" {
+		result.Metadata["audio_codec"] = audioInfo.Codec
+	}
+	if audioInfo.Container != "" {
+		result.Metadata["audio_container"] = audioInfo.Container
+	}
+	if audioInfo.BitRate > 0 {
+		result.Metadata["bit_rate"] = audioInfo.BitRate
+	}
+	if audioInfo.StreamCount > 0 {
+		result.Metadata["stream_count"] = audioInfo.StreamCount
+	}
+	if len(audioInfo.Tags) > 0 {
+		result.Metadata["audio_tags"] = audioInfo.Tags
+	}
+	if audioInfo.FilterChain != "" {
+		result.Metadata["audio_filters"] = audioInfo.FilterChain
+	}
+	if len(audioInfo.AudioStreams) > 1 {
+		result.Metadata["audio_stream"] = audioInfo.AudioStream
+		result.Metadata["audio_streams"] = audioInfo.AudioStreams
+		if language := audioInfo.AudioStreams[audioInfo.AudioStream].Language; language != "" {
+			result.Metadata["audio_stream_language"] = language
+		}
+	}
+	if video := audioInfo.Video; video != nil {
+		// 保留视频的帧率和时长，导出字幕时按视频帧对齐
+		result.Metadata["video_codec"] = video.Codec
+		result.Metadata["video_width"] = video.Width
+		result.Metadata["video_height"] = video.Height
+		result.Metadata["video_frame_rate"] = video.FrameRate
+		result.Metadata["video_frame_rate_text"] = video.FrameRateText
+		result.Metadata["video_duration"] = video.Duration
+		if video.FrameCount > 0 {
+			result.Metadata["video_frame_count"] = video.FrameCount
+		}
+	}
+	result.Metadata["total_words"] = len(allWords)
 	result.Metadata["total_segments"] = len(segments)
 	result.Metadata["recognition_type"] = "whisper_cli"
 	result.Metadata["has_timestamps"] = true
 
-	return result, nil
+	return result
 }
 
 // parseSRTPair 解析SRT时间戳对
@@ -690,110 +976,119 @@ func (s *WhisperService) parseSRTPair(timestampLine string) (float64, float64) {
 	return startTime, endTime
 }
 
-// addTimestampsToText 在文本中添加时间戳标记
-func (s *WhisperService) addTimestampsToText(text string, words []models.Word, audioDuration float64) string {
-	fmt.Printf("🔍 addTimestampsToText 调试:\n")
-	fmt.Printf("   输入text长度: %d\n", len(text))
-	fmt.Printf("   words数量: %d\n", len(words))
-	fmt.Printf("   audioDuration: %.2f\n", audioDuration)
-
-	if len(words) == 0 {
-		fmt.Printf("   words为空，返回原始text\n")
-		return text
+// addTimestampsToText 在文本中添加时间戳标记（基于识别结果的真实时间）
+func (s *WhisperService) addTimestampsToText(segments []models.RecognitionResultSegment, audioDuration float64) string {
+	if len(segments) == 0 {
+		return ""
 	}
 
 	var result strings.Builder
 
-	// 使用更精细的时间标记分割逻辑，传入音频时长作为限制
-	timeMarks := s.generateFineTimeMarks(words, audioDuration)
+	timeMarks := s.generateFineTimeMarks(segments, audioDuration)
 	fmt.Printf("   生成timeMarks数量: %d\n", len(timeMarks))
 
 	for i, mark := range timeMarks {
 		if i > 0 {
 			result.WriteString("\n") // 每个时间标记独立一行
 		}
-		timestamp := utils.FormatTimestamp(mark.StartTime)
-		result.WriteString(timestamp)
+		result.WriteString(utils.FormatTimestamp(mark.StartTime))
 		result.WriteString(" ")
 		result.WriteString(mark.Text)
-
-		textPreview := mark.Text
-	if len(mark.Text) > 50 {
-		textPreview = mark.Text[:50]
-	}
-	fmt.Printf("   处理timeMark %d: %s - %s\n", i, timestamp, textPreview)
 	}
 
-	finalResult := result.String()
-	fmt.Printf("   最终结果长度: %d\n", len(finalResult))
-	return finalResult
+	return result.String()
 }
 
 // generateFineTimeMarks 生成更精细的时间标记
-func (s *WhisperService) generateFineTimeMarks(words []models.Word, maxDuration float64) []TimeMark {
+// 有词级时间戳时按强停顿符号在词边界切分，直接使用词的真实开始时间；
+// 只有段落级时间（SRT备选路径）时，在段落时间范围内按字符数比例分配
+func (s *WhisperService) generateFineTimeMarks(segments []models.RecognitionResultSegment, maxDuration float64) []TimeMark {
 	var timeMarks []TimeMark
-	currentTime := 0.0
 
-	for _, word := range words {
-		// 跳过空白词
-		if strings.TrimSpace(word.Text) == "" {
+	for _, segment := range segments {
+		if strings.TrimSpace(segment.Text) == "" {
 			continue
 		}
 
-		// 按标点符号和自然停顿分割文本
-		subSegments := s.splitTextByNaturalPauses(word.Text)
-
-		// 为每个子片段分配时间
-		for _, segment := range subSegments {
-			if strings.TrimSpace(segment.Text) == "" {
-				continue
-			}
-
-			// 计算片段时长：基于文本长度，但限制在合理范围内
-			textLen := len([]rune(segment.Text))
-			baseDuration := float64(textLen) * 0.3 // 每个字符约0.3秒
-			maxSegmentDuration := 8.0 // 单个片段最长8秒
-			minSegmentDuration := 0.5 // 单个片段最短0.5秒
+		if len(segment.Words) > 1 {
+			timeMarks = append(timeMarks, s.timeMarksFromWords(segment.Words)...)
+		} else {
+			timeMarks = append(timeMarks, s.timeMarksFromSegment(segment)...)
+		}
+	}
 
-			segmentDuration := baseDuration
-			if segmentDuration > maxSegmentDuration {
-				segmentDuration = maxSegmentDuration
-			}
-			if segmentDuration < minSegmentDuration && textLen > 1 {
-				segmentDuration = minSegmentDuration
+	// 确保不超过音频时长
+	if maxDuration > 0 {
+		for i := range timeMarks {
+			if timeMarks[i].EndTime > maxDuration {
+				timeMarks[i].EndTime = maxDuration
 			}
+		}
+	}
 
-			segmentStartTime := currentTime
-			segmentEndTime := currentTime + segmentDuration
+	return timeMarks
+}
 
-			// 确保不超过最大时长
-			if segmentStartTime >= maxDuration {
-				break // 如果已经开始超过最大时长，停止添加
-			}
-			if segmentEndTime > maxDuration {
-				segmentEndTime = maxDuration
-			}
+// timeMarksFromWords 按词级时间戳生成时间标记
+func (s *WhisperService) timeMarksFromWords(words []models.Word) []TimeMark {
+	var timeMarks []TimeMark
+	var current strings.Builder
+	var markStart, markEnd float64
+
+	flush := func() {
+		text := strings.TrimSpace(current.String())
+		if text != "" {
+			timeMarks = append(timeMarks, TimeMark{
+				StartTime: markStart,
+				EndTime:   markEnd,
+				Text:      text,
+			})
+		}
+		current.Reset()
+	}
 
-			timeMark := TimeMark{
-				StartTime: segmentStartTime,
-				EndTime:   segmentEndTime,
-				Text:      strings.TrimSpace(segment.Text),
-			}
-			timeMarks = append(timeMarks, timeMark)
+	for _, word := range words {
+		if current.Len() == 0 {
+			markStart = word.Start
+		} else if !containsCJK(word.Text) {
+			current.WriteString(" ")
+		}
+		current.WriteString(word.Text)
+		markEnd = word.End
 
-			currentTime = segmentEndTime
-			if currentTime >= maxDuration {
-				break
-			}
+		runes := []rune(word.Text)
+		if len(runes) > 0 && s.isStrongPauseChar(runes[len(runes)-1]) {
+			flush()
 		}
+	}
+	flush()
 
-		// 添加词语间的停顿时间
-		pauseTime := 0.2 + rand.Float64()*0.3 // 0.2-0.5秒的停顿
-		currentTime += pauseTime
+	return timeMarks
+}
+
+// timeMarksFromSegment 在段落时间范围内按字符数比例生成时间标记
+func (s *WhisperService) timeMarksFromSegment(segment models.RecognitionResultSegment) []TimeMark {
+	subSegments := s.splitTextByNaturalPauses(segment.Text)
+	totalChars := s.countTotalChars(subSegments)
+	if totalChars == 0 {
+		return nil
+	}
 
-		if currentTime >= maxDuration {
-			break
+	var timeMarks []TimeMark
+	duration := segment.End - segment.Start
+	currentTime := segment.Start
+	for _, sub := range subSegments {
+		text := strings.TrimSpace(sub.Text)
+		if text == "" {
+			continue
 		}
+		subDuration := duration * float64(sub.CharCount) / float64(totalChars)
+		timeMarks = append(timeMarks, TimeMark{
+			StartTime: currentTime,
+			EndTime:   currentTime + subDuration,
+			Text:      text,
+		})
+		currentTime += subDuration
 	}
 
 	return timeMarks
@@ -1011,10 +1306,17 @@ func (s *WhisperService) convertToSimplified(text string) string {
 }
 
 
+// currentConfig 获取当前配置
+func (s *WhisperService) currentConfig() *models.RecognitionConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
 // GetSupportedLanguages 获取支持的语言列表
 func (s *WhisperService) GetSupportedLanguages() []string {
-	// Whisper支持多种语言
-	return []string{"zh-CN", "en-US", "ja", "ko", "es", "fr", "de", "it", "pt", "ru", "ar", "hi"}
+	// 来自语言映射表，包含auto自动检测
+	return languageCodes()
 }
 
 // IsModelLoaded 检查模型是否已加载
@@ -1038,12 +1340,15 @@ func (s *WhisperService) UnloadModel(language string) error {
 	return nil
 }
 
-// UpdateConfig 更新配置
+// UpdateConfig 更新配置，进行中的识别仍使用开始时的配置
 func (s *WhisperService) UpdateConfig(config *models.RecognitionConfig) {
+	s.configMu.Lock()
 	s.config = config
+	s.configMu.Unlock()
 	if s.processor != nil {
 		s.processor.SetSampleRate(config.SampleRate)
 		s.processor.SetChannels(1)
+		s.processor.SetFilterChain(audio.BuildFilterChain(config))
 	}
 }
 
@@ -0,0 +1,117 @@
+package recognition
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"tingshengbianzi/backend/models"
+)
+
+// TestWhisperServiceConcurrentJobs 两个任务同时用不同的模型识别，期间更新配置和模型目录
+// 每个任务必须使用自己指定的模型；需要用 go test -race 运行才能发现共享配置上的数据竞争
+func TestWhisperServiceConcurrentJobs(t *testing.T) {
+	installFakeFFmpeg(t)
+	modelDir := writeTestModels(t, "ggml-base.bin", "ggml-small.bin")
+	service, err := NewWhisperServiceWithOptions(testConfig(modelDir), WhisperServiceOptions{WhisperPath: buildFakeWhisperCLI(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	audioPath := filepath.Join(t.TempDir(), "input.mp3")
+	writeTestWAV(t, audioPath, 16000)
+
+	modelFiles := []string{filepath.Join(modelDir, "ggml-base.bin"), filepath.Join(modelDir, "ggml-small.bin")}
+	results := make([]*models.RecognitionResult, len(modelFiles))
+	errs := make([]error, len(modelFiles))
+	var wg sync.WaitGroup
+	for i, modelFile := range modelFiles {
+		wg.Add(1)
+		go func(i int, modelFile string) {
+			defer wg.Done()
+			results[i], errs[i] = service.RecognizeFileWithOptions(context.Background(), audioPath, "zh-CN",
+				models.RecognitionOptions{SpecificModelFile: modelFile}, nil)
+		}(i, modelFile)
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := service.LoadModel("zh-CN", modelDir); err != nil {
+			t.Errorf("LoadModel失败: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		service.UpdateConfig(testConfig(modelDir))
+	}()
+	wg.Wait()
+
+	for i, modelFile := range modelFiles {
+		if errs[i] != nil {
+			t.Fatalf("任务 %d 识别失败: %v", i, errs[i])
+		}
+		if used := results[i].Metadata["model_file"]; used != modelFile {
+			t.Errorf("任务 %d 使用的模型为 %v，期望 %s", i, used, modelFile)
+		}
+		if len(results[i].Segments) == 0 {
+			t.Errorf("任务 %d 没有识别结果", i)
+		}
+	}
+}
+
+// TestWhisperCLIOutputFallback 优先使用-ojf的JSON输出构建词级时间戳，没有JSON时回退到SRT，两者都没有时识别失败
+func TestWhisperCLIOutputFallback(t *testing.T) {
+	const segments = `"segments": [{"start": 0, "end": 1.5, "text": "你好，世界。"}, {"start": 1.5, "end": 3, "text": "Hello world."}]`
+	tests := []struct {
+		name      string
+		script    string
+		source    string
+		wantWords [][]string
+	}{
+		{"JSON和SRT", `{` + segments + `}`, "token", [][]string{{"你", "好，", "世", "界。"}, {"Hello", "world."}}},
+		{"只有JSON", `{` + segments + `, "noSRT": true}`, "token", [][]string{{"你", "好，", "世", "界。"}, {"Hello", "world."}}},
+		{"只有JSON且拆分多字节字符", `{` + segments + `, "noSRT": true, "splitMultibyte": true}`, "token", [][]string{{"你", "好，", "世", "界。"}, {"Hello", "world."}}},
+		// SRT只有段落级时间，每段作为一个词
+		{"只有SRT", `{` + segments + `, "noJSON": true}`, "srt", [][]string{{"你好，世界。"}, {"Hello world."}}},
+		{"只有SRT且拆分多字节字符", `{` + segments + `, "noJSON": true, "splitMultibyte": true}`, "srt", [][]string{{"你好，世界。"}, {"Hello world."}}},
+		{"没有输出文件", `{` + segments + `, "noJSON": true, "noSRT": true}`, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFakeWhisperScript(t, tt.script)
+			result, err := recognizeWithFakeWhisper(t)
+			if tt.wantWords == nil {
+				recognitionErr, ok := err.(*models.RecognitionError)
+				if !ok || recognitionErr.Code != models.ErrorCodeRecognitionFailed {
+					t.Fatalf("期望识别失败，实际为 %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("识别失败: %v", err)
+			}
+
+			if source := result.Metadata["timestamp_source"]; source != tt.source {
+				t.Errorf("时间戳来源为 %v，期望 %s", source, tt.source)
+			}
+			wantTimes := [][2]float64{{0, 1.5}, {1.5, 3}}
+			if len(result.Segments) != len(tt.wantWords) {
+				t.Fatalf("识别结果有 %d 个段落，期望 %d 个", len(result.Segments), len(tt.wantWords))
+			}
+			for i, segment := range result.Segments {
+				if segment.Start != wantTimes[i][0] || segment.End != wantTimes[i][1] {
+					t.Errorf("第 %d 段时间为 %.3f-%.3f，期望 %.3f-%.3f", i+1, segment.Start, segment.End, wantTimes[i][0], wantTimes[i][1])
+				}
+				if got := wordTexts(segment.Words); !reflect.DeepEqual(got, tt.wantWords[i]) {
+					t.Errorf("第 %d 段的词汇为 %q，期望 %q", i+1, got, tt.wantWords[i])
+				}
+				words := segment.Words
+				if len(words) > 0 && (words[0].Start < segment.Start || words[len(words)-1].End > segment.End) {
+					t.Errorf("第 %d 段的词汇时间超出段落: %+v", i+1, words)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/utils"
+)
+
+// ASS字幕默认值，字号和边距按1080p画面，画面尺寸不同时按高度缩放
+const (
+	defaultASSFontName    = "Arial"
+	defaultASSFontNameCJK = "Microsoft YaHei"
+	defaultASSFontSize    = 60
+	defaultASSOutline     = 3
+	defaultASSShadow      = 1
+	defaultASSAlignment   = 2
+	defaultASSMarginH     = 60
+	defaultASSMarginV     = 50
+	defaultASSPlayResX    = 1920
+	defaultASSPlayResY    = 1080
+
+	assDefaultStyle     = "Default"
+	assTranslationStyle = "Translation"
+)
+
+// assSpeakerColors 未指定颜色的说话人依次使用的文字颜色，第一个说话人使用原文样式的颜色
+var assSpeakerColors = []string{"#FFE066", "#7FDBFF", "#B8F28C", "#FF9EBB", "#FFB870", "#C8A2FF"}
+
+// exportToASS 导出为ASS字幕格式：原文一种样式，每个说话人一种颜色的样式，双语字幕的译文使用单独的样式
+func (s *ExportService) exportToASS(result models.RecognitionResult, options models.ExportOptions) string {
+	settings := resolveSubtitleSettings(result, options)
+	cues := buildSubtitleCues(result, settings)
+
+	playResX, playResY := options.ASS.PlayResX, options.ASS.PlayResY
+	if playResX <= 0 || playResY <= 0 {
+		playResX, playResY = defaultASSPlayResX, defaultASSPlayResY
+		if width, height := metadataNumber(result.Metadata["video_width"]), metadataNumber(result.Metadata["video_height"]); width > 0 && height > 0 {
+			playResX, playResY = int(width), int(height)
+		}
+	}
+	scale := float64(playResY) / defaultASSPlayResY
+
+	base := resolveASSStyle(options.ASS.Style, defaultASSStyle(result), scale)
+	translation := mergeASSStyle(base, options.ASS.TranslationStyle, scale)
+	if options.ASS.TranslationStyle.FontSize <= 0 {
+		translation.FontSize = base.FontSize * 3 / 4
+	}
+
+	// 每个说话人一种样式，按出现顺序分配颜色
+	speakerStyles := make(map[string]string)
+	var styles []string
+	styles = append(styles, assStyleLine(assDefaultStyle, base))
+	for _, cue := range cues {
+		if cue.Speaker == "" || speakerStyles[cue.Speaker] != "" {
+			continue
+		}
+		style := base
+		if color, ok := options.ASS.SpeakerColors[cue.Speaker]; ok {
+			style.PrimaryColor = color
+		} else if index := len(speakerStyles); index > 0 {
+			style.PrimaryColor = assSpeakerColors[(index-1)%len(assSpeakerColors)]
+		}
+		name := assStyleName(cue.Speaker)
+		speakerStyles[cue.Speaker] = name
+		styles = append(styles, assStyleLine(name, style))
+	}
+	hasTranslation := false
+	for _, cue := range cues {
+		if len(cue.Translation) > 0 {
+			hasTranslation = true
+			break
+		}
+	}
+	if hasTranslation {
+		styles = append(styles, assStyleLine(assTranslationStyle, translation))
+	}
+
+	var ass strings.Builder
+	ass.WriteString("[Script Info]\n")
+	// 源文件信息写入注释行（播放器会忽略）
+	for _, line := range s.sourceDescription(result) {
+		ass.WriteString("; " + line + "\n")
+	}
+	if title := metadataTag(result.Metadata["audio_tags"], "title"); title != "" {
+		ass.WriteString(fmt.Sprintf("Title: %s\n", title))
+	}
+	ass.WriteString("ScriptType: v4.00+\n")
+	ass.WriteString("WrapStyle: 0\n")
+	ass.WriteString("ScaledBorderAndShadow: yes\n")
+	ass.WriteString(fmt.Sprintf("PlayResX: %d\n", playResX))
+	ass.WriteString(fmt.Sprintf("PlayResY: %d\n\n", playResY))
+
+	ass.WriteString("[V4+ Styles]\n")
+	ass.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	for _, style := range styles {
+		ass.WriteString(style + "\n")
+	}
+	ass.WriteString("\n")
+
+	ass.WriteString("[Events]\n")
+	ass.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, cue := range cues {
+		style := assDefaultStyle
+		if name, ok := speakerStyles[cue.Speaker]; ok {
+			style = name
+		}
+
+		var text string
+		if options.ASS.Karaoke && len(cue.Words) > 0 {
+			text = assKaraokeText(cue)
+		} else {
+			escaped := make([]string, 0, len(cue.Lines))
+			for _, line := range cue.Lines {
+				escaped = append(escaped, escapeASSText(line))
+			}
+			text = strings.Join(escaped, `\N`)
+		}
+		if settings.speaker && cue.NewSpeaker {
+			text = escapeASSText(cue.Speaker) + ": " + text
+		}
+		if len(cue.Translation) > 0 {
+			escaped := make([]string, 0, len(cue.Translation))
+			for _, line := range cue.Translation {
+				escaped = append(escaped, escapeASSText(line))
+			}
+			// 译文切换到译文样式，与原文在同一事件中上下排列
+			text += `\N{\r` + assTranslationStyle + `}` + strings.Join(escaped, `\N`)
+		}
+
+		ass.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,%s,%s,0,0,0,,%s\n",
+			utils.FormatASSTime(cue.Start), utils.FormatASSTime(cue.End),
+			style, strings.ReplaceAll(cue.Speaker, ",", " "), text))
+	}
+
+	return ass.String()
+}
+
+// defaultASSStyle ASS默认样式（按1080p画面），以中日韩文字为主时使用中文字体
+func defaultASSStyle(result models.RecognitionResult) models.ASSStyle {
+	style := models.ASSStyle{
+		FontName:       defaultASSFontName,
+		FontSize:       defaultASSFontSize,
+		PrimaryColor:   "#FFFFFF",
+		SecondaryColor: "#A0A0A0",
+		OutlineColor:   "#000000",
+		Outline:        defaultASSOutline,
+		Shadow:         defaultASSShadow,
+		Alignment:      defaultASSAlignment,
+		MarginL:        defaultASSMarginH,
+		MarginR:        defaultASSMarginH,
+		MarginV:        defaultASSMarginV,
+	}
+	if isMostlyCJK(result) {
+		style.FontName = defaultASSFontNameCJK
+	}
+	return style
+}
+
+// resolveASSStyle 用默认样式填充未设置的项，并按画面高度缩放字号、描边和边距
+func resolveASSStyle(style, defaults models.ASSStyle, scale float64) models.ASSStyle {
+	resolved := mergeASSStyle(defaults, style, 1)
+	resolved.FontSize = int(math.Round(float64(resolved.FontSize) * scale))
+	resolved.Outline *= scale
+	resolved.Shadow *= scale
+	resolved.MarginL = int(math.Round(float64(resolved.MarginL) * scale))
+	resolved.MarginR = int(math.Round(float64(resolved.MarginR) * scale))
+	resolved.MarginV = int(math.Round(float64(resolved.MarginV) * scale))
+	return resolved
+}
+
+// mergeASSStyle 用override中已设置的项覆盖base，override的尺寸按scale缩放（base已缩放）
+func mergeASSStyle(base, override models.ASSStyle, scale float64) models.ASSStyle {
+	scaled := func(value int) int { return int(math.Round(float64(value) * scale)) }
+	if override.FontName != "" {
+		base.FontName = override.FontName
+	}
+	if override.FontSize > 0 {
+		base.FontSize = scaled(override.FontSize)
+	}
+	if override.PrimaryColor != "" {
+		base.PrimaryColor = override.PrimaryColor
+	}
+	if override.SecondaryColor != "" {
+		base.SecondaryColor = override.SecondaryColor
+	}
+	if override.OutlineColor != "" {
+		base.OutlineColor = override.OutlineColor
+	}
+	if override.BackColor != "" {
+		base.BackColor = override.BackColor
+	}
+	base.Bold = base.Bold || override.Bold
+	base.Italic = base.Italic || override.Italic
+	if override.Outline > 0 {
+		base.Outline = override.Outline * scale
+	}
+	if override.Shadow > 0 {
+		base.Shadow = override.Shadow * scale
+	}
+	if override.Alignment >= 1 && override.Alignment <= 9 {
+		base.Alignment = override.Alignment
+	}
+	if override.MarginL > 0 {
+		base.MarginL = scaled(override.MarginL)
+	}
+	if override.MarginR > 0 {
+		base.MarginR = scaled(override.MarginR)
+	}
+	if override.MarginV > 0 {
+		base.MarginV = scaled(override.MarginV)
+	}
+	return base
+}
+
+// assStyleLine 生成 [V4+ Styles] 中的样式行
+func assStyleLine(name string, style models.ASSStyle) string {
+	flag := func(value bool) int {
+		if value {
+			return -1
+		}
+		return 0
+	}
+	return fmt.Sprintf("Style: %s,%s,%d,%s,%s,%s,%s,%d,%d,0,0,100,100,0,0,1,%s,%s,%d,%d,%d,%d,1",
+		name, strings.ReplaceAll(style.FontName, ",", " "), style.FontSize,
+		assColor(style.PrimaryColor, "&H00FFFFFF"), assColor(style.SecondaryColor, "&H00A0A0A0"),
+		assColor(style.OutlineColor, "&H00000000"), assColor(style.BackColor, "&H80000000"),
+		flag(style.Bold), flag(style.Italic),
+		formatASSNumber(style.Outline), formatASSNumber(style.Shadow),
+		style.Alignment, style.MarginL, style.MarginR, style.MarginV)
+}
+
+// assKaraokeText 生成带卡拉OK(\k)标签的字幕文本，每个词的时长到下一个词开始为止，按原文的换行位置插入\N
+func assKaraokeText(cue subtitleCue) string {
+	var text strings.Builder
+	position := func(t float64) int64 { return int64(math.Round((t - cue.Start) * 100)) }
+
+	elapsed := int64(0)
+	if gap := position(cue.Words[0].Start); gap > 0 {
+		text.WriteString(fmt.Sprintf(`{\k%d}`, gap))
+		elapsed = gap
+	}
+
+	line, used := 0, 0
+	for i, word := range cue.Words {
+		end := position(word.End)
+		if i < len(cue.Words)-1 {
+			end = position(cue.Words[i+1].Start)
+		}
+		duration := max(end-elapsed, 0)
+		elapsed += duration
+
+		length := utf8.RuneCountInString(word.Text)
+		separator := ""
+		if i > 0 && joinSubtitleWords(cue.Words[i-1:i+1]) != cue.Words[i-1].Text+word.Text {
+			separator = " "
+		}
+		if line < len(cue.Lines)-1 && used > 0 && used+len(separator)+length > utf8.RuneCountInString(cue.Lines[line]) {
+			text.WriteString(`\N`)
+			line, used, separator = line+1, 0, ""
+		}
+		text.WriteString(separator)
+		text.WriteString(fmt.Sprintf(`{\k%d}`, duration))
+		text.WriteString(escapeASSText(word.Text))
+		used += len(separator) + length
+	}
+	return text.String()
+}
+
+// assColor 将 #RRGGBB 转换为ASS的 &HAABBGGRR 颜色，格式无效时使用默认值
+func assColor(color, fallback string) string {
+	hex := strings.TrimPrefix(strings.TrimSpace(color), "#")
+	if len(hex) != 6 {
+		return fallback
+	}
+	for _, r := range hex {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return fallback
+		}
+	}
+	hex = strings.ToUpper(hex)
+	return "&H00" + hex[4:6] + hex[2:4] + hex[0:2]
+}
+
+// assStyleName 将说话人转换为样式名（样式名不能包含逗号，也不能与原文、译文样式重名）
+func assStyleName(speaker string) string {
+	name := strings.ReplaceAll(strings.TrimSpace(speaker), ",", " ")
+	if name == assDefaultStyle || name == assTranslationStyle {
+		name = "Speaker " + name
+	}
+	return name
+}
+
+// formatASSNumber 格式化样式中的数值，去掉多余的小数位
+func formatASSNumber(value float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", value), "0"), ".")
+}
+
+// escapeASSText 替换字幕文本中会被解释为覆盖标签或转义序列的字符
+func escapeASSText(text string) string {
+	return strings.NewReplacer(`\`, `＼`, "{", "｛", "}", "｝", "\n", " ").Replace(text)
+}
@@ -90,10 +90,28 @@ func (s *AudioService) SelectAudioFile() map[string]interface{} {
 			"type":         audioInfo.Type,
 			"duration":     audioInfo.Duration,
 			"lastModified": audioInfo.LastModified,
+			"codec":        audioInfo.Codec,
+			"container":    audioInfo.Container,
+			"sampleRate":   audioInfo.SampleRate,
+			"channels":     audioInfo.Channels,
+			"bitRate":      audioInfo.BitRate,
+			"tags":         audioInfo.Tags,
+			"streamCount":  audioInfo.StreamCount,
+			"isVideo":      audioInfo.IsVideo,
+			"audioStreams": audioInfo.AudioStreams,
+			"audioStream":  audioInfo.AudioStream,
+			"video":        audioInfo.Video,
 		},
 	}
 }
 
+// SelectAudioFolder 选择包含音频或视频文件的文件夹（批量识别使用），用户取消时返回空字符串
+func (s *AudioService) SelectAudioFolder() (string, error) {
+	return runtime.OpenDirectoryDialog(s.ctx, runtime.OpenDialogOptions{
+		Title: "选择音频或视频文件夹",
+	})
+}
+
 // GetAudioDuration 获取音频文件的真实时长
 func (s *AudioService) GetAudioDuration(filePath string) map[string]interface{} {
 	if filePath == "" {
@@ -127,7 +145,12 @@ func (s *AudioService) OnFileDrop(files []string) {
 		return
 	}
 
-	// 只处理第一个文件
+	// 多个文件时另外发送文件列表，前端可加入批量识别队列
+	if len(files) > 1 {
+		s.sendFilesDropped(files)
+	}
+
+	// 第一个文件按单文件识别处理
 	filePath := files[0]
 	fmt.Printf("📁 OnFileDrop: 处理文件: %s\n", filePath)
 
@@ -150,24 +173,82 @@ func (s *AudioService) OnFileDrop(files []string) {
 			"size":         validationResult.FileInfo.Size(),
 			"sizeFormatted": validationResult.SizeStr,
 			"extension":    validationResult.Extension,
+			"isVideo":      validationResult.IsVideo,
 			"hasPath":      true,
 		},
 	}
 
+	// 多音轨视频附带音轨列表，便于前端选择识别的音轨
+	if validationResult.IsVideo {
+		if probed, err := utils.ProbeAudioFileWithAvailableFFprobe(filePath); err == nil {
+			file := fileData["file"].(map[string]interface{})
+			file["audioStreams"] = probed.AudioStreams
+			file["audioStream"] = probed.AudioStream
+			file["video"] = probed.Video
+			file["duration"] = probed.Duration
+		} else {
+			fmt.Printf("⚠️ OnFileDrop: 探测视频文件失败: %v\n", err)
+		}
+	}
+
 	runtime.EventsEmit(s.ctx, "file-dropped", fileData)
 	fmt.Printf("📤 OnFileDrop: 已发送文件拖放事件到前端\n")
 }
 
-// CreateTempFileFromBase64 从Base64数据创建临时文件
+// sendFilesDropped 验证拖放的全部文件，发送可识别的文件和被拒绝的文件列表
+func (s *AudioService) sendFilesDropped(files []string) {
+	accepted := make([]map[string]interface{}, 0, len(files))
+	rejected := make([]map[string]interface{}, 0)
+	for _, filePath := range files {
+		validationResult := utils.ValidateAudioFile(filePath)
+		if !validationResult.IsValid {
+			rejected = append(rejected, map[string]interface{}{
+				"file":  filePath,
+				"error": validationResult.ErrorMsg,
+			})
+			continue
+		}
+		accepted = append(accepted, map[string]interface{}{
+			"name":          validationResult.FileInfo.Name(),
+			"path":          filePath,
+			"size":          validationResult.FileInfo.Size(),
+			"sizeFormatted": validationResult.SizeStr,
+			"extension":     validationResult.Extension,
+			"isVideo":       validationResult.IsVideo,
+		})
+	}
+
+	runtime.EventsEmit(s.ctx, "files-dropped", map[string]interface{}{
+		"files":    accepted,
+		"rejected": rejected,
+	})
+	fmt.Printf("📤 OnFileDrop: 已发送 %d 个文件的列表（%d 个不支持）\n", len(accepted), len(rejected))
+}
+
+// CreateTempFileFromBase64 从Base64数据创建临时文件，扩展名按文件头识别的实际格式设置
 func (s *AudioService) CreateTempFileFromBase64(base64Data string) (string, error) {
+	// 解码前按编码长度检查大小，Base64数据整体解码在内存中，大文件需改用分块上传
+	limit := min(utils.GetMaxFileSize(), utils.MaxBase64FileSize)
+	if size := int64(base64.StdEncoding.DecodedLen(len(base64Data))); size > limit {
+		return "", fmt.Errorf("文件过大，Base64数据最大支持 %s，更大的文件请使用分块上传", utils.FormatFileSize(limit))
+	}
+
 	// 解码Base64数据
 	fileData, err := base64.StdEncoding.DecodeString(base64Data)
 	if err != nil {
 		return "", fmt.Errorf("Base64解码失败: %v", err)
 	}
 
+	ext := utils.SniffMediaExtension(fileData[:min(len(fileData), utils.SniffHeaderSize)])
+	if ext == "" {
+		return "", fmt.Errorf("无法识别的文件格式，请选择音频或视频文件")
+	}
+	if err := utils.EnsureDiskSpace(os.TempDir(), int64(len(fileData))); err != nil {
+		return "", err
+	}
+
 	// 创建临时文件
-	tempFile, err := os.CreateTemp("", "audio-*.wav")
+	tempFile, err := os.CreateTemp("", "audio-*"+ext)
 	if err != nil {
 		return "", fmt.Errorf("创建临时文件失败: %v", err)
 	}
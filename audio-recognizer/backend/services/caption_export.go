@@ -0,0 +1,396 @@
+package services
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/utils"
+)
+
+// captionLines 字幕要显示的全部行：说话人变化时在首行前标注说话人，译文接在原文后
+func captionLines(cue subtitleCue, settings subtitleSettings) []string {
+	lines := append([]string(nil), cue.Lines...)
+	if settings.speaker && cue.NewSpeaker {
+		lines[0] = cue.Speaker + ": " + lines[0]
+	}
+	return append(lines, cue.Translation...)
+}
+
+// exportToSBV 导出为YouTube SBV字幕格式
+func (s *ExportService) exportToSBV(result models.RecognitionResult, options models.ExportOptions) string {
+	settings := resolveSubtitleSettings(result, options)
+	var sbv strings.Builder
+
+	for _, cue := range buildSubtitleCues(result, settings) {
+		sbv.WriteString(fmt.Sprintf("%s,%s\n", utils.FormatSBVTime(cue.Start), utils.FormatSBVTime(cue.End)))
+		sbv.WriteString(strings.Join(captionLines(cue, settings), "\n") + "\n\n")
+	}
+
+	return sbv.String()
+}
+
+// exportToTTML 导出为TTML字幕格式（IMSC1文本配置），时间使用帧率对应的 HH:MM:SS:FF 时间表达式
+func (s *ExportService) exportToTTML(result models.RecognitionResult, options models.ExportOptions) string {
+	settings := resolveSubtitleSettings(result, options)
+	rate := resolveFrameRate(result, options)
+	nominal := nominalFrameRate(rate)
+
+	var ttml strings.Builder
+	ttml.WriteString(xml.Header)
+	ttml.WriteString(`<tt xmlns="http://www.w3.org/ns/ttml"` +
+		` xmlns:ttp="http://www.w3.org/ns/ttml#parameter"` +
+		` xmlns:tts="http://www.w3.org/ns/ttml#styling"` +
+		` xmlns:ttm="http://www.w3.org/ns/ttml#metadata"` +
+		` ttp:profile="http://www.w3.org/ns/ttml/profile/imsc1/text"` +
+		` ttp:timeBase="media"`)
+	ttml.WriteString(fmt.Sprintf(` ttp:frameRate="%d"`, nominal))
+	if isNTSCFrameRate(rate) {
+		ttml.WriteString(` ttp:frameRateMultiplier="1000 1001"`)
+	}
+	ttml.WriteString(fmt.Sprintf(" xml:lang=\"%s\">\n", escapeXML(ttmlLanguage(result.Language))))
+
+	cues := buildSubtitleCues(result, settings)
+	ttml.WriteString("  <head>\n")
+	ttml.WriteString("    <metadata>\n")
+	if title := metadataTag(result.Metadata["audio_tags"], "title"); title != "" {
+		ttml.WriteString(fmt.Sprintf("      <ttm:title>%s</ttm:title>\n", escapeXML(title)))
+	}
+	for _, line := range s.sourceDescription(result) {
+		ttml.WriteString(fmt.Sprintf("      <ttm:desc>%s</ttm:desc>\n", escapeXML(line)))
+	}
+	if settings.speaker {
+		// 说话人定义为agent，字幕通过ttm:agent引用
+		agents := make(map[string]bool)
+		for _, cue := range cues {
+			if cue.Speaker == "" || agents[cue.Speaker] {
+				continue
+			}
+			agents[cue.Speaker] = true
+			ttml.WriteString(fmt.Sprintf("      <ttm:agent xml:id=\"%s\" type=\"person\"><ttm:name type=\"full\">%s</ttm:name></ttm:agent>\n",
+				escapeXML(ttmlAgentID(cue.Speaker)), escapeXML(cue.Speaker)))
+		}
+	}
+	ttml.WriteString("    </metadata>\n")
+	ttml.WriteString("    <styling>\n")
+	ttml.WriteString(`      <style xml:id="default" tts:color="white" tts:backgroundColor="rgba(0,0,0,0.8)" tts:fontFamily="proportionalSansSerif" tts:fontSize="100%" tts:lineHeight="125%" tts:textAlign="center"/>` + "\n")
+	ttml.WriteString(`      <style xml:id="translation" tts:color="#FFFF9E"/>` + "\n")
+	ttml.WriteString("    </styling>\n")
+	ttml.WriteString("    <layout>\n")
+	ttml.WriteString(`      <region xml:id="bottom" tts:origin="10% 10%" tts:extent="80% 80%" tts:displayAlign="after"/>` + "\n")
+	ttml.WriteString("    </layout>\n")
+	ttml.WriteString("  </head>\n")
+
+	ttml.WriteString("  <body region=\"bottom\" style=\"default\">\n")
+	ttml.WriteString("    <div>\n")
+	for i, cue := range cues {
+		lines := make([]string, 0, len(cue.Lines)+len(cue.Translation))
+		for j, line := range cue.Lines {
+			if j == 0 && settings.speaker && cue.NewSpeaker {
+				line = cue.Speaker + ": " + line
+			}
+			lines = append(lines, escapeXML(line))
+		}
+		for _, line := range cue.Translation {
+			lines = append(lines, `<span style="translation">`+escapeXML(line)+`</span>`)
+		}
+
+		ttml.WriteString(fmt.Sprintf(`      <p xml:id="sub%d" begin="%s" end="%s"`, i+1, ttmlTime(cue.Start, rate), ttmlTime(cue.End, rate)))
+		if settings.speaker && cue.Speaker != "" {
+			ttml.WriteString(fmt.Sprintf(` ttm:role="dialog" ttm:agent="%s"`, escapeXML(ttmlAgentID(cue.Speaker))))
+		}
+		ttml.WriteString(">" + strings.Join(lines, "<br/>") + "</p>\n")
+	}
+	ttml.WriteString("    </div>\n")
+	ttml.WriteString("  </body>\n")
+	ttml.WriteString("</tt>\n")
+
+	return ttml.String()
+}
+
+// ttmlTime 格式化TTML时间表达式 HH:MM:SS:FF（media时基下时分秒为实际时间，帧为秒内的小数部分）
+func ttmlTime(seconds, rate float64) string {
+	seconds = math.Max(seconds, 0)
+	whole := math.Floor(seconds)
+	frames := int64(math.Round((seconds - whole) * rate))
+	if frames >= int64(nominalFrameRate(rate)) {
+		whole++
+		frames = 0
+	}
+	total := int64(whole)
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", total/3600, (total%3600)/60, total%60, frames)
+}
+
+// ttmlLanguage 识别语言对应的xml:lang，自动检测时为空
+func ttmlLanguage(language string) string {
+	if language == "" || language == "auto" {
+		return ""
+	}
+	return language
+}
+
+// ttmlAgentID 说话人的agent ID（xml:id不能包含空格，也不能以数字开头）
+func ttmlAgentID(speaker string) string {
+	return "speaker_" + strings.Join(strings.Fields(speaker), "_")
+}
+
+// escapeXML 转义XML文本和属性值
+func escapeXML(text string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
+
+// EBU-STL（EBU Tech 3264）固定长度的数据块
+const (
+	stlGSISize       = 1024
+	stlTTISize       = 128
+	stlTextFieldSize = 112
+	stlMaxRowChars   = 37 // 图文电视每行40列，留出控制字符的位置
+	stlMaxRows       = 23
+)
+
+// exportToSTL 导出为EBU-STL字幕（二进制）：GSI块后每条字幕一个或多个TTI块，文本使用ISO 6937拉丁字符集
+// EBU-STL不支持中日韩文字，包含这些文字时返回错误
+func (s *ExportService) exportToSTL(result models.RecognitionResult, options models.ExportOptions) ([]byte, error) {
+	settings := resolveSubtitleSettings(result, options)
+	settings.lineLength = min(settings.lineLength, stlMaxRowChars)
+	rate := stlFrameRate(resolveFrameRate(result, options))
+	cues := buildSubtitleCues(result, settings)
+
+	var blocks [][]byte
+	for number, cue := range cues {
+		lines := stlCaptionLines(cue, settings)
+		var text []byte
+		for i, line := range lines {
+			if containsCJKRune(line) {
+				return nil, fmt.Errorf("EBU-STL不支持中日韩文字，请选择TTML、SRT或ASS格式")
+			}
+			if i > 0 {
+				text = append(text, 0x8A, 0x8A) // 换行（双倍行距）
+			}
+			text = append(text, encodeISO6937(line)...)
+		}
+
+		// 文本超过一个TTI块时使用扩展块，最后一块的EBN为0xFF
+		for extension := 0; len(text) > 0; extension++ {
+			end := min(stlTextFieldSize, len(text))
+			if end < len(text) && text[end-1] >= 0xC1 && text[end-1] <= 0xCF {
+				end-- // 变音符号和字母不拆到两个块中
+			}
+			ebn := byte(extension)
+			if end == len(text) {
+				ebn = 0xFF
+			}
+			blocks = append(blocks, stlTTIBlock(number+1, ebn, cue, len(lines), text[:end], rate))
+			text = text[end:]
+		}
+	}
+
+	firstCue := 0.0
+	if len(cues) > 0 {
+		firstCue = cues[0].Start
+	}
+	data := stlGSIBlock(result, rate, len(blocks), len(cues), firstCue)
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	return data, nil
+}
+
+// stlCaptionLines EBU-STL字幕的全部行，加上说话人标注后超过每行最多字符数（GSI的MNC）的行重新换行
+func stlCaptionLines(cue subtitleCue, settings subtitleSettings) []string {
+	var lines []string
+	for _, line := range captionLines(cue, settings) {
+		lines = append(lines, wrapSubtitleText(line, stlMaxRowChars)...)
+	}
+	return lines
+}
+
+// stlFrameRate EBU-STL只支持25和30帧：30/60帧（含NTSC的29.97/59.94）的视频使用30帧，其他帧率使用25帧
+func stlFrameRate(rate float64) float64 {
+	switch nominalFrameRate(rate) {
+	case 30, 60:
+		if isNTSCFrameRate(rate) {
+			return 30000.0 / 1001
+		}
+		return 30
+	}
+	return 25
+}
+
+// stlGSIBlock 生成通用信息(GSI)块，firstCue为第一条字幕的开始时间
+func stlGSIBlock(result models.RecognitionResult, rate float64, blockCount, subtitleCount int, firstCue float64) []byte {
+	gsi := make([]byte, stlGSISize)
+	for i := range gsi {
+		gsi[i] = ' '
+	}
+	field := func(offset, length int, value string) {
+		copy(gsi[offset:offset+length], stlASCII(value, length))
+	}
+
+	dfc := "STL25.01"
+	if nominalFrameRate(rate) == 30 {
+		dfc = "STL30.01"
+	}
+	now := time.Now().Format("060102")
+	title := metadataTag(result.Metadata["audio_tags"], "title")
+
+	field(0, 3, "850")                                 // CPN 代码页
+	field(3, 8, dfc)                                   // DFC 帧率
+	field(11, 1, "1")                                  // DSC 图文电视Level-1
+	field(12, 2, "00")                                 // CCT 拉丁字符集
+	field(14, 2, stlLanguageCode(result.Language))     // LC 语言
+	field(16, 32, title)                               // OPT 节目标题
+	field(224, 6, now)                                 // CD 创建日期
+	field(230, 6, now)                                 // RD 修订日期
+	field(236, 2, "00")                                // RN 修订号
+	field(238, 5, fmt.Sprintf("%05d", blockCount))     // TNB TTI块数
+	field(243, 5, fmt.Sprintf("%05d", subtitleCount))  // TNS 字幕数
+	field(248, 3, "001")                               // TNG 字幕组数
+	field(251, 2, fmt.Sprintf("%02d", stlMaxRowChars)) // MNC 每行最多字符数
+	field(253, 2, fmt.Sprintf("%02d", stlMaxRows))     // MNR 最多行数
+	field(255, 1, "1")                                 // TCS 时间码有效
+	field(256, 8, "00000000")                          // TCP 节目起始时间码
+	field(264, 8, stlGSITimecode(firstCue, rate))      // TCF 第一条字幕时间码
+	field(272, 1, "1")                                 // TND 磁盘总数
+	field(273, 1, "1")                                 // DSN 磁盘序号
+	return gsi
+}
+
+// stlGSITimecode GSI块中的时间码字段 HHMMSSFF
+func stlGSITimecode(seconds, rate float64) string {
+	hours, minutes, secs, frames := splitTimecode(secondsToFrames(seconds, rate), rate)
+	return fmt.Sprintf("%02d%02d%02d%02d", hours, minutes, secs, frames)
+}
+
+// stlTTIBlock 生成文本与时间信息(TTI)块
+func stlTTIBlock(number int, ebn byte, cue subtitleCue, lineCount int, text []byte, rate float64) []byte {
+	tti := make([]byte, stlTTISize)
+	tti[0] = 0                                              // SGN 字幕组
+	binary.LittleEndian.PutUint16(tti[1:3], uint16(number)) // SN 字幕序号
+	tti[3] = ebn                                            // EBN 扩展块序号
+	tti[4] = 0                                              // CS 累积状态
+	copy(tti[5:9], stlTimecode(cue.Start, rate))
+	copy(tti[9:13], stlTimecode(cue.End, rate))
+	tti[13] = byte(max(1, 22-(lineCount-1)*2)) // VP 底部对齐，每行占两行（双倍行距）
+	tti[14] = 2                                // JC 居中
+	tti[15] = 0                                // CF 字幕（非注释）
+
+	field := tti[16:]
+	for i := range field {
+		field[i] = 0x8F // 未使用的位置
+	}
+	copy(field, text)
+	return tti
+}
+
+// stlTimecode 时间码（时、分、秒、帧各一个字节），按实际帧率计算帧数后以整数帧率计数
+func stlTimecode(seconds, rate float64) []byte {
+	hours, minutes, secs, frames := splitTimecode(secondsToFrames(seconds, rate), rate)
+	return []byte{byte(hours), byte(minutes), byte(secs), byte(frames)}
+}
+
+// stlASCII 将文本转换为GSI字段使用的ASCII（不支持的字符替换为?），截断或补齐到指定长度
+func stlASCII(text string, length int) []byte {
+	field := make([]byte, 0, length)
+	for _, r := range text {
+		if len(field) == length {
+			break
+		}
+		if r < 0x20 || r > 0x7E {
+			r = '?'
+		}
+		field = append(field, byte(r))
+	}
+	for len(field) < length {
+		field = append(field, ' ')
+	}
+	return field
+}
+
+// stlLanguageCode EBU语言代码
+func stlLanguageCode(language string) string {
+	codes := map[string]string{
+		"cs": "06", "da": "07", "de": "08", "en": "09", "es": "0A", "fr": "0F", "it": "15",
+		"hu": "1B", "nl": "1D", "no": "1E", "pl": "20", "pt": "21", "ro": "22", "fi": "27",
+		"sv": "28", "tr": "29",
+	}
+	if code, ok := codes[strings.ToLower(strings.SplitN(language, "-", 2)[0])]; ok {
+		return code
+	}
+	return "00"
+}
+
+// iso6937Diacritics ISO 6937的非间距变音符号（写在基本字母之前）及其组合字母
+var iso6937Diacritics = []struct {
+	mark     byte
+	base     string
+	composed string
+}{
+	{0xC1, "AEIOUaeiou", "ÀÈÌÒÙàèìòù"},
+	{0xC2, "AEIOUYaeiouyCcNnSsZzLlRr", "ÁÉÍÓÚÝáéíóúýĆćŃńŚśŹźĹĺŔŕ"},
+	{0xC3, "AEIOUaeiouCcGgHhJjSsWwYy", "ÂÊÎÔÛâêîôûĈĉĜĝĤĥĴĵŜŝŴŵŶŷ"},
+	{0xC4, "ANOanoIiUu", "ÃÑÕãñõĨĩŨũ"},
+	{0xC5, "AEIOUaeiou", "ĀĒĪŌŪāēīōū"},
+	{0xC6, "AaGgUu", "ĂăĞğŬŭ"},
+	{0xC7, "CcEeGgIZz", "ĊċĖėĠġİŻż"},
+	{0xC8, "AEIOUaeiouyY", "ÄËÏÖÜäëïöüÿŸ"},
+	{0xCA, "AaUu", "ÅåŮů"},
+	{0xCB, "CcGKkLlNnRrSsTt", "ÇçĢĶķĻļŅņŖŗŞşŢţ"},
+	{0xCD, "OoUu", "ŐőŰű"},
+	{0xCE, "AaEeIiUu", "ĄąĘęĮįŲų"},
+	{0xCF, "CcDdEeLlNnRrSsTtZz", "ČčĎďĚěĽľŇňŘřŠšŤťŽž"},
+}
+
+// iso6937Symbols ISO 6937中单字节编码的符号和字母
+var iso6937Symbols = map[rune]byte{
+	'$': 0xA4, '¡': 0xA1, '¢': 0xA2, '£': 0xA3, '¥': 0xA5, '§': 0xA7, '‘': 0xA9, '“': 0xAA, '«': 0xAB,
+	'°': 0xB0, '±': 0xB1, '²': 0xB2, '³': 0xB3, '×': 0xB4, 'µ': 0xB5, '¶': 0xB6, '·': 0xB7, '÷': 0xB8,
+	'’': 0xB9, '”': 0xBA, '»': 0xBB, '¼': 0xBC, '½': 0xBD, '¾': 0xBE, '¿': 0xBF, '―': 0xD0,
+	'Æ': 0xE1, 'Đ': 0xE2, 'ª': 0xE3, 'Ħ': 0xE4, 'Ĳ': 0xE6, 'Ŀ': 0xE7, 'Ł': 0xE8, 'Ø': 0xE9,
+	'Œ': 0xEA, 'º': 0xEB, 'Þ': 0xEC, 'Ŧ': 0xED, 'Ŋ': 0xEE, 'ŉ': 0xEF, 'ĸ': 0xF0, 'æ': 0xF1,
+	'đ': 0xF2, 'ð': 0xF3, 'ħ': 0xF4, 'ı': 0xF5, 'ĳ': 0xF6, 'ŀ': 0xF7, 'ł': 0xF8, 'ø': 0xF9,
+	'œ': 0xFA, 'ß': 0xFB, 'þ': 0xFC, 'ŧ': 0xFD, 'ŋ': 0xFE,
+}
+
+// encodeISO6937 将文本编码为ISO 6937（EBU-STL拉丁字符集），无法表示的字符替换为?
+func encodeISO6937(text string) []byte {
+	var encoded []byte
+	for _, r := range text {
+		switch {
+		case r == '$':
+			encoded = append(encoded, iso6937Symbols[r])
+		case r >= 0x20 && r <= 0x7E:
+			encoded = append(encoded, byte(r))
+		case r == '…':
+			encoded = append(encoded, "..."...)
+		case r == '–' || r == '—':
+			encoded = append(encoded, '-')
+		default:
+			if b, ok := iso6937Symbols[r]; ok {
+				encoded = append(encoded, b)
+				continue
+			}
+			encoded = append(encoded, encodeISO6937Composed(r)...)
+		}
+	}
+	return encoded
+}
+
+// encodeISO6937Composed 将带变音符号的字母编码为变音符号加基本字母
+func encodeISO6937Composed(r rune) []byte {
+	for _, diacritic := range iso6937Diacritics {
+		base := []rune(diacritic.base)
+		for i, composed := range []rune(diacritic.composed) {
+			if composed == r {
+				return []byte{diacritic.mark, byte(base[i])}
+			}
+		}
+	}
+	return []byte{'?'}
+}
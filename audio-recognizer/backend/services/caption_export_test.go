@@ -0,0 +1,437 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"tingshengbianzi/backend/models"
+)
+
+// updateGolden 运行 go test ./backend/services -update 重新生成testdata中的参考文件
+var updateGolden = flag.Bool("update", false, "重新生成testdata中的参考文件")
+
+// captionTestOptions 参考文件使用的导出选项：标注说话人并附加译文
+var captionTestOptions = models.ExportOptions{IncludeSpeaker: true, IncludeTranslation: true}
+
+// captionCue 从导出文件中解析出的字幕
+type captionCue struct {
+	Start float64
+	End   float64
+	Lines []string
+}
+
+// TestCaptionExportRoundTrip 导出TTML、SBV和EBU-STL，与testdata中的参考文件比较，再解析导出文件还原字幕
+// 测试数据为29.97帧的视频，第一条字幕不在0秒开始
+func TestCaptionExportRoundTrip(t *testing.T) {
+	resultJSON, err := os.ReadFile(filepath.Join("testdata", "caption_result.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result models.RecognitionResult
+	if err := json.Unmarshal(resultJSON, &result); err != nil {
+		t.Fatal(err)
+	}
+	rate := resolveFrameRate(result, captionTestOptions)
+	stlRate := stlFrameRate(rate)
+
+	tests := []struct {
+		format    string
+		lineLimit int // 导出时的行长度上限，0为不限制
+		tolerance float64
+		parse     func(t *testing.T, data []byte) []captionCue
+		lines     func(cue subtitleCue, settings subtitleSettings) []string
+	}{
+		{format: "ttml", tolerance: 0.5 / rate, parse: parseTTMLCaptions, lines: captionLines},
+		{format: "sbv", tolerance: 0.0005, parse: parseSBVCaptions, lines: captionLines},
+		{format: "stl", lineLimit: stlMaxRowChars, tolerance: 0.5 / stlRate, parse: func(t *testing.T, data []byte) []captionCue {
+			return parseSTLCaptions(t, data, stlRate)
+		}, lines: stlCaptionLines},
+	}
+
+	service := NewExportService("")
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "captions."+test.format)
+			if exportErr := service.ExportResultWithOptions(string(resultJSON), test.format, output, captionTestOptions); exportErr != nil {
+				t.Fatalf("导出失败: %+v", exportErr)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.format == "stl" {
+				copy(data[224:236], "000000000000") // 创建和修订日期随导出时间变化，比较前清零
+			}
+			compareGolden(t, "caption_reference."+test.format, data)
+
+			settings := resolveSubtitleSettings(result, captionTestOptions)
+			if test.lineLimit > 0 {
+				settings.lineLength = min(settings.lineLength, test.lineLimit)
+			}
+			var want []captionCue
+			for _, cue := range buildSubtitleCues(result, settings) {
+				want = append(want, captionCue{Start: cue.Start, End: cue.End, Lines: test.lines(cue, settings)})
+			}
+			compareCaptionCues(t, test.parse(t, data), want, test.tolerance)
+		})
+	}
+}
+
+// TestSTLGSITimecodes 导出EBU-STL，检查GSI块的帧率、时间码和计数字段，以及各TTI块的序号、扩展块标记、行数和行长度
+func TestSTLGSITimecodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64 // 导出选项中的帧率，0为使用视频帧率
+		shift   float64 // 字幕整体后移的秒数
+		realFPS float64
+		dfc     string
+		tcf     string
+	}{
+		{"29.97帧", 0, 0, 30000.0 / 1001, "STL30.01", "00000107"}, // 1.25秒 = 29.97帧下的第37帧
+		{"25帧且从1小时开始", 25, 3600, 25, "STL25.01", "01000106"},     // 3601.25秒 = 25帧下的第90031帧
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := loadCaptionResult(t)
+			for i := range result.Segments {
+				result.Segments[i].Start += tt.shift
+				result.Segments[i].End += tt.shift
+			}
+			options := captionTestOptions
+			options.FrameRate = tt.rate
+
+			data, err := NewExportService("").exportToSTL(result, options)
+			if err != nil {
+				t.Fatalf("导出失败: %v", err)
+			}
+			gsi := data[:stlGSISize]
+			blocks := (len(data) - stlGSISize) / stlTTISize
+			cues := parseSTLCaptions(t, data, tt.realFPS)
+
+			checks := []struct {
+				name  string
+				field string
+				want  string
+			}{
+				{"CPN", string(gsi[0:3]), "850"},
+				{"DFC", string(gsi[3:11]), tt.dfc},
+				{"LC", string(gsi[14:16]), "0F"},
+				{"RD", string(gsi[230:236]), string(gsi[224:230])},
+				{"TNB", string(gsi[238:243]), fmt.Sprintf("%05d", blocks)},
+				{"TNS", string(gsi[243:248]), fmt.Sprintf("%05d", len(result.Segments))},
+				{"MNC", string(gsi[251:253]), fmt.Sprintf("%02d", stlMaxRowChars)},
+				{"MNR", string(gsi[253:255]), fmt.Sprintf("%02d", stlMaxRows)},
+				{"TCP", string(gsi[256:264]), "00000000"},
+				{"TCF", string(gsi[264:272]), tt.tcf},
+			}
+			for _, check := range checks {
+				if check.field != check.want {
+					t.Errorf("GSI %s = %q，期望 %q", check.name, check.field, check.want)
+				}
+			}
+			if _, err := time.Parse("060102", string(gsi[224:230])); err != nil {
+				t.Errorf("GSI CD %q 不是有效日期", gsi[224:230])
+			}
+			if len(cues) != len(result.Segments) {
+				t.Fatalf("解析出 %d 条字幕，期望 %d 条", len(cues), len(result.Segments))
+			}
+
+			// 同一字幕的扩展块序号相同，最后一块的EBN为0xFF；VP按行数从底部向上排列
+			cue := 0
+			for block := 0; block < blocks; block++ {
+				tti := data[stlGSISize+block*stlTTISize : stlGSISize+(block+1)*stlTTISize]
+				if sn := int(binary.LittleEndian.Uint16(tti[1:3])); sn != cue+1 {
+					t.Errorf("第 %d 个TTI块的SN为 %d，期望 %d", block+1, sn, cue+1)
+				}
+				if tti[3] != 0xFF {
+					continue
+				}
+				lines := cues[cue].Lines
+				if vp := int(tti[13]); vp != 22-(len(lines)-1)*2 {
+					t.Errorf("第 %d 条字幕有 %d 行，VP为 %d", cue+1, len(lines), vp)
+				}
+				for _, line := range lines {
+					if utf8.RuneCountInString(line) > stlMaxRowChars {
+						t.Errorf("第 %d 条字幕的行 %q 超过每行最多 %d 个字符", cue+1, line, stlMaxRowChars)
+					}
+				}
+				cue++
+			}
+		})
+	}
+}
+
+// TestCaptionHandwrittenReferences 导出的TTML和EBU-STL与手工编写（不是由导出器生成）的参考文件比较字幕时间和文本，
+// EBU-STL还比较GSI字段（节目标题和日期除外）和各TTI块的头部
+func TestCaptionHandwrittenReferences(t *testing.T) {
+	result := loadCaptionResult(t)
+	service := NewExportService("")
+	rate := 30000.0 / 1001
+
+	t.Run("ttml", func(t *testing.T) {
+		reference, err := os.ReadFile(filepath.Join("testdata", "handwritten_reference.ttml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		exported := service.exportToTTML(result, captionTestOptions)
+		compareCaptionCues(t, parseTTMLCaptions(t, []byte(exported)), parseTTMLCaptions(t, reference), 1e-6)
+	})
+
+	t.Run("stl", func(t *testing.T) {
+		reference, err := os.ReadFile(filepath.Join("testdata", "handwritten_reference.stl"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		exported, err := service.exportToSTL(result, captionTestOptions)
+		if err != nil {
+			t.Fatalf("导出失败: %v", err)
+		}
+		compareCaptionCues(t, parseSTLCaptions(t, exported, rate), parseSTLCaptions(t, reference, rate), 1e-6)
+
+		// 参考文件中扩展块在行之间断开，导出器在112字节处断开，块数相同时头部应一致
+		if len(exported) != len(reference) {
+			t.Fatalf("导出文件长度为 %d，参考文件为 %d", len(exported), len(reference))
+		}
+		for _, field := range [][2]int{{0, 16}, {236, 274}} {
+			if got, want := exported[field[0]:field[1]], reference[field[0]:field[1]]; !bytes.Equal(got, want) {
+				t.Errorf("GSI[%d:%d] = %q，参考文件为 %q", field[0], field[1], got, want)
+			}
+		}
+		for offset := stlGSISize; offset < len(reference); offset += stlTTISize {
+			if got, want := exported[offset:offset+16], reference[offset:offset+16]; !bytes.Equal(got, want) {
+				t.Errorf("第 %d 个TTI块头部为 % x，参考文件为 % x", (offset-stlGSISize)/stlTTISize+1, got, want)
+			}
+		}
+	})
+}
+
+// loadCaptionResult 读取参考文件使用的识别结果
+func loadCaptionResult(t *testing.T) models.RecognitionResult {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "caption_result.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result models.RecognitionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// compareGolden 与testdata中的参考文件比较，-update 时改为写入参考文件
+func compareGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("导出内容与参考文件 %s 不一致（确认改动无误后用 -update 更新参考文件）:\n%s", name, got)
+	}
+}
+
+// compareCaptionCues 比较解析出的字幕与导出前的字幕，时间允许tolerance秒的取整误差
+func compareCaptionCues(t *testing.T, got, want []captionCue, tolerance float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("解析出 %d 条字幕，期望 %d 条: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if math.Abs(got[i].Start-want[i].Start) > tolerance+1e-9 || math.Abs(got[i].End-want[i].End) > tolerance+1e-9 {
+			t.Errorf("第 %d 条字幕时间为 %.3f-%.3f，期望 %.3f-%.3f", i+1, got[i].Start, got[i].End, want[i].Start, want[i].End)
+		}
+		if strings.Join(got[i].Lines, "\n") != strings.Join(want[i].Lines, "\n") {
+			t.Errorf("第 %d 条字幕文本为 %q，期望 %q", i+1, got[i].Lines, want[i].Lines)
+		}
+	}
+}
+
+// parseTTMLCaptions 解析TTML的 <p> 元素，按 ttp:frameRate 和 ttp:frameRateMultiplier 换算时间
+func parseTTMLCaptions(t *testing.T, data []byte) []captionCue {
+	t.Helper()
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	rate, multiplier := 30.0, 1.0
+	var cues []captionCue
+	var current *captionCue
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("TTML格式无效: %v", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "tt":
+				for _, attr := range element.Attr {
+					switch attr.Name.Local {
+					case "frameRate":
+						rate, _ = strconv.ParseFloat(attr.Value, 64)
+					case "frameRateMultiplier":
+						var numerator, denominator float64
+						fmt.Sscanf(attr.Value, "%g %g", &numerator, &denominator)
+						multiplier = numerator / denominator
+					}
+				}
+			case "p":
+				current = &captionCue{Lines: []string{""}}
+				for _, attr := range element.Attr {
+					switch attr.Name.Local {
+					case "begin":
+						current.Start = parseTTMLTime(t, attr.Value, rate*multiplier)
+					case "end":
+						current.End = parseTTMLTime(t, attr.Value, rate*multiplier)
+					}
+				}
+			case "br":
+				current.Lines = append(current.Lines, "")
+			}
+		case xml.CharData:
+			if current != nil {
+				current.Lines[len(current.Lines)-1] += string(element)
+			}
+		case xml.EndElement:
+			if element.Name.Local == "p" {
+				cues = append(cues, *current)
+				current = nil
+			}
+		}
+	}
+	return cues
+}
+
+// parseTTMLTime 解析 HH:MM:SS:FF 时间表达式
+func parseTTMLTime(t *testing.T, value string, rate float64) float64 {
+	t.Helper()
+	var hours, minutes, seconds, frames int
+	if _, err := fmt.Sscanf(value, "%d:%d:%d:%d", &hours, &minutes, &seconds, &frames); err != nil {
+		t.Fatalf("TTML时间表达式无效: %s", value)
+	}
+	return float64(hours*3600+minutes*60+seconds) + float64(frames)/rate
+}
+
+// parseSBVCaptions 解析SBV：每条字幕为时间行加文本行，字幕之间空一行
+func parseSBVCaptions(t *testing.T, data []byte) []captionCue {
+	t.Helper()
+	var cues []captionCue
+	for _, block := range strings.Split(strings.TrimSpace(string(data)), "\n\n") {
+		lines := strings.Split(block, "\n")
+		times := strings.Split(lines[0], ",")
+		if len(times) != 2 || len(lines) < 2 {
+			t.Fatalf("SBV字幕格式无效: %q", block)
+		}
+		cues = append(cues, captionCue{
+			Start: parseSBVTime(t, times[0]),
+			End:   parseSBVTime(t, times[1]),
+			Lines: lines[1:],
+		})
+	}
+	return cues
+}
+
+// parseSBVTime 解析 H:MM:SS.mmm 时间
+func parseSBVTime(t *testing.T, value string) float64 {
+	t.Helper()
+	var hours, minutes int
+	var seconds float64
+	if _, err := fmt.Sscanf(value, "%d:%d:%g", &hours, &minutes, &seconds); err != nil {
+		t.Fatalf("SBV时间无效: %s", value)
+	}
+	return float64(hours*3600+minutes*60) + seconds
+}
+
+// parseSTLCaptions 解析EBU-STL的TTI块，合并扩展块的文本；rate为实际帧率，时间码按整数帧率计数
+func parseSTLCaptions(t *testing.T, data []byte, rate float64) []captionCue {
+	t.Helper()
+	if len(data) < stlGSISize || (len(data)-stlGSISize)%stlTTISize != 0 {
+		t.Fatalf("EBU-STL文件长度无效: %d", len(data))
+	}
+	nominal := float64(nominalFrameRate(rate))
+	timecode := func(tc []byte) float64 {
+		frames := ((float64(tc[0])*60+float64(tc[1]))*60+float64(tc[2]))*nominal + float64(tc[3])
+		return frames / rate
+	}
+
+	var cues []captionCue
+	var text []byte
+	for offset := stlGSISize; offset < len(data); offset += stlTTISize {
+		tti := data[offset : offset+stlTTISize]
+		text = append(text, bytes.TrimRight(tti[16:], "\x8f")...)
+		if tti[3] != 0xFF {
+			continue
+		}
+		var lines []string
+		for _, line := range bytes.Split(text, []byte{0x8A, 0x8A}) {
+			lines = append(lines, decodeISO6937(line))
+		}
+		cues = append(cues, captionCue{Start: timecode(tti[5:9]), End: timecode(tti[9:13]), Lines: lines})
+		text = nil
+	}
+	return cues
+}
+
+// decodeISO6937 将ISO 6937编码的文本还原为UTF-8
+func decodeISO6937(data []byte) string {
+	symbols := make(map[byte]rune)
+	for r, b := range iso6937Symbols {
+		symbols[b] = r
+	}
+	var decoded strings.Builder
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b >= 0xC1 && b <= 0xCF && i+1 < len(data):
+			i++
+			decoded.WriteRune(decodeISO6937Composed(b, data[i]))
+		case b < 0x80:
+			decoded.WriteByte(b)
+		default:
+			if r, ok := symbols[b]; ok {
+				decoded.WriteRune(r)
+			} else {
+				decoded.WriteRune('?')
+			}
+		}
+	}
+	return decoded.String()
+}
+
+// decodeISO6937Composed 变音符号加基本字母还原为组合字母
+func decodeISO6937Composed(mark, base byte) rune {
+	for _, diacritic := range iso6937Diacritics {
+		if diacritic.mark != mark {
+			continue
+		}
+		composed := []rune(diacritic.composed)
+		for i, r := range diacritic.base {
+			if byte(r) == base {
+				return composed[i]
+			}
+		}
+	}
+	return '?'
+}
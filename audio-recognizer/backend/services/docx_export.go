@@ -0,0 +1,181 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tingshengbianzi/backend/models"
+)
+
+// DOCX文档的固定部件
+const (
+	docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>`
+
+	docxPackageRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>`
+
+	docxDocumentRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+	// docxStyles 内置样式：标题、说话人标题(Heading1)、正文和时间戳
+	docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults>
+<w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Microsoft YaHei" w:cs="Calibri"/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="160" w:line="300" w:lineRule="auto"/></w:pPr></w:pPrDefault>
+</w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:spacing w:after="240"/></w:pPr><w:rPr><w:b/><w:sz w:val="40"/><w:szCs w:val="40"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:color w:val="1F4E79"/><w:sz w:val="28"/><w:szCs w:val="28"/></w:rPr></w:style>
+<w:style w:type="character" w:styleId="Timestamp"><w:name w:val="Timestamp"/><w:rPr><w:color w:val="808080"/><w:sz w:val="18"/><w:szCs w:val="18"/></w:rPr></w:style>
+</w:styles>`
+)
+
+// exportToDOCX 导出为Word文档（纯Go生成，不需要安装Office）：标题、文件信息、说话人标题和带时间戳的段落
+// 模板目录中的 transcript.docx（或导出选项指定的.docx文件）作为参考文档，使用其中的样式
+func (s *ExportService) exportToDOCX(result models.RecognitionResult, options models.ExportOptions) ([]byte, error) {
+	styles, err := s.docxStyles(options)
+	if err != nil {
+		return nil, err
+	}
+	document := buildTranscriptDocument(result, options)
+
+	var body strings.Builder
+	body.WriteString(docxParagraph("Title", docxRun("", document.Title, false)))
+	info := []struct{ label, value string }{
+		{"文件", document.FileName},
+		{"时长", document.Duration},
+		{"模型", document.Model},
+		{"语言", document.Language},
+		{"处理时间", document.ProcessedAt},
+	}
+	for _, item := range info {
+		if item.value != "" {
+			body.WriteString(docxParagraph("", docxRun("", item.label+"：", true)+docxRun("", item.value, false)))
+		}
+	}
+	for _, section := range document.Sections {
+		if section.Speaker != "" {
+			body.WriteString(docxParagraph("Heading1", docxRun("", section.Speaker+" ", false)+docxRun("Timestamp", section.Start, false)))
+		}
+		for _, paragraph := range section.Paragraphs {
+			body.WriteString(docxParagraph("", docxRun("Timestamp", "["+paragraph.Start+"] ", false)+docxRun("", paragraph.Text, false)))
+		}
+	}
+
+	documentXML := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body.String() +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>` +
+		`</w:body></w:document>`
+
+	created := time.Now().UTC().Format(time.RFC3339)
+	coreXML := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		fmt.Sprintf(`<dc:title>%s</dc:title><dc:language>%s</dc:language>`, escapeXML(document.Title), escapeXML(document.Language)) +
+		fmt.Sprintf(`<dcterms:created xsi:type="dcterms:W3CDTF">%s</dcterms:created><dcterms:modified xsi:type="dcterms:W3CDTF">%s</dcterms:modified>`, created, created) +
+		`</cp:coreProperties>`
+
+	var output bytes.Buffer
+	archive := zip.NewWriter(&output)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxPackageRels},
+		{"docProps/core.xml", coreXML},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", styles},
+		{"word/document.xml", documentXML},
+	}
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("生成DOCX失败: %w", err)
+		}
+		if _, err := io.WriteString(writer, part.content); err != nil {
+			return nil, fmt.Errorf("生成DOCX失败: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("生成DOCX失败: %w", err)
+	}
+	return output.Bytes(), nil
+}
+
+// docxStyles 读取参考文档的样式：导出选项指定的.docx文件 > 模板目录中的 transcript.docx > 内置样式
+func (s *ExportService) docxStyles(options models.ExportOptions) (string, error) {
+	reference := ""
+	if strings.EqualFold(filepath.Ext(options.TemplatePath), ".docx") {
+		reference = options.TemplatePath
+	} else if s.templateDir != "" {
+		if _, err := os.Stat(filepath.Join(s.templateDir, docxTemplateFile)); err == nil {
+			reference = filepath.Join(s.templateDir, docxTemplateFile)
+		}
+	}
+	if reference == "" {
+		return docxStyles, nil
+	}
+
+	archive, err := zip.OpenReader(reference)
+	if err != nil {
+		return "", fmt.Errorf("读取DOCX参考文档失败: %w", err)
+	}
+	defer archive.Close()
+	for _, file := range archive.File {
+		if file.Name != "word/styles.xml" {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return "", fmt.Errorf("读取DOCX参考文档失败: %w", err)
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return "", fmt.Errorf("读取DOCX参考文档失败: %w", err)
+		}
+		fmt.Printf("📄 使用DOCX参考文档的样式: %s\n", reference)
+		return string(data), nil
+	}
+	return "", fmt.Errorf("DOCX参考文档中没有样式: %s", reference)
+}
+
+// docxParagraph 生成段落，style为空时使用正文样式
+func docxParagraph(style, runs string) string {
+	if style == "" {
+		return "<w:p>" + runs + "</w:p>"
+	}
+	return fmt.Sprintf(`<w:p><w:pPr><w:pStyle w:val="%s"/></w:pPr>%s</w:p>`, style, runs)
+}
+
+// docxRun 生成文本，style为字符样式
+func docxRun(style, text string, bold bool) string {
+	var properties string
+	if style != "" {
+		properties += fmt.Sprintf(`<w:rStyle w:val="%s"/>`, style)
+	}
+	if bold {
+		properties += "<w:b/>"
+	}
+	if properties != "" {
+		properties = "<w:rPr>" + properties + "</w:rPr>"
+	}
+	return fmt.Sprintf(`<w:r>%s<w:t xml:space="preserve">%s</w:t></w:r>`, properties, escapeXML(text))
+}
@@ -5,21 +5,28 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"time"
 
 	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/utils"
 )
 
 // ExportService 导出服务
-type ExportService struct{}
+type ExportService struct {
+	templateDir string // 自定义文稿模板目录
+}
 
-// NewExportService 创建导出服务
-func NewExportService() *ExportService {
-	return &ExportService{}
+// NewExportService 创建导出服务，templateDir中的同名模板覆盖内置的文稿模板
+func NewExportService(templateDir string) *ExportService {
+	return &ExportService{templateDir: templateDir}
 }
 
-// ExportResult 导出识别结果
+// ExportResult 导出识别结果（使用默认导出选项）
 func (s *ExportService) ExportResult(resultJSON, format, outputPath string) *models.RecognitionError {
+	return s.ExportResultWithOptions(resultJSON, format, outputPath, models.ExportOptions{})
+}
+
+// ExportResultWithOptions 按导出选项导出识别结果，字幕格式使用其中的行长度、阅读速度和时长限制
+func (s *ExportService) ExportResultWithOptions(resultJSON, format, outputPath string, options models.ExportOptions) *models.RecognitionError {
 	var result models.RecognitionResult
 	if err := json.Unmarshal([]byte(resultJSON), &result); err != nil {
 		return models.NewRecognitionError(
@@ -37,9 +44,35 @@ func (s *ExportService) ExportResult(resultJSON, format, outputPath string) *mod
 	case "txt":
 		content = s.exportToTXT(result)
 	case "srt":
-		content = s.exportToSRT(result)
+		content = s.exportToSRT(result, options)
 	case "vtt":
-		content = s.exportToVTT(result)
+		content = s.exportToVTT(result, options)
+	case "ass":
+		content = s.exportToASS(result, options)
+	case "ttml":
+		content = s.exportToTTML(result, options)
+	case "sbv":
+		content = s.exportToSBV(result, options)
+	case "stl":
+		var data []byte
+		data, err = s.exportToSTL(result, options)
+		content = string(data)
+	case "fcpxml":
+		content, err = s.exportToFCPXML(result, options)
+	case "xmeml":
+		content, err = s.exportToXMEML(result, options)
+	case "edl":
+		content, err = s.exportToEDL(result, options)
+	case "audacity":
+		content = s.exportToAudacity(result)
+	case "docx":
+		var data []byte
+		data, err = s.exportToDOCX(result, options)
+		content = string(data)
+	case "md":
+		content, err = s.exportToMarkdown(result, options)
+	case "html":
+		content, err = s.exportToHTML(result, options)
 	case "json":
 		contentBytes, err := json.MarshalIndent(result, "", "  ")
 		content = string(contentBytes)
@@ -81,17 +114,17 @@ func (s *ExportService) ExportToTXT(result models.RecognitionResult) string {
 
 // ExportToSRT 导出为SRT字幕格式
 func (s *ExportService) ExportToSRT(result models.RecognitionResult) string {
-	return s.exportToSRT(result)
+	return s.exportToSRT(result, models.ExportOptions{})
 }
 
 // ExportToVTT 导出为WebVTT格式
 func (s *ExportService) ExportToVTT(result models.RecognitionResult) string {
-	return s.exportToVTT(result)
+	return s.exportToVTT(result, models.ExportOptions{})
 }
 
 // GetSupportedFormats 获取支持的导出格式
 func (s *ExportService) GetSupportedFormats() []string {
-	return []string{"txt", "srt", "vtt", "json"}
+	return []string{"txt", "srt", "vtt", "ass", "ttml", "sbv", "stl", "fcpxml", "xmeml", "edl", "audacity", "docx", "md", "html", "json"}
 }
 
 // 内部方法
@@ -102,41 +135,131 @@ func (s *ExportService) exportToTXT(result models.RecognitionResult) string {
 }
 
 // exportToSRT 导出为SRT字幕格式
-func (s *ExportService) exportToSRT(result models.RecognitionResult) string {
+func (s *ExportService) exportToSRT(result models.RecognitionResult, options models.ExportOptions) string {
+	settings := resolveSubtitleSettings(result, options)
 	var srt strings.Builder
 
-	for i, word := range result.Words {
-		startSec := int64(word.Start)
-		startMS := int64((word.Start - float64(startSec)) * 1000)
-		endSec := int64(word.End)
-		endMS := int64((word.End - float64(endSec)) * 1000)
-
-		startTime := time.Unix(startSec, startMS*int64(time.Millisecond))
-		endTime := time.Unix(endSec, endMS*int64(time.Millisecond))
+	for i, cue := range buildSubtitleCues(result, settings) {
+		lines := append([]string(nil), cue.Lines...)
+		// SRT没有注释语法，说话人写在首行前，置信度写在末行后
+		if settings.speaker && cue.NewSpeaker {
+			lines[0] = cue.Speaker + ": " + lines[0]
+		}
+		if settings.confidence && cue.Confidence > 0 {
+			lines[len(lines)-1] += fmt.Sprintf(" [%.0f%%]", cue.Confidence*100)
+		}
+		lines = append(lines, cue.Translation...)
 
 		srt.WriteString(fmt.Sprintf("%d\n", i+1))
-		srt.WriteString(fmt.Sprintf("%s --> %s\n",
-			startTime.Format("15:04:05,000"),
-			endTime.Format("15:04:05,000")))
-		srt.WriteString(fmt.Sprintf("%s\n\n", word.Text))
+		srt.WriteString(fmt.Sprintf("%s --> %s\n", utils.FormatSRTTime(cue.Start), utils.FormatSRTTime(cue.End)))
+		srt.WriteString(strings.Join(lines, "\n") + "\n\n")
 	}
 
 	return srt.String()
 }
 
 // exportToVTT 导出为WebVTT格式
-func (s *ExportService) exportToVTT(result models.RecognitionResult) string {
+func (s *ExportService) exportToVTT(result models.RecognitionResult, options models.ExportOptions) string {
+	settings := resolveSubtitleSettings(result, options)
 	var vtt strings.Builder
 	vtt.WriteString("WEBVTT\n\n")
 
-	for _, word := range result.Words {
-		vtt.WriteString(fmt.Sprintf("%.2f --> %.2f\n", word.Start, word.End))
-		vtt.WriteString(fmt.Sprintf("%s\n\n", word.Text))
+	// 源文件信息写入NOTE块（播放器会忽略）
+	if source := s.sourceDescription(result); len(source) > 0 {
+		vtt.WriteString("NOTE\n")
+		for _, line := range source {
+			vtt.WriteString(line + "\n")
+		}
+		vtt.WriteString("\n")
+	}
+
+	for _, cue := range buildSubtitleCues(result, settings) {
+		if settings.confidence && cue.Confidence > 0 {
+			vtt.WriteString(fmt.Sprintf("NOTE 置信度 %.0f%%\n\n", cue.Confidence*100))
+		}
+
+		lines := make([]string, 0, len(cue.Lines)+len(cue.Translation))
+		for _, line := range cue.Lines {
+			lines = append(lines, escapeVTTText(line))
+		}
+		// 说话人使用WebVTT的声音标签，与其他格式一样只在说话人变化时标注
+		if settings.speaker && cue.NewSpeaker {
+			lines[0] = fmt.Sprintf("<v %s>%s", escapeVTTText(cue.Speaker), lines[0])
+		}
+		for _, line := range cue.Translation {
+			lines = append(lines, escapeVTTText(line))
+		}
+
+		vtt.WriteString(fmt.Sprintf("%s --> %s\n", utils.FormatWebVTTTime(cue.Start), utils.FormatWebVTTTime(cue.End)))
+		vtt.WriteString(strings.Join(lines, "\n") + "\n\n")
 	}
 
 	return vtt.String()
 }
 
+// escapeVTTText 转义WebVTT字幕文本中的特殊字符
+func escapeVTTText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// sourceDescription 根据识别结果元数据生成源文件描述（标题、艺术家、专辑、编码信息）
+func (s *ExportService) sourceDescription(result models.RecognitionResult) []string {
+	var lines []string
+
+	if tags, ok := result.Metadata["audio_tags"]; ok {
+		tagLabels := []struct{ key, label string }{
+			{"title", "标题"},
+			{"artist", "艺术家"},
+			{"album", "专辑"},
+		}
+		for _, tag := range tagLabels {
+			if value := metadataTag(tags, tag.key); value != "" {
+				lines = append(lines, fmt.Sprintf("%s: %s", tag.label, value))
+			}
+		}
+	}
+
+	var audioParts []string
+	if codec, ok := result.Metadata["audio_codec"].(string); ok && codec != "" {
+		audioParts = append(audioParts, codec)
+	}
+	if sampleRate := metadataNumber(result.Metadata["sample_rate"]); sampleRate > 0 {
+		audioParts = append(audioParts, fmt.Sprintf("%.0fHz", sampleRate))
+	}
+	if channels := metadataNumber(result.Metadata["channels"]); channels > 0 {
+		audioParts = append(audioParts, fmt.Sprintf("%.0f声道", channels))
+	}
+	if len(audioParts) > 0 {
+		lines = append(lines, "音频: "+strings.Join(audioParts, ", "))
+	}
+
+	return lines
+}
+
+// metadataTag 读取标签值，兼容识别结果直接传入和经过JSON往返后的两种类型
+func metadataTag(tags interface{}, key string) string {
+	switch t := tags.(type) {
+	case map[string]string:
+		return t[key]
+	case map[string]interface{}:
+		if value, ok := t[key].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// metadataNumber 读取数值元数据（JSON往返后整数会变为float64）
+func metadataNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
 // writeToFile 写入文件
 func (s *ExportService) writeToFile(filePath, content string) error {
 	return os.WriteFile(filePath, []byte(content), 0644)
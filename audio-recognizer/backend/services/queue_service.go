@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"tingshengbianzi/backend/models"
+)
+
+const (
+	// DefaultQueueConcurrency 默认同时识别的队列任务数
+	DefaultQueueConcurrency = 1
+	// MaxQueueConcurrency 同时识别的队列任务数上限
+	MaxQueueConcurrency = 8
+)
+
+// QueueRunner 识别一个队列任务，payload为加入队列时附带的识别请求
+type QueueRunner func(ctx context.Context, job models.QueueJob, payload interface{}, progress func(*models.RecognitionProgress)) (*models.RecognitionResult, error)
+
+// queueEntry 队列中的任务及其运行状态
+type queueEntry struct {
+	job        models.QueueJob
+	payload    interface{}
+	cancel     context.CancelFunc // 识别中的任务的取消函数
+	pausing    bool               // 暂停识别中的任务，结束后改为paused
+	cancelling bool               // 取消识别中的任务，结束后改为cancelled
+}
+
+// queueEvent 待发送的队列事件
+type queueEvent struct {
+	eventType string
+	data      interface{}
+}
+
+// QueueService 批量识别队列：按顺序识别多个文件，支持并发数、暂停/恢复/取消单个任务和调整顺序
+// 每个任务的状态、进度和结果通过以任务ID区分的事件通知前端
+type QueueService struct {
+	mu          sync.Mutex
+	ctx         context.Context
+	cancelAll   context.CancelFunc
+	runner      QueueRunner
+	emit        func(eventType string, data interface{})
+	entries     []*queueEntry // 按识别顺序排列
+	concurrency int
+	running     int
+	sequence    int
+	closed      bool
+	events      []queueEvent // 持有锁时产生、解锁后发送的事件
+	emitting    bool         // 有goroutine正在发送事件，其他goroutine产生的事件由它按顺序发送
+}
+
+// NewQueueService 创建批量识别队列，runner执行识别，emit发送事件
+func NewQueueService(runner QueueRunner, emit func(eventType string, data interface{})) *QueueService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &QueueService{
+		ctx:         ctx,
+		cancelAll:   cancel,
+		runner:      runner,
+		emit:        emit,
+		concurrency: DefaultQueueConcurrency,
+	}
+}
+
+// SetConcurrency 设置同时识别的任务数，0为默认值；减少时识别中的任务继续完成
+func (s *QueueService) SetConcurrency(concurrency int) {
+	if concurrency <= 0 {
+		concurrency = DefaultQueueConcurrency
+	}
+	s.mu.Lock()
+	defer s.unlock()
+	s.concurrency = min(concurrency, MaxQueueConcurrency)
+	s.scheduleLocked()
+}
+
+// Concurrency 当前同时识别的任务数
+func (s *QueueService) Concurrency() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.concurrency
+}
+
+// Enqueue 将文件加入队列末尾
+func (s *QueueService) Enqueue(filePath string, size int64, payload interface{}) models.QueueJob {
+	s.mu.Lock()
+	defer s.unlock()
+
+	s.sequence++
+	entry := &queueEntry{
+		job: models.QueueJob{
+			ID:        fmt.Sprintf("job_%d_%d", time.Now().UnixNano(), s.sequence),
+			FilePath:  filePath,
+			Name:      filepath.Base(filePath),
+			Size:      size,
+			Status:    models.QueueJobPending,
+			CreatedAt: time.Now(),
+		},
+		payload: payload,
+	}
+	s.entries = append(s.entries, entry)
+	s.notifyLocked("queue_job_added", entry.job)
+	s.scheduleLocked()
+	return entry.job
+}
+
+// Jobs 按识别顺序返回全部任务
+func (s *QueueService) Jobs() []models.QueueJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobsLocked()
+}
+
+// Job 获取任务
+func (s *QueueService) Job(id string) (models.QueueJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, _, err := s.findLocked(id)
+	if err != nil {
+		return models.QueueJob{}, err
+	}
+	return entry.job, nil
+}
+
+// Pause 暂停任务。等待中的任务不再被调度；识别中的任务立即停止，恢复后从头识别
+func (s *QueueService) Pause(id string) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	entry, _, err := s.findLocked(id)
+	if err != nil {
+		return err
+	}
+	switch entry.job.Status {
+	case models.QueueJobPending:
+		entry.job.Status = models.QueueJobPaused
+		s.notifyLocked("queue_job_status", entry.job)
+	case models.QueueJobRunning:
+		entry.pausing = true
+		entry.cancel()
+	default:
+		return fmt.Errorf("任务状态为 %s，无法暂停", entry.job.Status)
+	}
+	return nil
+}
+
+// Resume 恢复暂停的任务，失败或已取消的任务重新加入队列（保持原位置）
+func (s *QueueService) Resume(id string) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	entry, _, err := s.findLocked(id)
+	if err != nil {
+		return err
+	}
+	switch entry.job.Status {
+	case models.QueueJobPaused, models.QueueJobFailed, models.QueueJobCancelled:
+	default:
+		return fmt.Errorf("任务状态为 %s，无法恢复", entry.job.Status)
+	}
+	entry.job.Status = models.QueueJobPending
+	entry.job.Error = nil
+	entry.job.Progress = nil
+	entry.job.FinishedAt = nil
+	s.notifyLocked("queue_job_status", entry.job)
+	s.scheduleLocked()
+	return nil
+}
+
+// Cancel 取消任务，识别中的任务立即停止
+func (s *QueueService) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	entry, _, err := s.findLocked(id)
+	if err != nil {
+		return err
+	}
+	switch entry.job.Status {
+	case models.QueueJobPending, models.QueueJobPaused:
+		s.finishLocked(entry, models.QueueJobCancelled)
+		s.notifyLocked("queue_job_status", entry.job)
+	case models.QueueJobRunning:
+		entry.pausing = false
+		entry.cancelling = true
+		entry.cancel()
+	default:
+		return fmt.Errorf("任务已结束")
+	}
+	return nil
+}
+
+// Move 调整任务在队列中的位置（从0开始），位置越靠前越先识别
+func (s *QueueService) Move(id string, position int) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	entry, index, err := s.findLocked(id)
+	if err != nil {
+		return err
+	}
+	position = max(0, min(position, len(s.entries)-1))
+	s.entries = append(s.entries[:index], s.entries[index+1:]...)
+	s.entries = append(s.entries[:position], append([]*queueEntry{entry}, s.entries[position:]...)...)
+	s.notifyLocked("queue_updated", s.jobsLocked())
+	return nil
+}
+
+// Remove 从队列中移除任务，识别中的任务需要先取消
+func (s *QueueService) Remove(id string) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	entry, index, err := s.findLocked(id)
+	if err != nil {
+		return err
+	}
+	if entry.job.Status == models.QueueJobRunning {
+		return fmt.Errorf("任务正在识别，请先取消")
+	}
+	s.entries = append(s.entries[:index], s.entries[index+1:]...)
+	s.notifyLocked("queue_updated", s.jobsLocked())
+	return nil
+}
+
+// ClearFinished 移除全部已结束的任务，返回移除的数量
+func (s *QueueService) ClearFinished() int {
+	s.mu.Lock()
+	defer s.unlock()
+
+	kept := s.entries[:0]
+	for _, entry := range s.entries {
+		if !entry.job.Finished() {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(s.entries) - len(kept)
+	s.entries = kept
+	if removed > 0 {
+		s.notifyLocked("queue_updated", s.jobsLocked())
+	}
+	return removed
+}
+
+// Close 停止调度并取消全部识别中的任务（应用退出时调用）
+func (s *QueueService) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cancelAll()
+}
+
+// scheduleLocked 按队列顺序启动等待中的任务，直到达到并发数（调用方需持有锁）
+func (s *QueueService) scheduleLocked() {
+	for _, entry := range s.entries {
+		if s.closed || s.running >= s.concurrency {
+			return
+		}
+		if entry.job.Status == models.QueueJobPending {
+			s.startLocked(entry)
+		}
+	}
+}
+
+// startLocked 启动任务（调用方需持有锁）
+func (s *QueueService) startLocked(entry *queueEntry) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	now := time.Now()
+	entry.cancel = cancel
+	entry.job.Status = models.QueueJobRunning
+	entry.job.Attempts++
+	entry.job.StartedAt = &now
+	entry.job.Progress = nil
+	entry.job.Error = nil
+	s.running++
+	s.notifyLocked("queue_job_status", entry.job)
+	fmt.Printf("📋 队列开始识别: %s (%s)\n", entry.job.Name, entry.job.ID)
+
+	go s.run(ctx, entry, entry.job, entry.payload)
+}
+
+// run 执行任务并根据结果更新状态
+func (s *QueueService) run(ctx context.Context, entry *queueEntry, job models.QueueJob, payload interface{}) {
+	result, err := s.runner(ctx, job, payload, func(progress *models.RecognitionProgress) {
+		// 与状态事件一起排队发送，保证进度不会早于开始识别的事件
+		s.mu.Lock()
+		entry.job.Progress = progress
+		s.notifyLocked("queue_job_progress", map[string]interface{}{
+			"jobId":    job.ID,
+			"progress": progress,
+		})
+		s.unlock()
+	})
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	entry.cancel()
+	entry.cancel = nil
+	s.running--
+	switch {
+	case entry.pausing:
+		entry.pausing = false
+		entry.job.Status = models.QueueJobPaused
+		entry.job.Progress = nil
+	case entry.cancelling || s.ctx.Err() != nil:
+		entry.cancelling = false
+		s.finishLocked(entry, models.QueueJobCancelled)
+	case err != nil:
+		s.finishLocked(entry, models.QueueJobFailed)
+		entry.job.Error = queueJobError(err)
+		fmt.Printf("❌ 队列任务失败: %s: %v\n", job.Name, err)
+	default:
+		s.finishLocked(entry, models.QueueJobCompleted)
+		entry.job.ResultID = result.ID
+		fmt.Printf("✅ 队列任务完成: %s\n", job.Name)
+	}
+	s.notifyLocked("queue_job_status", entry.job)
+	if entry.job.Status == models.QueueJobCompleted {
+		s.notifyLocked("queue_job_result", map[string]interface{}{
+			"jobId":  job.ID,
+			"result": result,
+		})
+	}
+	s.scheduleLocked()
+}
+
+// finishLocked 将任务标记为已结束（调用方需持有锁）
+func (s *QueueService) finishLocked(entry *queueEntry, status string) {
+	now := time.Now()
+	entry.job.Status = status
+	entry.job.Progress = nil
+	entry.job.FinishedAt = &now
+}
+
+// findLocked 查找任务及其位置（调用方需持有锁）
+func (s *QueueService) findLocked(id string) (*queueEntry, int, error) {
+	for i, entry := range s.entries {
+		if entry.job.ID == id {
+			return entry, i, nil
+		}
+	}
+	return nil, -1, fmt.Errorf("队列任务不存在: %s", id)
+}
+
+// jobsLocked 复制全部任务（调用方需持有锁）
+func (s *QueueService) jobsLocked() []models.QueueJob {
+	jobs := make([]models.QueueJob, 0, len(s.entries))
+	for _, entry := range s.entries {
+		jobs = append(jobs, entry.job)
+	}
+	return jobs
+}
+
+// notifyLocked 记录待发送的事件，解锁后发送（调用方需持有锁）
+func (s *QueueService) notifyLocked(eventType string, data interface{}) {
+	s.events = append(s.events, queueEvent{eventType: eventType, data: data})
+}
+
+// unlock 释放锁并发送期间产生的事件，避免事件接收方回调队列时死锁
+// 同一时间只有一个goroutine发送事件，事件按产生的顺序发送
+func (s *QueueService) unlock() {
+	if s.emitting {
+		s.mu.Unlock()
+		return
+	}
+	s.emitting = true
+	for len(s.events) > 0 {
+		events := s.events
+		s.events = nil
+		s.mu.Unlock()
+		for _, event := range events {
+			s.emit(event.eventType, event.data)
+		}
+		s.mu.Lock()
+	}
+	s.emitting = false
+	s.mu.Unlock()
+}
+
+// queueJobError 将识别错误转换为前端使用的错误结构
+func queueJobError(err error) *models.RecognitionError {
+	var recognitionErr *models.RecognitionError
+	if errors.As(err, &recognitionErr) {
+		return recognitionErr
+	}
+	if errors.Is(err, models.ErrDiskSpaceFull) {
+		return models.NewRecognitionError(models.ErrorCodeDiskSpaceFull, "磁盘空间不足", err.Error())
+	}
+	return models.NewRecognitionError(models.ErrorCodeRecognitionFailed, "语音识别失败", err.Error())
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"tingshengbianzi/backend/models"
+)
+
+// stubJob 队列测试中一个任务的识别过程，识别开始后阻塞到release收到结果或被取消
+type stubJob struct {
+	started chan struct{}
+	release chan error
+}
+
+// queueTestEvent 队列发送的一个事件
+type queueTestEvent struct {
+	eventType string
+	jobID     string
+	status    string
+}
+
+// queueHarness 使用阻塞式识别替身的队列
+type queueHarness struct {
+	queue  *QueueService
+	mu     sync.Mutex
+	events []queueTestEvent
+}
+
+// newQueueHarness 创建队列，测试结束时关闭
+func newQueueHarness(t *testing.T) *queueHarness {
+	t.Helper()
+	h := &queueHarness{}
+	h.queue = NewQueueService(func(ctx context.Context, job models.QueueJob, payload interface{}, progress func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+		stub := payload.(*stubJob)
+		progress(&models.RecognitionProgress{Status: "识别中", Percentage: 50})
+		stub.started <- struct{}{}
+		select {
+		case err := <-stub.release:
+			if err != nil {
+				return nil, err
+			}
+			return &models.RecognitionResult{ID: "result_" + job.ID}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}, h.record)
+	t.Cleanup(h.queue.Close)
+	return h
+}
+
+// record 记录事件，作为队列的emit使用
+func (h *queueHarness) record(eventType string, data interface{}) {
+	event := queueTestEvent{eventType: eventType}
+	switch value := data.(type) {
+	case models.QueueJob:
+		event.jobID, event.status = value.ID, value.Status
+	case map[string]interface{}:
+		event.jobID, _ = value["jobId"].(string)
+	}
+	h.mu.Lock()
+	h.events = append(h.events, event)
+	h.mu.Unlock()
+}
+
+// jobEvents 指定任务的事件，格式为"事件类型"或"事件类型:状态"
+func (h *queueHarness) jobEvents(id string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var events []string
+	for _, event := range h.events {
+		if event.jobID != id {
+			continue
+		}
+		if event.status != "" && event.eventType == "queue_job_status" {
+			events = append(events, event.eventType+":"+event.status)
+		} else {
+			events = append(events, event.eventType)
+		}
+	}
+	return events
+}
+
+// enqueue 加入一个任务
+func (h *queueHarness) enqueue(name string) (models.QueueJob, *stubJob) {
+	stub := &stubJob{started: make(chan struct{}, 4), release: make(chan error, 1)}
+	return h.queue.Enqueue("/audio/"+name, 1024, stub), stub
+}
+
+// waitStarted 等待任务开始识别
+func waitStarted(t *testing.T, job models.QueueJob, stub *stubJob) {
+	t.Helper()
+	select {
+	case <-stub.started:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("等待任务 %s 开始识别超时", job.Name)
+	}
+}
+
+// assertNotStarted 确认任务没有开始识别
+func assertNotStarted(t *testing.T, job models.QueueJob, stub *stubJob) {
+	t.Helper()
+	select {
+	case <-stub.started:
+		t.Fatalf("任务 %s 不应开始识别", job.Name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// waitStatus 等待任务进入指定状态
+func (h *queueHarness) waitStatus(t *testing.T, job models.QueueJob, status string) models.QueueJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		current, err := h.queue.Job(job.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current.Status == status {
+			return current
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("任务 %s 的状态为 %s，期望 %s", job.Name, current.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// queueOrder 按识别顺序排列的任务名
+func (h *queueHarness) queueOrder() []string {
+	var names []string
+	for _, job := range h.queue.Jobs() {
+		names = append(names, job.Name)
+	}
+	return names
+}
+
+// TestQueueService 队列调度：暂停/恢复、取消、调整顺序、移除、清除和并发数
+func TestQueueService(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, h *queueHarness)
+	}{
+		{"暂停识别中的任务，恢复后重新识别", func(t *testing.T, h *queueHarness) {
+			a, stubA := h.enqueue("a.mp3")
+			b, stubB := h.enqueue("b.mp3")
+			waitStarted(t, a, stubA)
+
+			if err := h.queue.Pause(a.ID); err != nil {
+				t.Fatal(err)
+			}
+			paused := h.waitStatus(t, a, models.QueueJobPaused)
+			if paused.Progress != nil || paused.FinishedAt != nil {
+				t.Errorf("暂停的任务不应保留进度或结束时间: %+v", paused)
+			}
+			waitStarted(t, b, stubB)
+
+			if err := h.queue.Resume(a.ID); err != nil {
+				t.Fatal(err)
+			}
+			h.waitStatus(t, a, models.QueueJobPending)
+			assertNotStarted(t, a, stubA)
+
+			stubB.release <- nil
+			waitStarted(t, a, stubA)
+			if resumed := h.waitStatus(t, a, models.QueueJobRunning); resumed.Attempts != 2 {
+				t.Errorf("恢复后识别次数为 %d，期望 2", resumed.Attempts)
+			}
+			stubA.release <- nil
+			if completed := h.waitStatus(t, a, models.QueueJobCompleted); completed.ResultID != "result_"+a.ID {
+				t.Errorf("结果ID为 %q", completed.ResultID)
+			}
+		}},
+		{"取消等待中的任务", func(t *testing.T, h *queueHarness) {
+			a, stubA := h.enqueue("a.mp3")
+			b, stubB := h.enqueue("b.mp3")
+			waitStarted(t, a, stubA)
+
+			if err := h.queue.Cancel(b.ID); err != nil {
+				t.Fatal(err)
+			}
+			if cancelled, _ := h.queue.Job(b.ID); cancelled.Status != models.QueueJobCancelled || cancelled.FinishedAt == nil {
+				t.Errorf("等待中的任务应立即取消: %+v", cancelled)
+			}
+			stubA.release <- nil
+			h.waitStatus(t, a, models.QueueJobCompleted)
+			assertNotStarted(t, b, stubB)
+			if err := h.queue.Cancel(b.ID); err == nil {
+				t.Error("已结束的任务不能再次取消")
+			}
+		}},
+		{"取消识别中的任务", func(t *testing.T, h *queueHarness) {
+			a, stubA := h.enqueue("a.mp3")
+			b, stubB := h.enqueue("b.mp3")
+			waitStarted(t, a, stubA)
+
+			if err := h.queue.Cancel(a.ID); err != nil {
+				t.Fatal(err)
+			}
+			if cancelled := h.waitStatus(t, a, models.QueueJobCancelled); cancelled.Error != nil {
+				t.Errorf("取消的任务不应记录错误: %+v", cancelled.Error)
+			}
+			waitStarted(t, b, stubB)
+			if err := h.queue.Resume(a.ID); err != nil {
+				t.Fatalf("取消的任务应能重新加入队列: %v", err)
+			}
+			h.waitStatus(t, a, models.QueueJobPending)
+		}},
+		{"调整到识别中的任务之前", func(t *testing.T, h *queueHarness) {
+			a, stubA := h.enqueue("a.mp3")
+			b, stubB := h.enqueue("b.mp3")
+			c, stubC := h.enqueue("c.mp3")
+			waitStarted(t, a, stubA)
+
+			if err := h.queue.Move(c.ID, 0); err != nil {
+				t.Fatal(err)
+			}
+			if order := h.queueOrder(); !reflect.DeepEqual(order, []string{"c.mp3", "a.mp3", "b.mp3"}) {
+				t.Errorf("队列顺序为 %v", order)
+			}
+			// 调整顺序不影响识别中的任务
+			h.waitStatus(t, a, models.QueueJobRunning)
+			assertNotStarted(t, c, stubC)
+
+			stubA.release <- nil
+			waitStarted(t, c, stubC)
+			assertNotStarted(t, b, stubB)
+		}},
+		{"识别中的任务不能移除", func(t *testing.T, h *queueHarness) {
+			a, stubA := h.enqueue("a.mp3")
+			waitStarted(t, a, stubA)
+
+			if err := h.queue.Remove(a.ID); err == nil {
+				t.Fatal("识别中的任务应拒绝移除")
+			}
+			if _, err := h.queue.Job(a.ID); err != nil {
+				t.Fatalf("拒绝移除后任务应仍在队列中: %v", err)
+			}
+			if err := h.queue.Cancel(a.ID); err != nil {
+				t.Fatal(err)
+			}
+			h.waitStatus(t, a, models.QueueJobCancelled)
+			if err := h.queue.Remove(a.ID); err != nil {
+				t.Fatalf("取消后移除失败: %v", err)
+			}
+			if len(h.queue.Jobs()) != 0 {
+				t.Errorf("移除后队列中仍有 %d 个任务", len(h.queue.Jobs()))
+			}
+		}},
+		{"清除已结束的任务", func(t *testing.T, h *queueHarness) {
+			a, stubA := h.enqueue("a.mp3")
+			b, stubB := h.enqueue("b.mp3")
+			c, _ := h.enqueue("c.mp3")
+			d, _ := h.enqueue("d.mp3")
+			waitStarted(t, a, stubA)
+			stubA.release <- nil
+			waitStarted(t, b, stubB)
+			if err := h.queue.Cancel(c.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := h.queue.Pause(d.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			if removed := h.queue.ClearFinished(); removed != 2 {
+				t.Errorf("清除了 %d 个任务，期望 2", removed)
+			}
+			if order := h.queueOrder(); !reflect.DeepEqual(order, []string{"b.mp3", "d.mp3"}) {
+				t.Errorf("清除后队列为 %v，期望保留识别中和暂停的任务", order)
+			}
+			if removed := h.queue.ClearFinished(); removed != 0 {
+				t.Errorf("再次清除了 %d 个任务", removed)
+			}
+		}},
+		{"调整并发数", func(t *testing.T, h *queueHarness) {
+			a, stubA := h.enqueue("a.mp3")
+			b, stubB := h.enqueue("b.mp3")
+			c, stubC := h.enqueue("c.mp3")
+			waitStarted(t, a, stubA)
+			assertNotStarted(t, b, stubB)
+
+			h.queue.SetConcurrency(2)
+			waitStarted(t, b, stubB)
+			assertNotStarted(t, c, stubC)
+
+			// 减少并发数时识别中的任务继续完成，之后按新的并发数调度
+			h.queue.SetConcurrency(1)
+			h.waitStatus(t, b, models.QueueJobRunning)
+			stubA.release <- nil
+			h.waitStatus(t, a, models.QueueJobCompleted)
+			assertNotStarted(t, c, stubC)
+			stubB.release <- nil
+			waitStarted(t, c, stubC)
+
+			h.queue.SetConcurrency(100)
+			if concurrency := h.queue.Concurrency(); concurrency != MaxQueueConcurrency {
+				t.Errorf("并发数为 %d，期望上限 %d", concurrency, MaxQueueConcurrency)
+			}
+			h.queue.SetConcurrency(0)
+			if concurrency := h.queue.Concurrency(); concurrency != DefaultQueueConcurrency {
+				t.Errorf("并发数为 %d，期望默认值 %d", concurrency, DefaultQueueConcurrency)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newQueueHarness(t))
+		})
+	}
+}
+
+// TestQueueServiceJobEvents 每个任务的事件按 加入 → 开始 → 进度 → 结束 的顺序发送
+func TestQueueServiceJobEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  error
+		want    []string
+		wantErr string
+	}{
+		{"完成", nil, []string{
+			"queue_job_added", "queue_job_status:running", "queue_job_progress",
+			"queue_job_status:completed", "queue_job_result",
+		}, ""},
+		{"失败", errors.New("解码失败"), []string{
+			"queue_job_added", "queue_job_status:running", "queue_job_progress",
+			"queue_job_status:failed",
+		}, models.ErrorCodeRecognitionFailed},
+		{"磁盘空间不足", models.ErrDiskSpaceFull, []string{
+			"queue_job_added", "queue_job_status:running", "queue_job_progress",
+			"queue_job_status:failed",
+		}, models.ErrorCodeDiskSpaceFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newQueueHarness(t)
+			job, stub := h.enqueue("a.mp3")
+			waitStarted(t, job, stub)
+			stub.release <- tt.result
+
+			status := models.QueueJobCompleted
+			if tt.result != nil {
+				status = models.QueueJobFailed
+			}
+			finished := h.waitStatus(t, job, status)
+			if tt.wantErr != "" && (finished.Error == nil || finished.Error.Code != tt.wantErr) {
+				t.Errorf("任务错误为 %+v，期望 %s", finished.Error, tt.wantErr)
+			}
+			// 状态在事件发送前更新，等待最后一个事件
+			deadline := time.Now().Add(5 * time.Second)
+			for len(h.jobEvents(job.ID)) < len(tt.want) && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+			if events := h.jobEvents(job.ID); !reflect.DeepEqual(events, tt.want) {
+				t.Errorf("事件为 %v，期望 %v", events, tt.want)
+			}
+		})
+	}
+}
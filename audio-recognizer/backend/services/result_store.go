@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"tingshengbianzi/backend/models"
+)
+
+const (
+	// maxStoredResults 内存中保留的识别结果数（批量识别一次可能有几十个文件），超出时丢弃最早的结果
+	maxStoredResults = 50
+	// maxResultHistory 每个结果保留的撤销步数
+	maxResultHistory = 20
+)
+
+// ResultSource 识别结果对应的音频来源，重新识别段落时使用
+type ResultSource struct {
+	Path      string                    // 音频文件路径
+	Temporary bool                      // 是否为拖放生成的临时文件，结果被丢弃时删除
+	Language  string                    // 识别语言
+	Engine    string                    // 识别引擎名称
+	Options   models.RecognitionOptions // 原识别选项（任务、词汇表、音轨等）
+}
+
+// storedResult 保存的识别结果及其撤销记录
+type storedResult struct {
+	result  *models.RecognitionResult
+	source  ResultSource
+	history []*models.RecognitionResult // 修改前的版本，最后一个为最近一次修改前
+}
+
+// ResultStore 保存最近的识别结果，支持按ID修改和撤销
+type ResultStore struct {
+	mu      sync.Mutex
+	results map[string]*storedResult
+	order   []string // 按保存顺序排列的结果ID
+}
+
+// NewResultStore 创建识别结果存储
+func NewResultStore() *ResultStore {
+	return &ResultStore{results: make(map[string]*storedResult)}
+}
+
+// Put 保存识别结果，超出数量上限时丢弃最早的结果并删除其临时文件
+func (s *ResultStore) Put(result *models.RecognitionResult, source ResultSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.results[result.ID]; !ok {
+		s.order = append(s.order, result.ID)
+	}
+	s.results[result.ID] = &storedResult{result: result, source: source}
+	for len(s.order) > maxStoredResults {
+		s.removeLocked(s.order[0])
+	}
+}
+
+// Get 获取识别结果和音频来源
+func (s *ResultStore) Get(id string) (*models.RecognitionResult, ResultSource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.results[id]
+	if !ok {
+		return nil, ResultSource{}, fmt.Errorf("识别结果不存在或已过期: %s", id)
+	}
+	return stored.result, stored.source, nil
+}
+
+// Update 用修改后的结果替换当前结果，修改前的版本进入撤销记录
+func (s *ResultStore) Update(id string, result *models.RecognitionResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.results[id]
+	if !ok {
+		return fmt.Errorf("识别结果不存在或已过期: %s", id)
+	}
+	stored.history = append(stored.history, stored.result)
+	if len(stored.history) > maxResultHistory {
+		stored.history = stored.history[len(stored.history)-maxResultHistory:]
+	}
+	stored.result = result
+	return nil
+}
+
+// Undo 撤销最近一次修改，返回恢复后的结果和剩余的撤销步数
+func (s *ResultStore) Undo(id string) (*models.RecognitionResult, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.results[id]
+	if !ok {
+		return nil, 0, fmt.Errorf("识别结果不存在或已过期: %s", id)
+	}
+	if len(stored.history) == 0 {
+		return nil, 0, fmt.Errorf("没有可以撤销的修改")
+	}
+	last := len(stored.history) - 1
+	stored.result = stored.history[last]
+	stored.history = stored.history[:last]
+	return stored.result, len(stored.history), nil
+}
+
+// Cleanup 丢弃全部结果并删除临时文件（应用退出时调用）
+func (s *ResultStore) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.order) > 0 {
+		s.removeLocked(s.order[0])
+	}
+}
+
+// removeLocked 丢弃结果并删除其临时文件（调用方需持有锁）
+func (s *ResultStore) removeLocked(id string) {
+	if stored, ok := s.results[id]; ok && stored.source.Temporary {
+		os.Remove(stored.source.Path)
+	}
+	delete(s.results, id)
+	for i, existing := range s.order {
+		if existing == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
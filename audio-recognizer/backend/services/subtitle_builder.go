@@ -0,0 +1,403 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"tingshengbianzi/backend/models"
+)
+
+// 字幕默认值，参考常见的字幕规范（中日韩文字每个字占位更宽、阅读更慢）
+const (
+	defaultSubtitleMaxLines      = 2
+	defaultSubtitleLineLength    = 42
+	defaultSubtitleLineLengthCJK = 16
+	defaultSubtitleCPS           = 17.0
+	defaultSubtitleCPSCJK        = 9.0
+	defaultSubtitleMinDuration   = 1.0
+	defaultSubtitleMaxDuration   = 7.0
+	defaultSubtitleMinGap        = 0.08
+)
+
+// subtitleCue 一条字幕
+type subtitleCue struct {
+	Start       float64
+	End         float64
+	Text        string   // 未换行的原文
+	Lines       []string // 换行后的原文
+	Translation []string // 换行后的译文（双语字幕）
+	Speaker     string
+	NewSpeaker  bool    // 说话人与上一条字幕不同
+	Confidence  float64 // 平均置信度
+	Words       []models.Word
+}
+
+// subtitleSettings 填充默认值后的字幕选项
+type subtitleSettings struct {
+	maxLines    int
+	lineLength  int
+	maxCPS      float64
+	minDuration float64
+	maxDuration float64
+	minGap      float64
+	splitText   bool
+	speaker     bool
+	translation bool
+	confidence  bool
+}
+
+// resolveSubtitleSettings 填充字幕选项的默认值，行长度和阅读速度的默认值按文本是否以中日韩文字为主决定
+func resolveSubtitleSettings(result models.RecognitionResult, options models.ExportOptions) subtitleSettings {
+	settings := subtitleSettings{
+		maxLines:    options.MaxLines,
+		lineLength:  options.MaxLineLength,
+		maxCPS:      options.MaxCharsPerSecond,
+		minDuration: options.MinCueDuration,
+		maxDuration: options.MaxCueDuration,
+		minGap:      options.MinCueGap,
+		splitText:   options.SplitText,
+		speaker:     options.IncludeSpeaker,
+		translation: options.IncludeTranslation,
+		confidence:  options.IncludeConfidence,
+	}
+
+	cjk := isMostlyCJK(result)
+	if settings.maxLines <= 0 {
+		settings.maxLines = defaultSubtitleMaxLines
+	}
+	if settings.lineLength <= 0 {
+		settings.lineLength = defaultSubtitleLineLength
+		if cjk {
+			settings.lineLength = defaultSubtitleLineLengthCJK
+		}
+	}
+	if settings.maxCPS <= 0 {
+		settings.maxCPS = defaultSubtitleCPS
+		if cjk {
+			settings.maxCPS = defaultSubtitleCPSCJK
+		}
+	}
+	if settings.minDuration <= 0 {
+		settings.minDuration = defaultSubtitleMinDuration
+	}
+	if settings.maxDuration <= 0 {
+		settings.maxDuration = defaultSubtitleMaxDuration
+	}
+	settings.maxDuration = math.Max(settings.maxDuration, settings.minDuration)
+	if settings.minGap <= 0 {
+		settings.minGap = defaultSubtitleMinGap
+	}
+	return settings
+}
+
+// buildSubtitleCues 根据段落和词时间生成字幕：
+// 超出行数或最长时长的段落按词拆分（优先在标点处断开），阅读速度过快或过短的字幕在不影响下一条的前提下延长，
+// 相邻字幕保持最小间隔，多行字幕按长度均衡换行
+func buildSubtitleCues(result models.RecognitionResult, settings subtitleSettings) []subtitleCue {
+	var cues []subtitleCue
+	for _, segment := range result.Segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+
+		// 译文无法按词对齐，带译文的段落不拆分
+		if settings.translation && strings.TrimSpace(segment.Translation) != "" {
+			cue := newSubtitleCue(segment, subtitleUnits(segment), settings)
+			cue.Start, cue.End = segment.Start, segment.End
+			cue.Translation = wrapSubtitleText(segment.Translation, settings.lineLength)
+			cues = append(cues, cue)
+			continue
+		}
+
+		for _, chunk := range splitSubtitleUnits(subtitleUnits(segment), settings) {
+			cues = append(cues, newSubtitleCue(segment, chunk, settings))
+		}
+	}
+
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].Start < cues[j].Start })
+	adjustSubtitleTiming(cues, settings)
+
+	previous := ""
+	for i := range cues {
+		cues[i].NewSpeaker = cues[i].Speaker != "" && cues[i].Speaker != previous
+		previous = cues[i].Speaker
+	}
+	return cues
+}
+
+// newSubtitleCue 由一组词生成字幕
+func newSubtitleCue(segment models.RecognitionResultSegment, words []models.Word, settings subtitleSettings) subtitleCue {
+	text := joinSubtitleWords(words)
+	cue := subtitleCue{
+		Start:      words[0].Start,
+		End:        words[len(words)-1].End,
+		Text:       text,
+		Lines:      wrapSubtitleText(text, settings.lineLength),
+		Speaker:    segment.Speaker,
+		Confidence: segment.Confidence,
+		Words:      words,
+	}
+	if cue.Speaker == "" {
+		cue.Speaker = words[0].Speaker
+	}
+
+	total, count := 0.0, 0
+	for _, word := range words {
+		if word.Confidence > 0 {
+			total += word.Confidence
+			count++
+		}
+	}
+	if count > 0 {
+		cue.Confidence = total / float64(count)
+	}
+	return cue
+}
+
+// subtitleUnits 段落的词；没有词时间时按字符位置在段落时间内插值生成
+func subtitleUnits(segment models.RecognitionResultSegment) []models.Word {
+	var words []models.Word
+	for _, word := range segment.Words {
+		if strings.TrimSpace(word.Text) == "" {
+			continue
+		}
+		word.Text = strings.TrimSpace(word.Text)
+		word.End = math.Max(word.End, word.Start)
+		words = append(words, word)
+	}
+	if len(words) > 0 {
+		return words
+	}
+
+	var tokens []string
+	for _, field := range strings.Fields(segment.Text) {
+		for _, r := range field {
+			switch {
+			case len(tokens) > 0 && isSubtitlePunct(r):
+				tokens[len(tokens)-1] += string(r)
+			case isCJKRune(r) || len(tokens) == 0 || containsCJKRune(tokens[len(tokens)-1]):
+				tokens = append(tokens, string(r))
+			default:
+				tokens[len(tokens)-1] += string(r)
+			}
+		}
+		// 拉丁文字按空格分词，下一个字段另起一个词
+		tokens = append(tokens, "")
+	}
+
+	total := 0
+	for _, token := range tokens {
+		total += utf8.RuneCountInString(token)
+	}
+	duration := math.Max(segment.End-segment.Start, 0)
+	offset := 0
+	for _, token := range tokens {
+		if token == "" {
+			continue
+		}
+		length := utf8.RuneCountInString(token)
+		words = append(words, models.Word{
+			Text:       token,
+			Start:      segment.Start + duration*float64(offset)/float64(total),
+			End:        segment.Start + duration*float64(offset+length)/float64(total),
+			Confidence: segment.Confidence,
+			Speaker:    segment.Speaker,
+		})
+		offset += length
+	}
+	return words
+}
+
+// splitSubtitleUnits 将段落的词分成若干条字幕：每条不超过最多行数和最长时长，
+// 超出时优先在本条后半部分的标点处断开；SplitText时每句单独成为一条字幕
+func splitSubtitleUnits(words []models.Word, settings subtitleSettings) [][]models.Word {
+	fits := func(chunk []models.Word) bool {
+		return len(wrapSubtitleText(joinSubtitleWords(chunk), settings.lineLength)) <= settings.maxLines &&
+			chunk[len(chunk)-1].End-chunk[0].Start <= settings.maxDuration
+	}
+
+	var chunks [][]models.Word
+	var current []models.Word
+	for i, word := range words {
+		if len(current) > 0 && !fits(append(current[:len(current):len(current)], word)) {
+			cut := len(current)
+			half := utf8.RuneCountInString(joinSubtitleWords(current)) / 2
+			for k := len(current) - 1; k > 0; k-- {
+				if endsWithPunct(current[k-1].Text) && utf8.RuneCountInString(joinSubtitleWords(current[:k])) >= half {
+					cut = k
+					break
+				}
+			}
+			chunks = append(chunks, current[:cut])
+			current = append([]models.Word(nil), current[cut:]...)
+		}
+		current = append(current, word)
+
+		if settings.splitText && i < len(words)-1 && endsSentence(word.Text) {
+			chunks = append(chunks, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// adjustSubtitleTiming 调整字幕时间：过短或阅读速度过快时延长（不超过最长时长，且与下一条保持最小间隔），
+// 与下一条重叠或间隔过小时提前结束
+func adjustSubtitleTiming(cues []subtitleCue, settings subtitleSettings) {
+	for i := range cues {
+		cue := &cues[i]
+		limit := math.Inf(1)
+		if i < len(cues)-1 {
+			limit = cues[i+1].Start - settings.minGap
+		}
+
+		desired := math.Max(cue.End, cue.Start+settings.minDuration)
+		chars := float64(utf8.RuneCountInString(strings.Join(cue.Lines, "")))
+		desired = math.Max(desired, cue.Start+chars/settings.maxCPS)
+		desired = math.Min(desired, cue.Start+math.Max(settings.maxDuration, cue.End-cue.Start))
+		if desired > cue.End {
+			cue.End = math.Max(cue.End, math.Min(desired, limit))
+		}
+
+		if cue.End > limit {
+			if limit > cue.Start {
+				cue.End = limit
+			} else if i < len(cues)-1 {
+				// 两条字幕几乎同时开始，无法保留间隔时至少不重叠
+				cue.End = math.Max(cue.Start, math.Min(cue.End, cues[i+1].Start))
+			}
+		}
+	}
+}
+
+// wrapSubtitleText 按每行字符数换行，需要多行时让各行长度尽量均衡；
+// 拉丁文字在空格处断开，中日韩文字可在任意字之间断开（标点不放在行首），优先在标点后断开
+func wrapSubtitleText(text string, lineLength int) []string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return nil
+	}
+
+	var lines []string
+	rest := []rune(text)
+	for len(rest) > lineLength {
+		count := (len(rest) + lineLength - 1) / lineLength
+		target := (len(rest) + count - 1) / count
+
+		cut, best := -1, math.Inf(1)
+		for i := 1; i < len(rest) && i <= lineLength; i++ {
+			if !canBreakBefore(rest, i) {
+				continue
+			}
+			score := math.Abs(float64(i - target))
+			if isSubtitlePunct(rest[i-1]) {
+				score -= 3
+			}
+			if score < best {
+				cut, best = i, score
+			}
+		}
+		if cut < 0 {
+			// 没有可断开的位置（超长单词），在第一个空格处或按行长度强制断开
+			cut = min(lineLength, len(rest))
+			if space := strings.IndexRune(string(rest), ' '); space > 0 {
+				cut = utf8.RuneCountInString(string(rest)[:space])
+			}
+		}
+
+		lines = append(lines, strings.TrimSpace(string(rest[:cut])))
+		rest = []rune(strings.TrimSpace(string(rest[cut:])))
+	}
+	if len(rest) > 0 {
+		lines = append(lines, string(rest))
+	}
+	return lines
+}
+
+// canBreakBefore 判断能否在第i个字符前换行
+func canBreakBefore(runes []rune, i int) bool {
+	if runes[i] == ' ' {
+		return true
+	}
+	if isSubtitlePunct(runes[i]) || runes[i-1] == ' ' {
+		return false
+	}
+	return isCJKRune(runes[i-1]) || isCJKRune(runes[i])
+}
+
+// joinSubtitleWords 连接词文本，中日韩文字之间不加空格
+func joinSubtitleWords(words []models.Word) string {
+	var builder strings.Builder
+	previous := ""
+	for _, word := range words {
+		text := strings.TrimSpace(word.Text)
+		if text == "" {
+			continue
+		}
+		if previous != "" {
+			last, _ := utf8.DecodeLastRuneInString(previous)
+			first, _ := utf8.DecodeRuneInString(text)
+			if !isCJKRune(last) && !isCJKRune(first) && !isSubtitlePunct(first) {
+				builder.WriteByte(' ')
+			}
+		}
+		builder.WriteString(text)
+		previous = text
+	}
+	return builder.String()
+}
+
+// isMostlyCJK 判断识别文本是否以中日韩文字为主
+func isMostlyCJK(result models.RecognitionResult) bool {
+	cjk, letters := 0, 0
+	for _, segment := range result.Segments {
+		for _, r := range segment.Text {
+			if isCJKRune(r) {
+				cjk++
+			} else if unicode.IsLetter(r) {
+				letters++
+			}
+		}
+	}
+	return cjk > 0 && cjk >= letters
+}
+
+// isCJKRune 判断是否为中日韩文字或全角标点
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// containsCJKRune 判断文本是否包含中日韩文字
+func containsCJKRune(text string) bool {
+	for _, r := range text {
+		if isCJKRune(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubtitlePunct 判断是否为不能放在行首的标点
+func isSubtitlePunct(r rune) bool {
+	return strings.ContainsRune(",.!?;:，。！？；：、…)）」』”’", r)
+}
+
+// endsWithPunct 判断词是否以标点结尾
+func endsWithPunct(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimSpace(text))
+	return isSubtitlePunct(r)
+}
+
+// endsSentence 判断词是否以句末标点结尾
+func endsSentence(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimSpace(text))
+	return strings.ContainsRune(".!?。！？…", r)
+}
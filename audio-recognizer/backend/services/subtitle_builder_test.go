@@ -0,0 +1,229 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/utils"
+)
+
+// testSubtitleSettings 测试使用的字幕选项
+func testSubtitleSettings() subtitleSettings {
+	return subtitleSettings{
+		maxLines:    2,
+		lineLength:  defaultSubtitleLineLength,
+		maxCPS:      defaultSubtitleCPS,
+		minDuration: defaultSubtitleMinDuration,
+		maxDuration: defaultSubtitleMaxDuration,
+		minGap:      defaultSubtitleMinGap,
+	}
+}
+
+// testWords 按文本生成词，每个词持续duration秒，从start开始首尾相接
+func testWords(start, duration float64, texts ...string) []models.Word {
+	words := make([]models.Word, 0, len(texts))
+	for i, text := range texts {
+		words = append(words, models.Word{
+			Text:  text,
+			Start: start + float64(i)*duration,
+			End:   start + float64(i+1)*duration,
+		})
+	}
+	return words
+}
+
+// TestSubtitleTimeFormats SRT和WebVTT时间格式：毫秒四舍五入，超过24小时时小时数继续累加
+func TestSubtitleTimeFormats(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		srt     string
+		vtt     string
+	}{
+		{0, "00:00:00,000", "00:00:00.000"},
+		{-1, "00:00:00,000", "00:00:00.000"},
+		{1.9999999, "00:00:02,000", "00:00:02.000"},
+		{3661.001, "01:01:01,001", "01:01:01.001"},
+		{86399.999, "23:59:59,999", "23:59:59.999"},
+		{86400, "24:00:00,000", "24:00:00.000"},
+		{90061.5, "25:01:01,500", "25:01:01.500"},
+		{360000.25, "100:00:00,250", "100:00:00.250"},
+	}
+
+	for _, tt := range tests {
+		if got := utils.FormatSRTTime(tt.seconds); got != tt.srt {
+			t.Errorf("FormatSRTTime(%v) = %s，期望 %s", tt.seconds, got, tt.srt)
+		}
+		if got := utils.FormatWebVTTTime(tt.seconds); got != tt.vtt {
+			t.Errorf("FormatWebVTTTime(%v) = %s，期望 %s", tt.seconds, got, tt.vtt)
+		}
+	}
+}
+
+// TestWrapSubtitleText 按行长度换行：拉丁文字在空格处断开，中日韩文字优先在标点后断开，标点不放在行首
+func TestWrapSubtitleText(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		lineLength int
+		want       []string
+	}{
+		{"空文本", "  ", 16, nil},
+		{"不需要换行", "Hello world", 42, []string{"Hello world"}},
+		{"合并多余空白", "Hello \n  world", 42, []string{"Hello world"}},
+		{"拉丁文字均衡换行", "The quick brown fox jumps over the lazy dog near the river bank", 42,
+			[]string{"The quick brown fox jumps over", "the lazy dog near the river bank"}},
+		{"超长单词", "Pneumonoultramicroscopicsilicovolcanoconiosis is long", 16,
+			[]string{"Pneumonoultramicroscopicsilicovolcanoconiosis", "is long"}},
+		{"中文在标点后断开", "今天天气很好，我们一起去公园散步吧。", 16,
+			[]string{"今天天气很好，", "我们一起去公园散步吧。"}},
+		{"中文标点不在行首", "一二三四五六，七八九十", 8,
+			[]string{"一二三四五六，", "七八九十"}},
+		{"中英混排", "我们用Whisper识别语音，然后导出字幕文件给剪辑软件使用", 16,
+			[]string{"我们用Whisper识别语音，", "然后导出字幕文件给剪辑软件使用"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapSubtitleText(tt.text, tt.lineLength)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("换行结果为 %q，期望 %q", got, tt.want)
+			}
+			for _, line := range got {
+				if first, _ := utf8.DecodeRuneInString(line); isSubtitlePunct(first) {
+					t.Errorf("行 %q 以标点开头", line)
+				}
+			}
+		})
+	}
+}
+
+// TestSplitSubtitleUnits 段落超过最长时长或行数时拆分，优先在后半部分的标点处断开；SplitText时按句拆分
+func TestSplitSubtitleUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []models.Word
+		settings func(*subtitleSettings)
+		want     []string
+	}{
+		{"不需要拆分", testWords(0, 0.5, "Hello", "world."), nil, []string{"Hello world."}},
+		{"超过最长时长", testWords(0, 1, "one", "two", "three", "four", "five", "six", "seven", "eight"),
+			func(s *subtitleSettings) { s.maxDuration = 3 },
+			[]string{"one two three", "four five six", "seven eight"}},
+		{"在标点处断开", testWords(0, 1, "one", "two,", "three", "four", "five"),
+			func(s *subtitleSettings) { s.maxDuration = 3 },
+			[]string{"one two,", "three four five"}},
+		{"超过行数", testWords(0, 0.2, "一", "二", "三", "四", "五", "六", "七"),
+			func(s *subtitleSettings) { s.lineLength = 3; s.maxLines = 1 },
+			[]string{"一二三", "四五六", "七"}},
+		{"按句拆分", testWords(0, 0.5, "你好。", "今天", "怎么样？", "不错"),
+			func(s *subtitleSettings) { s.splitText = true },
+			[]string{"你好。", "今天怎么样？", "不错"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := testSubtitleSettings()
+			if tt.settings != nil {
+				tt.settings(&settings)
+			}
+			chunks := splitSubtitleUnits(tt.words, settings)
+			var got []string
+			for _, chunk := range chunks {
+				got = append(got, joinSubtitleWords(chunk))
+				if duration := chunk[len(chunk)-1].End - chunk[0].Start; duration > settings.maxDuration {
+					t.Errorf("字幕 %q 时长 %.1f 秒超过最长时长 %.1f 秒", joinSubtitleWords(chunk), duration, settings.maxDuration)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("拆分结果为 %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAdjustSubtitleTiming 过短或阅读速度过快的字幕延长（不超过最长时长和下一条字幕），重叠时提前结束
+func TestAdjustSubtitleTiming(t *testing.T) {
+	type span struct{ start, end float64 }
+	tests := []struct {
+		name string
+		cues []subtitleCue
+		want []span
+	}{
+		{"过短的字幕延长到最短时长", []subtitleCue{
+			{Start: 0, End: 0.3, Lines: []string{"Hi"}},
+		}, []span{{0, 1}}},
+		{"延长时与下一条保持间隔", []subtitleCue{
+			{Start: 0, End: 0.3, Lines: []string{"Hi"}},
+			{Start: 0.5, End: 2, Lines: []string{"there"}},
+		}, []span{{0, 0.42}, {0.5, 2}}},
+		{"阅读速度过快时延长", []subtitleCue{
+			{Start: 0, End: 1, Lines: []string{strings.Repeat("a", 34)}},
+		}, []span{{0, 2}}},
+		{"延长不超过最长时长", []subtitleCue{
+			{Start: 0, End: 1, Lines: []string{strings.Repeat("a", 170)}},
+		}, []span{{0, 7}}},
+		{"原本超过最长时长的字幕不缩短", []subtitleCue{
+			{Start: 0, End: 9, Lines: []string{"long"}},
+		}, []span{{0, 9}}},
+		{"与下一条重叠时提前结束", []subtitleCue{
+			{Start: 0, End: 3, Lines: []string{"first"}},
+			{Start: 2, End: 4, Lines: []string{"second"}},
+		}, []span{{0, 1.92}, {2, 4}}},
+		{"几乎同时开始时至少不重叠", []subtitleCue{
+			{Start: 1, End: 3, Lines: []string{"first"}},
+			{Start: 1.05, End: 4, Lines: []string{"second"}},
+		}, []span{{1, 1.05}, {1.05, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustSubtitleTiming(tt.cues, testSubtitleSettings())
+			for i, cue := range tt.cues {
+				if math.Abs(cue.Start-tt.want[i].start) > 1e-9 || math.Abs(cue.End-tt.want[i].end) > 1e-9 {
+					t.Errorf("第 %d 条字幕为 %.3f-%.3f，期望 %.3f-%.3f", i+1, cue.Start, cue.End, tt.want[i].start, tt.want[i].end)
+				}
+			}
+		})
+	}
+}
+
+// TestSubtitleSpeakerLabels SRT和WebVTT使用相同的规则：只在说话人变化时标注说话人
+func TestSubtitleSpeakerLabels(t *testing.T) {
+	result := models.RecognitionResult{Segments: []models.RecognitionResultSegment{
+		{Start: 0, End: 2, Text: "Hello there.", Speaker: "Alice"},
+		{Start: 3, End: 5, Text: "How are you?", Speaker: "Alice"},
+		{Start: 6, End: 8, Text: "Fine, thanks.", Speaker: "Bob"},
+		{Start: 9, End: 11, Text: "Good to hear.", Speaker: "Alice"},
+	}}
+	options := models.ExportOptions{IncludeSpeaker: true}
+	service := NewExportService("")
+
+	srt := service.exportToSRT(result, options)
+	vtt := service.exportToVTT(result, options)
+	labels := map[string][]string{
+		"SRT": {"Alice: Hello there.", "How are you?", "Bob: Fine, thanks.", "Alice: Good to hear."},
+		"VTT": {"<v Alice>Hello there.", "How are you?", "<v Bob>Fine, thanks.", "<v Alice>Good to hear."},
+	}
+	for format, content := range map[string]string{"SRT": srt, "VTT": vtt} {
+		for _, line := range labels[format] {
+			if !strings.Contains(content, "\n"+line+"\n") {
+				t.Errorf("%s中没有 %q:\n%s", format, line, content)
+			}
+		}
+		for _, unlabelled := range []string{"Alice: How are you?", "<v Alice>How are you?"} {
+			if strings.Contains(content, unlabelled) {
+				t.Errorf("%s中说话人没有变化的字幕不应标注说话人:\n%s", format, content)
+			}
+		}
+	}
+
+	// 不标注说话人时两种格式都没有说话人
+	plain := models.ExportOptions{}
+	if content := service.exportToSRT(result, plain) + service.exportToVTT(result, plain); strings.Contains(content, "Alice") {
+		t.Errorf("没有启用说话人标注时不应输出说话人:\n%s", content)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"tingshengbianzi/backend/models"
+)
+
+// defaultTimecodeFrameRate 没有指定帧率且不是视频文件时使用的帧率（PAL）
+const defaultTimecodeFrameRate = 25.0
+
+// resolveFrameRate 时间码帧率：导出选项 > 视频帧率 > 默认25
+func resolveFrameRate(result models.RecognitionResult, options models.ExportOptions) float64 {
+	if options.FrameRate > 0 {
+		return options.FrameRate
+	}
+	if rate := metadataNumber(result.Metadata["video_frame_rate"]); rate > 0 {
+		return rate
+	}
+	return defaultTimecodeFrameRate
+}
+
+// nominalFrameRate 时间码使用的整数帧率（29.97为30，23.976为24）
+func nominalFrameRate(rate float64) int {
+	return max(1, int(math.Round(rate)))
+}
+
+// isNTSCFrameRate 判断是否为 N*1000/1001 的NTSC帧率
+func isNTSCFrameRate(rate float64) bool {
+	nominal := float64(nominalFrameRate(rate))
+	return math.Abs(rate-nominal) > 0.001 && math.Abs(rate-nominal*1000/1001) < 0.01
+}
+
+// secondsToFrames 将秒转换为帧数（按实际帧率）
+func secondsToFrames(seconds, rate float64) int64 {
+	return int64(math.Round(math.Max(seconds, 0) * rate))
+}
+
+// splitTimecode 将帧数按整数帧率拆分为时、分、秒、帧（非丢帧时间码）
+func splitTimecode(frames int64, rate float64) (hours, minutes, seconds, frame int64) {
+	nominal := int64(nominalFrameRate(rate))
+	frame = frames % nominal
+	totalSeconds := frames / nominal
+	return totalSeconds / 3600, (totalSeconds % 3600) / 60, totalSeconds % 60, frame
+}
+
+// formatTimecode 格式化为 HH:MM:SS:FF 非丢帧时间码
+func formatTimecode(frames int64, rate float64) string {
+	hours, minutes, seconds, frame := splitTimecode(frames, rate)
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", hours, minutes, seconds, frame)
+}
+
+// parseTimecode 解析 HH:MM:SS:FF 时间码（丢帧写法 HH:MM:SS;FF 按非丢帧计算），返回帧数
+func parseTimecode(timecode string, rate float64) (int64, error) {
+	timecode = strings.TrimSpace(timecode)
+	if timecode == "" {
+		return 0, nil
+	}
+	parts := strings.FieldsFunc(timecode, func(r rune) bool { return r == ':' || r == ';' })
+	if len(parts) != 4 {
+		return 0, fmt.Errorf("时间码格式无效，应为 HH:MM:SS:FF: %s", timecode)
+	}
+	var values [4]int64
+	for i, part := range parts {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("时间码格式无效，应为 HH:MM:SS:FF: %s", timecode)
+		}
+		values[i] = value
+	}
+	nominal := int64(nominalFrameRate(rate))
+	if values[1] >= 60 || values[2] >= 60 || values[3] >= nominal {
+		return 0, fmt.Errorf("时间码超出范围（%d帧）: %s", nominal, timecode)
+	}
+	return ((values[0]*60+values[1])*60+values[2])*nominal + values[3], nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tingshengbianzi/backend/models"
+)
+
+// fcpxmlBasicTitle Final Cut Pro 内置的基本字幕效果
+const fcpxmlBasicTitle = ".../Titles.localized/Bumper:Opener.localized/Basic Title.localized/Basic Title.moti"
+
+// timelineItem 按帧对齐的段落
+type timelineItem struct {
+	start int64 // 相对时间线起点的开始帧
+	end   int64 // 相对时间线起点的结束帧（不含）
+	text  string
+}
+
+// timeline 剪辑软件时间线的公共参数
+type timeline struct {
+	name   string
+	rate   float64
+	start  int64 // 起始时间码对应的帧数
+	width  int
+	height int
+	items  []timelineItem
+}
+
+// buildTimeline 将段落转换为按帧对齐、互不重叠的时间线条目，说话人写在文本前
+func buildTimeline(result models.RecognitionResult, options models.ExportOptions) (*timeline, error) {
+	rate := resolveFrameRate(result, options)
+	start, err := parseTimecode(options.StartTimecode, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	line := &timeline{name: resultTitle(result), rate: rate, start: start, width: 1920, height: 1080}
+	if width, height := metadataNumber(result.Metadata["video_width"]), metadataNumber(result.Metadata["video_height"]); width > 0 && height > 0 {
+		line.width, line.height = int(width), int(height)
+	}
+
+	for _, segment := range result.Segments {
+		text := strings.Join(strings.Fields(segment.Text), " ")
+		if text == "" {
+			continue
+		}
+		if segment.Speaker != "" {
+			text = segment.Speaker + ": " + text
+		}
+
+		item := timelineItem{
+			start: secondsToFrames(segment.Start, rate),
+			end:   secondsToFrames(segment.End, rate),
+			text:  text,
+		}
+		if n := len(line.items); n > 0 {
+			item.start = max(item.start, line.items[n-1].end)
+		}
+		item.end = max(item.end, item.start+1)
+		line.items = append(line.items, item)
+	}
+	return line, nil
+}
+
+// duration 时间线总帧数
+func (t *timeline) duration() int64 {
+	if len(t.items) == 0 {
+		return 1
+	}
+	return t.items[len(t.items)-1].end
+}
+
+// resultTitle 导出文件中使用的名称：音频标题 > 文件名（不含扩展名） > 识别结果ID
+func resultTitle(result models.RecognitionResult) string {
+	if title := metadataTag(result.Metadata["audio_tags"], "title"); title != "" {
+		return title
+	}
+	if name, ok := result.Metadata["audio_file"].(string); ok && name != "" {
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return "识别结果 " + result.ID
+}
+
+// exportToAudacity 导出为Audacity标签轨道（开始秒数、结束秒数、标签文本，制表符分隔）
+func (s *ExportService) exportToAudacity(result models.RecognitionResult) string {
+	var labels strings.Builder
+	for _, segment := range result.Segments {
+		text := strings.Join(strings.Fields(segment.Text), " ")
+		if text == "" {
+			continue
+		}
+		if segment.Speaker != "" {
+			text = segment.Speaker + ": " + text
+		}
+		labels.WriteString(fmt.Sprintf("%.6f\t%.6f\t%s\n", segment.Start, segment.End, text))
+	}
+	return labels.String()
+}
+
+// exportToEDL 导出为CMX3600 EDL：每个段落一个音频事件，段落文本写在注释中
+func (s *ExportService) exportToEDL(result models.RecognitionResult, options models.ExportOptions) (string, error) {
+	line, err := buildTimeline(result, options)
+	if err != nil {
+		return "", err
+	}
+
+	var edl strings.Builder
+	edl.WriteString(fmt.Sprintf("TITLE: %s\n", strings.ToUpper(edlText(line.name))))
+	edl.WriteString("FCM: NON-DROP FRAME\n\n")
+	for i, item := range line.items {
+		edl.WriteString(fmt.Sprintf("%03d  AX       A     C        %s %s %s %s\n", i+1,
+			formatTimecode(item.start, line.rate), formatTimecode(item.end, line.rate),
+			formatTimecode(line.start+item.start, line.rate), formatTimecode(line.start+item.end, line.rate)))
+		edl.WriteString(fmt.Sprintf("* FROM CLIP NAME: %s\n", edlText(line.name)))
+		edl.WriteString(fmt.Sprintf("* COMMENT: %s\n\n", edlText(item.text)))
+	}
+	return edl.String(), nil
+}
+
+// edlText EDL中的文本只能占一行
+func edlText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// exportToFCPXML 导出为FCPXML 1.9（Final Cut Pro、DaVinci Resolve）：每个段落一个基本字幕和一个标记
+func (s *ExportService) exportToFCPXML(result models.RecognitionResult, options models.ExportOptions) (string, error) {
+	line, err := buildTimeline(result, options)
+	if err != nil {
+		return "", err
+	}
+	frameTime := func(frames int64) string { return fcpxmlTime(frames, line.rate) }
+
+	var fcp strings.Builder
+	fcp.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fcp.WriteString("<!DOCTYPE fcpxml>\n")
+	fcp.WriteString("<fcpxml version=\"1.9\">\n")
+	fcp.WriteString("  <resources>\n")
+	fcp.WriteString(fmt.Sprintf("    <format id=\"r1\" frameDuration=\"%s\" width=\"%d\" height=\"%d\"/>\n", frameTime(1), line.width, line.height))
+	fcp.WriteString(fmt.Sprintf("    <effect id=\"r2\" name=\"Basic Title\" uid=\"%s\"/>\n", fcpxmlBasicTitle))
+	fcp.WriteString("  </resources>\n")
+	fcp.WriteString("  <library>\n")
+	fcp.WriteString(fmt.Sprintf("    <event name=\"%s\">\n", escapeXML(line.name)))
+	fcp.WriteString(fmt.Sprintf("      <project name=\"%s\">\n", escapeXML(line.name)))
+	fcp.WriteString(fmt.Sprintf("        <sequence format=\"r1\" duration=\"%s\" tcStart=\"%s\" tcFormat=\"NDF\">\n",
+		frameTime(line.duration()), frameTime(line.start)))
+	fcp.WriteString("          <spine>\n")
+	// 字幕和标记挂在覆盖整条时间线的空隙上，时间相对空隙的起点（即起始时间码）
+	fcp.WriteString(fmt.Sprintf("            <gap name=\"Gap\" offset=\"%s\" start=\"%s\" duration=\"%s\">\n",
+		frameTime(line.start), frameTime(line.start), frameTime(line.duration())))
+	for i, item := range line.items {
+		text := escapeXML(item.text)
+		fcp.WriteString(fmt.Sprintf("              <title ref=\"r2\" lane=\"1\" offset=\"%s\" duration=\"%s\" name=\"%s\">\n",
+			frameTime(line.start+item.start), frameTime(item.end-item.start), escapeXML(truncateRunes(item.text, 40))))
+		fcp.WriteString("                <text>\n")
+		fcp.WriteString(fmt.Sprintf("                  <text-style ref=\"ts%d\">%s</text-style>\n", i+1, text))
+		fcp.WriteString("                </text>\n")
+		fcp.WriteString(fmt.Sprintf("                <text-style-def id=\"ts%d\">\n", i+1))
+		fcp.WriteString("                  <text-style font=\"Helvetica\" fontSize=\"60\" fontColor=\"1 1 1 1\" alignment=\"center\"/>\n")
+		fcp.WriteString("                </text-style-def>\n")
+		fcp.WriteString("              </title>\n")
+	}
+	for _, item := range line.items {
+		fcp.WriteString(fmt.Sprintf("              <marker start=\"%s\" duration=\"%s\" value=\"%s\"/>\n",
+			frameTime(line.start+item.start), frameTime(1), escapeXML(item.text)))
+	}
+	fcp.WriteString("            </gap>\n")
+	fcp.WriteString("          </spine>\n")
+	fcp.WriteString("        </sequence>\n")
+	fcp.WriteString("      </project>\n")
+	fcp.WriteString("    </event>\n")
+	fcp.WriteString("  </library>\n")
+	fcp.WriteString("</fcpxml>\n")
+	return fcp.String(), nil
+}
+
+// fcpxmlTime FCPXML的有理数时间：NTSC帧率为 帧数*1001/(帧率*1000)s，整数帧率为 帧数/帧率s
+func fcpxmlTime(frames int64, rate float64) string {
+	nominal := int64(nominalFrameRate(rate))
+	if isNTSCFrameRate(rate) {
+		return fmt.Sprintf("%d/%ds", frames*1001, nominal*1000)
+	}
+	return fmt.Sprintf("%d/%ds", frames, nominal)
+}
+
+// exportToXMEML 导出为Premiere Pro XML（xmeml 4）：每个段落一个文字生成器和一个序列标记
+func (s *ExportService) exportToXMEML(result models.RecognitionResult, options models.ExportOptions) (string, error) {
+	line, err := buildTimeline(result, options)
+	if err != nil {
+		return "", err
+	}
+	ntsc := "FALSE"
+	if isNTSCFrameRate(line.rate) {
+		ntsc = "TRUE"
+	}
+	rate := fmt.Sprintf("<rate><timebase>%d</timebase><ntsc>%s</ntsc></rate>", nominalFrameRate(line.rate), ntsc)
+
+	var xmeml strings.Builder
+	xmeml.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	xmeml.WriteString("<!DOCTYPE xmeml>\n")
+	xmeml.WriteString("<xmeml version=\"4\">\n")
+	xmeml.WriteString("  <sequence id=\"sequence-1\">\n")
+	xmeml.WriteString(fmt.Sprintf("    <name>%s</name>\n", escapeXML(line.name)))
+	xmeml.WriteString(fmt.Sprintf("    <duration>%d</duration>\n", line.duration()))
+	xmeml.WriteString("    " + rate + "\n")
+	xmeml.WriteString(fmt.Sprintf("    <timecode>%s<string>%s</string><frame>%d</frame><displayformat>NDF</displayformat></timecode>\n",
+		rate, formatTimecode(line.start, line.rate), line.start))
+	xmeml.WriteString("    <media>\n")
+	xmeml.WriteString("      <video>\n")
+	xmeml.WriteString(fmt.Sprintf("        <format><samplecharacteristics>%s<width>%d</width><height>%d</height></samplecharacteristics></format>\n",
+		rate, line.width, line.height))
+	xmeml.WriteString("        <track>\n")
+	for i, item := range line.items {
+		duration := item.end - item.start
+		xmeml.WriteString(fmt.Sprintf("          <generatoritem id=\"text-%d\">\n", i+1))
+		xmeml.WriteString(fmt.Sprintf("            <name>%s</name>\n", escapeXML(truncateRunes(item.text, 40))))
+		xmeml.WriteString("            <enabled>TRUE</enabled>\n")
+		xmeml.WriteString(fmt.Sprintf("            <duration>%d</duration>\n", duration))
+		xmeml.WriteString("            " + rate + "\n")
+		xmeml.WriteString(fmt.Sprintf("            <start>%d</start>\n", item.start))
+		xmeml.WriteString(fmt.Sprintf("            <end>%d</end>\n", item.end))
+		xmeml.WriteString("            <in>0</in>\n")
+		xmeml.WriteString(fmt.Sprintf("            <out>%d</out>\n", duration))
+		xmeml.WriteString("            <effect>\n")
+		xmeml.WriteString("              <name>Text</name>\n")
+		xmeml.WriteString("              <effectid>Text</effectid>\n")
+		xmeml.WriteString("              <effectcategory>Text</effectcategory>\n")
+		xmeml.WriteString("              <effecttype>generator</effecttype>\n")
+		xmeml.WriteString("              <mediatype>video</mediatype>\n")
+		xmeml.WriteString(fmt.Sprintf("              <parameter><parameterid>str</parameterid><name>Text</name><value>%s</value></parameter>\n", escapeXML(item.text)))
+		xmeml.WriteString("            </effect>\n")
+		xmeml.WriteString("          </generatoritem>\n")
+	}
+	xmeml.WriteString("        </track>\n")
+	xmeml.WriteString("      </video>\n")
+	xmeml.WriteString("    </media>\n")
+	for _, item := range line.items {
+		xmeml.WriteString(fmt.Sprintf("    <marker><name>%s</name><comment>%s</comment><in>%d</in><out>-1</out></marker>\n",
+			escapeXML(truncateRunes(item.text, 40)), escapeXML(item.text), item.start))
+	}
+	xmeml.WriteString("  </sequence>\n")
+	xmeml.WriteString("</xmeml>\n")
+	return xmeml.String(), nil
+}
+
+// truncateRunes 截断过长的名称
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
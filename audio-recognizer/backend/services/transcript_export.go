@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"unicode/utf8"
+
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/utils"
+)
+
+// defaultParagraphPause 文稿中停顿超过该时长(秒)时另起一段
+const defaultParagraphPause = 2.0
+
+// 模板目录中覆盖内置模板的文件名
+const (
+	markdownTemplateFile = "transcript.md.tmpl"
+	htmlTemplateFile     = "transcript.html.tmpl"
+	docxTemplateFile     = "transcript.docx" // 参考文档，使用其中的样式
+)
+
+//go:embed templates/transcript.md.tmpl templates/transcript.html.tmpl
+var builtinTemplates embed.FS
+
+// TranscriptDocument 文稿模板的数据
+type TranscriptDocument struct {
+	Title       string              // 标题（音频标题或文件名）
+	FileName    string              // 源文件名
+	Duration    string              // 时长 HH:MM:SS
+	Model       string              // 识别模型
+	Language    string              // 识别语言
+	ProcessedAt string              // 处理时间
+	Sections    []TranscriptSection // 按说话人划分的部分（未分离说话人时只有一部分）
+}
+
+// TranscriptSection 同一说话人连续发言的部分
+type TranscriptSection struct {
+	Speaker    string // 说话人，未分离说话人时为空
+	Start      string // 开始时间 HH:MM:SS
+	Paragraphs []TranscriptParagraph
+}
+
+// TranscriptParagraph 按停顿划分的段落
+type TranscriptParagraph struct {
+	Start   string // 开始时间 HH:MM:SS
+	Seconds int    // 开始时间(秒)，可用于锚点
+	Text    string
+}
+
+// buildTranscriptDocument 生成文稿数据：说话人变化时开始新的部分，停顿超过阈值时另起一段
+func buildTranscriptDocument(result models.RecognitionResult, options models.ExportOptions) TranscriptDocument {
+	pause := options.ParagraphPause
+	if pause <= 0 {
+		pause = defaultParagraphPause
+	}
+
+	document := TranscriptDocument{
+		Title:       resultTitle(result),
+		Duration:    transcriptTime(result.Duration),
+		Language:    result.Language,
+		ProcessedAt: result.ProcessedAt.Local().Format("2006-01-02 15:04"),
+	}
+	if name, ok := result.Metadata["audio_file"].(string); ok {
+		document.FileName = name
+	}
+	if model, ok := result.Metadata["model_file"].(string); ok && model != "" {
+		document.Model = filepath.Base(model)
+	} else if engine, ok := result.Metadata["engine"].(string); ok {
+		document.Model = engine
+	}
+
+	lastEnd := 0.0
+	for _, segment := range result.Segments {
+		text := strings.Join(strings.Fields(segment.Text), " ")
+		if text == "" {
+			continue
+		}
+
+		sections := &document.Sections
+		if n := len(*sections); n == 0 || (*sections)[n-1].Speaker != segment.Speaker {
+			*sections = append(*sections, TranscriptSection{Speaker: segment.Speaker, Start: transcriptTime(segment.Start)})
+		}
+		section := &(*sections)[len(*sections)-1]
+
+		paragraphs := section.Paragraphs
+		if n := len(paragraphs); n > 0 && segment.Start-lastEnd < pause {
+			paragraphs[n-1].Text = joinTranscriptText(paragraphs[n-1].Text, text)
+		} else {
+			section.Paragraphs = append(section.Paragraphs, TranscriptParagraph{
+				Start:   transcriptTime(segment.Start),
+				Seconds: int(math.Max(segment.Start, 0)),
+				Text:    text,
+			})
+		}
+		lastEnd = segment.End
+	}
+	return document
+}
+
+// transcriptTime 文稿中的时间 HH:MM:SS
+func transcriptTime(seconds float64) string {
+	total := int(math.Max(seconds, 0))
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, (total%3600)/60, total%60)
+}
+
+// joinTranscriptText 连接同一段落的文本，中日韩文字之间不加空格
+func joinTranscriptText(previous, text string) string {
+	last, _ := utf8.DecodeLastRuneInString(previous)
+	first, _ := utf8.DecodeRuneInString(text)
+	if isCJKRune(last) || isCJKRune(first) {
+		return previous + text
+	}
+	return previous + " " + text
+}
+
+// exportToMarkdown 按模板导出为Markdown文稿
+func (s *ExportService) exportToMarkdown(result models.RecognitionResult, options models.ExportOptions) (string, error) {
+	source, err := s.loadTemplate(options, markdownTemplateFile)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := texttemplate.New(markdownTemplateFile).Funcs(texttemplate.FuncMap{"markdown": escapeMarkdown}).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("Markdown模板格式错误: %w", err)
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, buildTranscriptDocument(result, options)); err != nil {
+		return "", fmt.Errorf("生成Markdown文稿失败: %w", err)
+	}
+	return output.String(), nil
+}
+
+// exportToHTML 按模板导出为单文件HTML文稿（文本自动转义）
+func (s *ExportService) exportToHTML(result models.RecognitionResult, options models.ExportOptions) (string, error) {
+	source, err := s.loadTemplate(options, htmlTemplateFile)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := htmltemplate.New(htmlTemplateFile).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("HTML模板格式错误: %w", err)
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, buildTranscriptDocument(result, options)); err != nil {
+		return "", fmt.Errorf("生成HTML文稿失败: %w", err)
+	}
+	return output.String(), nil
+}
+
+// loadTemplate 读取文稿模板：导出选项指定的文件 > 模板目录中的同名文件 > 内置模板
+func (s *ExportService) loadTemplate(options models.ExportOptions, name string) (string, error) {
+	if options.TemplatePath != "" {
+		data, err := os.ReadFile(options.TemplatePath)
+		if err != nil {
+			return "", fmt.Errorf("读取模板失败: %w", err)
+		}
+		return string(data), nil
+	}
+	if s.templateDir != "" {
+		if data, err := os.ReadFile(filepath.Join(s.templateDir, name)); err == nil {
+			fmt.Printf("📄 使用自定义文稿模板: %s\n", name)
+			return string(data), nil
+		}
+	}
+	data, err := builtinTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("读取内置模板失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// TemplateDirectory 自定义文稿模板的目录
+func (s *ExportService) TemplateDirectory() string {
+	return s.templateDir
+}
+
+// InstallTemplates 将内置的Markdown和HTML模板复制到模板目录供修改，已存在的文件不覆盖，返回新复制的文件
+func (s *ExportService) InstallTemplates() ([]string, error) {
+	if s.templateDir == "" {
+		return nil, fmt.Errorf("未设置模板目录")
+	}
+	if err := os.MkdirAll(s.templateDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建模板目录失败: %w", err)
+	}
+
+	installed := []string{}
+	for _, name := range []string{markdownTemplateFile, htmlTemplateFile} {
+		target := filepath.Join(s.templateDir, name)
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		data, err := builtinTemplates.ReadFile("templates/" + name)
+		if err != nil {
+			return installed, fmt.Errorf("读取内置模板失败: %w", err)
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return installed, fmt.Errorf("写入模板失败: %w", err)
+		}
+		installed = append(installed, target)
+	}
+	utils.LogInfo("文稿模板目录: %s，新复制 %d 个模板", s.templateDir, len(installed))
+	return installed, nil
+}
+
+// escapeMarkdown 转义Markdown中有特殊含义的字符
+func escapeMarkdown(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune("\\`*_{}[]<>#|~", r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tingshengbianzi/backend/utils"
+)
+
+const (
+	// UploadChunkSize 建议的分块大小，Base64编码后约5.3MB，不会阻塞WebView的IPC通道
+	UploadChunkSize = 4 * 1024 * 1024
+	// uploadIdleTimeout 超过该时间没有写入的未完成上传会被清理
+	uploadIdleTimeout = time.Hour
+)
+
+// UploadedFile 上传完成的文件
+type UploadedFile struct {
+	Path      string `json:"path"`      // 临时文件路径，可直接作为识别请求的filePath
+	Name      string `json:"name"`      // 原始文件名
+	Size      int64  `json:"size"`      // 文件大小(字节)
+	Extension string `json:"extension"` // 按文件头识别的扩展名
+	IsVideo   bool   `json:"isVideo"`   // 是否为视频文件
+	SHA256    string `json:"sha256"`    // 文件的SHA-256校验值
+}
+
+// upload 进行中的上传
+type upload struct {
+	name       string
+	size       int64
+	received   int64
+	file       *os.File
+	hash       hash.Hash
+	lastActive time.Time
+}
+
+// UploadService 分块上传服务，前端无法提供文件路径时（如浏览器拖放）分块写入临时文件，避免整个文件以Base64经过WebView
+type UploadService struct {
+	mu        sync.Mutex
+	dir       string
+	uploads   map[string]*upload
+	completed map[string]string // 上传ID -> 已完成的文件路径
+}
+
+// NewUploadService 创建分块上传服务，dir为临时文件目录
+func NewUploadService(dir string) *UploadService {
+	return &UploadService{
+		dir:       dir,
+		uploads:   make(map[string]*upload),
+		completed: make(map[string]string),
+	}
+}
+
+// Begin 开始上传，检查文件大小上限和磁盘空间，返回上传ID
+func (s *UploadService) Begin(name string, size int64) (string, error) {
+	if size <= 0 {
+		return "", fmt.Errorf("文件大小无效: %d", size)
+	}
+	if limit := utils.GetMaxFileSize(); size > limit {
+		return "", fmt.Errorf("文件过大，最大支持 %s", utils.FormatFileSize(limit))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeIdleLocked()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("创建上传目录失败: %w", err)
+	}
+	if err := utils.EnsureDiskSpace(s.dir, size); err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(s.dir, "upload-*.part")
+	if err != nil {
+		return "", fmt.Errorf("创建临时文件失败: %w", err)
+	}
+
+	id := fmt.Sprintf("upload_%d", time.Now().UnixNano())
+	for s.uploads[id] != nil {
+		id += "_1"
+	}
+	s.uploads[id] = &upload{
+		name:       filepath.Base(name),
+		size:       size,
+		file:       file,
+		hash:       sha256.New(),
+		lastActive: time.Now(),
+	}
+	fmt.Printf("📥 开始分块上传: %s (%s), ID=%s\n", name, utils.FormatFileSize(size), id)
+	return id, nil
+}
+
+// Append 写入一个分块，offset必须等于已接收的字节数；checksum为分块的SHA-256（十六进制，可选）
+// 重发已写入的分块（网络重试）时直接返回已接收的字节数
+func (s *UploadService) Append(id string, offset int64, chunkBase64, checksum string) (int64, error) {
+	chunk, err := base64.StdEncoding.DecodeString(chunkBase64)
+	if err != nil {
+		return 0, fmt.Errorf("分块Base64解码失败: %w", err)
+	}
+	if checksum != "" {
+		sum := sha256.Sum256(chunk)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), checksum) {
+			return 0, fmt.Errorf("分块校验失败: 偏移 %d", offset)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.uploads[id]
+	if !ok {
+		return 0, fmt.Errorf("上传不存在或已过期: %s", id)
+	}
+	current.lastActive = time.Now()
+
+	if offset < current.received && offset+int64(len(chunk)) <= current.received {
+		return current.received, nil
+	}
+	if offset != current.received {
+		return current.received, fmt.Errorf("分块偏移不连续: 期望 %d，实际 %d", current.received, offset)
+	}
+	if current.received+int64(len(chunk)) > current.size {
+		return current.received, fmt.Errorf("上传数据超过声明的文件大小 %d", current.size)
+	}
+
+	if _, err := current.file.Write(chunk); err != nil {
+		return current.received, fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	current.hash.Write(chunk)
+	current.received += int64(len(chunk))
+	return current.received, nil
+}
+
+// Commit 完成上传：校验大小和整个文件的SHA-256（可选），按文件头识别格式并改为对应扩展名
+func (s *UploadService) Commit(id, checksum string) (*UploadedFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.uploads[id]
+	if !ok {
+		return nil, fmt.Errorf("上传不存在或已过期: %s", id)
+	}
+	delete(s.uploads, id)
+	partPath := current.file.Name()
+	fail := func(err error) (*UploadedFile, error) {
+		os.Remove(partPath)
+		return nil, err
+	}
+
+	if err := current.file.Close(); err != nil {
+		return fail(fmt.Errorf("关闭临时文件失败: %w", err))
+	}
+	if current.received != current.size {
+		return fail(fmt.Errorf("上传不完整: 已接收 %d / %d 字节", current.received, current.size))
+	}
+	sum := hex.EncodeToString(current.hash.Sum(nil))
+	if checksum != "" && !strings.EqualFold(sum, checksum) {
+		return fail(fmt.Errorf("文件校验失败: SHA-256不一致"))
+	}
+
+	// 以文件头为准，浏览器提供的文件名和类型可能不可靠
+	ext, err := utils.SniffMediaFile(partPath)
+	if err != nil {
+		return fail(fmt.Errorf("%v，请选择音频或视频文件", err))
+	}
+	finalPath := strings.TrimSuffix(partPath, ".part") + ext
+	if err := os.Rename(partPath, finalPath); err != nil {
+		return fail(fmt.Errorf("重命名临时文件失败: %w", err))
+	}
+
+	validation := utils.ValidateAudioFile(finalPath)
+	if !validation.IsValid {
+		os.Remove(finalPath)
+		return nil, fmt.Errorf("%s", validation.ErrorMsg)
+	}
+
+	s.completed[id] = finalPath
+	fmt.Printf("✅ 分块上传完成: %s -> %s\n", current.name, finalPath)
+	return &UploadedFile{
+		Path:      finalPath,
+		Name:      current.name,
+		Size:      current.size,
+		Extension: ext,
+		IsVideo:   validation.IsVideo,
+		SHA256:    sum,
+	}, nil
+}
+
+// Cancel 取消上传或删除已上传的文件
+func (s *UploadService) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.uploads[id]; ok {
+		delete(s.uploads, id)
+		current.file.Close()
+		return os.Remove(current.file.Name())
+	}
+	if path, ok := s.completed[id]; ok {
+		delete(s.completed, id)
+		return os.Remove(path)
+	}
+	return fmt.Errorf("上传不存在: %s", id)
+}
+
+// Cleanup 删除全部上传的临时文件（应用退出时调用）
+func (s *UploadService) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, current := range s.uploads {
+		current.file.Close()
+		os.Remove(current.file.Name())
+		delete(s.uploads, id)
+	}
+	for id, path := range s.completed {
+		os.Remove(path)
+		delete(s.completed, id)
+	}
+}
+
+// purgeIdleLocked 清理长时间没有写入的未完成上传（调用方需持有锁）
+func (s *UploadService) purgeIdleLocked() {
+	for id, current := range s.uploads {
+		if time.Since(current.lastActive) < uploadIdleTimeout {
+			continue
+		}
+		current.file.Close()
+		os.Remove(current.file.Name())
+		delete(s.uploads, id)
+		fmt.Printf("🧹 清理过期的上传: %s\n", id)
+	}
+}
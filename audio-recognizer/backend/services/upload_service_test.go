@@ -0,0 +1,237 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tingshengbianzi/backend/utils"
+)
+
+// testUploadWAV 生成一个16kHz单声道的静音WAV文件内容
+func testUploadWAV(samples int) []byte {
+	var buf bytes.Buffer
+	dataSize := uint32(samples * 2)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, []interface{}{
+		uint32(16), uint16(1), uint16(1), uint32(16000), uint32(32000), uint16(2), uint16(16),
+	})
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+// sha256Hex 计算数据的SHA-256（十六进制）
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// appendChunk 上传data[offset:end]作为一个分块
+func appendChunk(s *UploadService, id string, data []byte, offset, end int, checksum string) (int64, error) {
+	return s.Append(id, int64(offset), base64.StdEncoding.EncodeToString(data[offset:end]), checksum)
+}
+
+// uploadDirFiles 上传目录中的文件名
+func uploadDirFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+// TestUploadServiceChunkedUpload 分块上传WAV文件：重发的分块被忽略，提交后按文件头改为.wav扩展名
+func TestUploadServiceChunkedUpload(t *testing.T) {
+	dir := t.TempDir()
+	service := NewUploadService(dir)
+	data := testUploadWAV(1000)
+
+	// 浏览器提供的文件名扩展名不可靠
+	id, err := service.Begin("录音.bin", int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := uploadDirFiles(t, dir); len(files) != 1 || !strings.HasSuffix(files[0], ".part") {
+		t.Fatalf("上传中的临时文件为 %v，期望一个.part文件", files)
+	}
+
+	bounds := []int{0, 500, 1200, len(data)}
+	for i := 0; i+1 < len(bounds); i++ {
+		received, err := appendChunk(service, id, data, bounds[i], bounds[i+1], sha256Hex(data[bounds[i]:bounds[i+1]]))
+		if err != nil {
+			t.Fatalf("第 %d 个分块上传失败: %v", i+1, err)
+		}
+		if received != int64(bounds[i+1]) {
+			t.Fatalf("第 %d 个分块后已接收 %d 字节，期望 %d", i+1, received, bounds[i+1])
+		}
+		if i == 1 {
+			// 网络重试时重发已写入的分块
+			received, err := appendChunk(service, id, data, bounds[0], bounds[1], "")
+			if err != nil || received != int64(bounds[2]) {
+				t.Fatalf("重发分块返回 %d, %v，期望 %d", received, err, bounds[2])
+			}
+		}
+	}
+
+	uploaded, err := service.Commit(id, strings.ToUpper(sha256Hex(data)))
+	if err != nil {
+		t.Fatalf("提交上传失败: %v", err)
+	}
+	if uploaded.Extension != ".wav" || filepath.Ext(uploaded.Path) != ".wav" {
+		t.Errorf("上传后的扩展名为 %s (%s)，期望 .wav", uploaded.Extension, uploaded.Path)
+	}
+	if uploaded.Name != "录音.bin" || uploaded.Size != int64(len(data)) || uploaded.IsVideo || uploaded.SHA256 != sha256Hex(data) {
+		t.Errorf("上传结果为 %+v", uploaded)
+	}
+	content, err := os.ReadFile(uploaded.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, data) {
+		t.Error("上传后的文件内容与原文件不一致")
+	}
+	if files := uploadDirFiles(t, dir); len(files) != 1 || files[0] != filepath.Base(uploaded.Path) {
+		t.Errorf("提交后目录中的文件为 %v，.part文件应已改名", files)
+	}
+
+	if err := service.Cancel(id); err != nil {
+		t.Fatalf("删除已上传的文件失败: %v", err)
+	}
+	if files := uploadDirFiles(t, dir); len(files) != 0 {
+		t.Errorf("删除后目录中仍有文件 %v", files)
+	}
+}
+
+// TestUploadServiceRejections 分块或整个文件校验失败、偏移不连续、数据超过声明大小和无法识别的格式
+func TestUploadServiceRejections(t *testing.T) {
+	data := testUploadWAV(1000)
+	tests := []struct {
+		name    string
+		content []byte
+		size    int
+		run     func(s *UploadService, id string, data []byte) error
+		wantErr string
+		kept    bool // 上传被拒绝后是否保留.part文件以便继续
+	}{
+		{"分块校验失败", data, len(data), func(s *UploadService, id string, data []byte) error {
+			_, err := appendChunk(s, id, data, 0, 100, sha256Hex(data[:99]))
+			return err
+		}, "分块校验失败", true},
+		{"偏移不连续", data, len(data), func(s *UploadService, id string, data []byte) error {
+			appendChunk(s, id, data, 0, 100, "")
+			received, err := appendChunk(s, id, data, 200, 300, "")
+			if received != 100 {
+				return fmt.Errorf("拒绝后已接收 %d 字节，期望 100", received)
+			}
+			return err
+		}, "分块偏移不连续", true},
+		{"与已写入部分重叠的分块", data, len(data), func(s *UploadService, id string, data []byte) error {
+			appendChunk(s, id, data, 0, 100, "")
+			_, err := appendChunk(s, id, data, 50, 150, "")
+			return err
+		}, "分块偏移不连续", true},
+		{"超过声明的文件大小", data, len(data) - 1, func(s *UploadService, id string, data []byte) error {
+			_, err := appendChunk(s, id, data, 0, len(data), "")
+			return err
+		}, "超过声明的文件大小", true},
+		{"上传不完整", data, len(data), func(s *UploadService, id string, data []byte) error {
+			appendChunk(s, id, data, 0, 100, "")
+			_, err := s.Commit(id, "")
+			return err
+		}, "上传不完整", false},
+		{"文件SHA-256不一致", data, len(data), func(s *UploadService, id string, data []byte) error {
+			appendChunk(s, id, data, 0, len(data), "")
+			_, err := s.Commit(id, sha256Hex(data[1:]))
+			return err
+		}, "文件校验失败", false},
+		{"无法识别的格式", []byte("这不是音频文件，只是一段文字。"), len("这不是音频文件，只是一段文字。"), func(s *UploadService, id string, data []byte) error {
+			appendChunk(s, id, data, 0, len(data), "")
+			_, err := s.Commit(id, "")
+			return err
+		}, "无法识别的文件格式", false},
+		{"上传不存在", data, len(data), func(s *UploadService, id string, data []byte) error {
+			_, err := s.Commit(id+"_missing", "")
+			return err
+		}, "上传不存在", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			service := NewUploadService(dir)
+			id, err := service.Begin("audio.wav", int64(tt.size))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tt.run(service, id, tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("错误为 %v，期望包含 %q", err, tt.wantErr)
+			}
+
+			// 分块被拒绝后上传仍可继续，提交失败后临时文件被删除
+			files := uploadDirFiles(t, dir)
+			if tt.kept && (len(files) != 1 || !strings.HasSuffix(files[0], ".part")) {
+				t.Errorf("拒绝后目录中的文件为 %v，期望保留.part文件", files)
+			}
+			if !tt.kept && len(files) != 0 {
+				t.Errorf("提交失败后目录中残留文件 %v", files)
+			}
+			service.Cleanup()
+		})
+	}
+}
+
+// TestUploadServiceRetryRejectedChunk 校验失败的分块不写入，重新上传后可以继续
+func TestUploadServiceRetryRejectedChunk(t *testing.T) {
+	service := NewUploadService(t.TempDir())
+	data := testUploadWAV(1000)
+	id, err := service.Begin("audio.wav", int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Cleanup()
+
+	if _, err := appendChunk(service, id, data, 0, len(data), "0000"); err == nil {
+		t.Fatal("校验值错误的分块应被拒绝")
+	}
+	received, err := appendChunk(service, id, data, 0, len(data), sha256Hex(data))
+	if err != nil || received != int64(len(data)) {
+		t.Fatalf("重新上传返回 %d, %v", received, err)
+	}
+	if _, err := service.Commit(id, sha256Hex(data)); err != nil {
+		t.Fatalf("提交上传失败: %v", err)
+	}
+}
+
+// TestUploadServiceBeginLimits 文件大小无效或超过上限时拒绝上传
+func TestUploadServiceBeginLimits(t *testing.T) {
+	service := NewUploadService(t.TempDir())
+	utils.SetMaxFileSize(1024)
+	defer utils.SetMaxFileSize(0)
+
+	for _, size := range []int64{0, -1, 1025} {
+		if _, err := service.Begin("audio.wav", size); err == nil {
+			t.Errorf("大小为 %d 的上传应被拒绝", size)
+		}
+	}
+	if _, err := service.Begin("audio.wav", 1024); err != nil {
+		t.Errorf("大小为上限的上传应被接受: %v", err)
+	}
+	service.Cleanup()
+}
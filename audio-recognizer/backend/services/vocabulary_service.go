@@ -0,0 +1,190 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tingshengbianzi/backend/models"
+)
+
+// VocabularySummary 词汇表概要
+type VocabularySummary struct {
+	Name      string    `json:"name"`
+	TermCount int       `json:"termCount"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// VocabularyService 项目词汇表管理，每个词汇表保存为目录下的一个JSON文件
+type VocabularyService struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewVocabularyService 创建词汇表服务
+func NewVocabularyService(dir string) *VocabularyService {
+	return &VocabularyService{dir: dir}
+}
+
+// List 列出所有词汇表，按名称排序
+func (s *VocabularyService) List() ([]VocabularySummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []VocabularySummary{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取词汇表目录失败: %w", err)
+	}
+
+	summaries := []VocabularySummary{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		vocabulary, err := s.readLocked(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			fmt.Printf("⚠️ 跳过无效的词汇表文件 %s: %v\n", entry.Name(), err)
+			continue
+		}
+		summaries = append(summaries, VocabularySummary{
+			Name:      vocabulary.Name,
+			TermCount: len(vocabulary.Terms),
+			UpdatedAt: vocabulary.UpdatedAt,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+// Get 读取词汇表
+func (s *VocabularyService) Get(name string) (*models.Vocabulary, error) {
+	path, err := s.vocabularyPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked(path)
+}
+
+// Save 校验并保存词汇表，同名词汇表会被覆盖
+func (s *VocabularyService) Save(vocabulary *models.Vocabulary) error {
+	vocabulary.Name = strings.TrimSpace(vocabulary.Name)
+	path, err := s.vocabularyPath(vocabulary.Name)
+	if err != nil {
+		return err
+	}
+	vocabulary.Terms = normalizeVocabularyTerms(vocabulary.Terms)
+	vocabulary.UpdatedAt = time.Now()
+
+	content, err := json.MarshalIndent(vocabulary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化词汇表失败: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("创建词汇表目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("写入词汇表失败: %w", err)
+	}
+	fmt.Printf("✅ 词汇表已保存: %s (%d 个术语)\n", vocabulary.Name, len(vocabulary.Terms))
+	return nil
+}
+
+// Delete 删除词汇表
+func (s *VocabularyService) Delete(name string) error {
+	path, err := s.vocabularyPath(name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("词汇表不存在: %s", name)
+		}
+		return fmt.Errorf("删除词汇表失败: %w", err)
+	}
+	return nil
+}
+
+// readLocked 读取词汇表文件（调用方需持有锁）
+func (s *VocabularyService) readLocked(path string) (*models.Vocabulary, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("词汇表不存在: %s", strings.TrimSuffix(filepath.Base(path), ".json"))
+		}
+		return nil, fmt.Errorf("读取词汇表失败: %w", err)
+	}
+	var vocabulary models.Vocabulary
+	if err := json.Unmarshal(content, &vocabulary); err != nil {
+		return nil, fmt.Errorf("解析词汇表失败: %w", err)
+	}
+	if vocabulary.Name == "" {
+		vocabulary.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	return &vocabulary, nil
+}
+
+// vocabularyPath 获取词汇表文件路径，名称不能为空或包含路径字符
+func (s *VocabularyService) vocabularyPath(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("词汇表名称不能为空")
+	}
+	if strings.ContainsAny(name, `/\:*?"<>|`) || name == "." || name == ".." {
+		return "", fmt.Errorf("词汇表名称包含非法字符: %s", name)
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
+
+// normalizeVocabularyTerms 去掉空白和空术语，合并重复的术语
+func normalizeVocabularyTerms(terms []models.VocabularyTerm) []models.VocabularyTerm {
+	normalized := make([]models.VocabularyTerm, 0, len(terms))
+	index := make(map[string]int)
+	for _, term := range terms {
+		term.Term = strings.TrimSpace(term.Term)
+		term.Spelling = strings.TrimSpace(term.Spelling)
+		term.Pronunciation = strings.TrimSpace(term.Pronunciation)
+		if term.Term == "" && term.Spelling == "" {
+			continue
+		}
+		if term.Term == "" {
+			term.Term = term.Spelling
+		}
+
+		var aliases []string
+		for _, alias := range term.Aliases {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				aliases = append(aliases, alias)
+			}
+		}
+		term.Aliases = aliases
+
+		key := strings.ToLower(term.Output())
+		if existing, ok := index[key]; ok {
+			normalized[existing].Aliases = append(normalized[existing].Aliases, term.Aliases...)
+			if normalized[existing].Pronunciation == "" {
+				normalized[existing].Pronunciation = term.Pronunciation
+			}
+			continue
+		}
+		index[key] = len(normalized)
+		normalized = append(normalized, term)
+	}
+	return normalized
+}
@@ -3,10 +3,10 @@ package utils
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
+
+	"tingshengbianzi/backend/models"
 )
 
 // AudioFileInfo 音频文件信息
@@ -17,6 +17,17 @@ type AudioFileInfo struct {
 	Type         string  `json:"type"`
 	Duration     float64 `json:"duration"`
 	LastModified int64   `json:"lastModified"`
+	Codec        string            `json:"codec,omitempty"`
+	Container    string            `json:"container,omitempty"`
+	SampleRate   int               `json:"sampleRate,omitempty"`
+	Channels     int               `json:"channels,omitempty"`
+	BitRate      int               `json:"bitRate,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	StreamCount  int               `json:"streamCount,omitempty"`
+	IsVideo      bool              `json:"isVideo"`
+	AudioStreams []models.AudioStreamInfo `json:"audioStreams,omitempty"`
+	AudioStream  int                      `json:"audioStream"`
+	Video        *models.VideoInfo        `json:"video,omitempty"`
 }
 
 // AudioFileHandler 音频文件处理器
@@ -45,18 +56,35 @@ func (h *AudioFileHandler) GetAudioFileInfo(filePath string) (*AudioFileInfo, er
 	fileInfo := validationResult.FileInfo
 	ext := validationResult.Extension
 
-	// 使用文件大小估算音频时长
-	duration := EstimateDurationFromSize(fileInfo.Size(), ext)
-	fmt.Printf("使用估算时长: %.2f 秒\n", duration)
-
-	return &AudioFileInfo{
+	info := &AudioFileInfo{
 		Name:         filepath.Base(filePath),
 		Path:         filePath,
 		Size:         fileInfo.Size(),
 		Type:         GetMimeTypeFromExtension(ext),
-		Duration:     duration,
 		LastModified: fileInfo.ModTime().UnixMilli(),
-	}, nil
+		IsVideo:      validationResult.IsVideo,
+	}
+
+	// 优先使用FFprobe获取完整信息，失败时使用文件大小估算音频时长
+	probed, err := ProbeAudioFileWithAvailableFFprobe(filePath)
+	if err == nil && probed.Duration > 0 {
+		info.Duration = probed.Duration
+		info.Codec = probed.Codec
+		info.Container = probed.Container
+		info.SampleRate = probed.SampleRate
+		info.Channels = probed.Channels
+		info.BitRate = probed.BitRate
+		info.Tags = probed.Tags
+		info.StreamCount = probed.StreamCount
+		info.AudioStreams = probed.AudioStreams
+		info.AudioStream = probed.AudioStream
+		info.Video = probed.Video
+	} else {
+		info.Duration = EstimateDurationFromSize(fileInfo.Size(), ext)
+		fmt.Printf("使用估算时长: %.2f 秒\n", info.Duration)
+	}
+
+	return info, nil
 }
 
 // GetAudioDuration 仅获取音频时长（增强版）
@@ -85,6 +113,8 @@ func EstimateDurationFromSize(fileSize int64, ext string) float64 {
 		bitRate = 1000 // 无损压缩
 	case ".ogg":
 		bitRate = 160
+	case ".mp4", ".m4v", ".mkv", ".mov", ".webm", ".avi":
+		bitRate = 2000 // 视频文件的大小主要由视频流决定，按常见的2Mbps估算
 	default:
 		bitRate = 128 // 默认
 	}
@@ -105,82 +135,39 @@ func EstimateDurationFromSize(fileSize int64, ext string) float64 {
 
 // SelectAudioFile 选择音频文件的通用对话框选项
 func GetAudioFileDialogOptions() map[string]interface{} {
+	allExtensions := append(append([]string{}, AudioFileExtensions...), VideoFileExtensions...)
 	return map[string]interface{}{
-		"title":             "选择音频文件",
+		"title":             "选择音频或视频文件",
 		"defaultDirectory":  "",
 		"defaultFilename":   "",
 		"filters": []map[string]interface{}{
-			{
-				"displayName": "音频文件 (*.mp3, *.wav, *.m4a, *.ogg, *.flac)",
-				"pattern":     "*.mp3;*.wav;*.m4a;*.ogg;*.flac",
-			},
+			dialogFilter("音频和视频文件", allExtensions),
+			dialogFilter("音频文件", AudioFileExtensions),
+			dialogFilter("视频文件", VideoFileExtensions),
 		},
 	}
 }
 
-// GetAudioDurationWithFFmpeg 使用FFprobe获取精确音频时长
-func GetAudioDurationWithFFmpeg(filePath string) (float64, error) {
-	// 尝试多种可能的FFprobe路径
-	ffprobePaths := []string{
-		"ffprobe", // 系统PATH中查找
-		"/opt/homebrew/bin/ffprobe",
-		"/usr/local/bin/ffprobe",
-		"/usr/bin/ffprobe",
+// dialogFilter 生成文件对话框的过滤器，如 "音频文件 (*.mp3, *.wav)" 和 "*.mp3;*.wav"
+func dialogFilter(name string, extensions []string) map[string]interface{} {
+	patterns := make([]string, len(extensions))
+	for i, ext := range extensions {
+		patterns[i] = "*" + ext
 	}
-
-	// 如果有FFmpegManager，尝试使用其管理的FFprobe
-	if ffmpegManager, err := NewFFmpegManager(); err == nil {
-		if err := ffmpegManager.EnsureFFmpegAvailable(); err == nil {
-			ffprobePaths = append([]string{ffmpegManager.GetFFprobePath()}, ffprobePaths...)
-		}
-	}
-
-	var lastError error
-	for _, ffprobePath := range ffprobePaths {
-		duration, err := getDurationWithFFprobePath(ffprobePath, filePath)
-		if err == nil {
-			fmt.Printf("✅ 使用FFprobe获取精确时长: %.2f秒 (FFmpeg路径: %s)\n", duration, ffprobePath)
-			return duration, nil
-		}
-		lastError = err
-		fmt.Printf("⚠️ FFprobe路径 %s 不可用: %v\n", ffprobePath, err)
+	return map[string]interface{}{
+		"displayName": fmt.Sprintf("%s (%s)", name, strings.Join(patterns, ", ")),
+		"pattern":     strings.Join(patterns, ";"),
 	}
-
-	// 如果FFprobe完全不可用，返回错误
-	return 0, fmt.Errorf("所有FFprobe路径都不可用，最后错误: %v", lastError)
 }
 
-// getDurationWithFFprobePath 使用指定路径的FFprobe获取时长
-func getDurationWithFFprobePath(ffprobePath, filePath string) (float64, error) {
-	// 检查FFprobe是否存在
-	if _, err := exec.LookPath(ffprobePath); err != nil {
-		return 0, fmt.Errorf("FFprobe不存在: %v", err)
-	}
-
-	// 使用FFprobe获取音频信息
-	cmd := exec.Command(ffprobePath,
-		"-v", "quiet",
-		"-show_entries", "format=duration",
-		"-of", "default=noprint_wrappers=1:nokey=1",
-		filePath,
-	)
-
-	output, err := cmd.Output()
+// GetAudioDurationWithFFmpeg 使用FFprobe获取精确音频时长
+func GetAudioDurationWithFFmpeg(filePath string) (float64, error) {
+	audioInfo, err := ProbeAudioFileWithAvailableFFprobe(filePath)
 	if err != nil {
-		return 0, fmt.Errorf("执行FFprobe失败: %v", err)
+		return 0, err
 	}
 
-	// 解析输出
-	durationStr := strings.TrimSpace(string(output))
-	if durationStr == "" {
-		return 0, fmt.Errorf("FFprobe返回空结果")
-	}
-
-	// 转换为浮点数
-	duration, err := strconv.ParseFloat(durationStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("解析时长失败: %v, 原始数据: %s", err, durationStr)
-	}
+	duration := audioInfo.Duration
 
 	// 验证时长的合理性
 	if duration <= 0 {
@@ -191,6 +178,7 @@ func getDurationWithFFprobePath(ffprobePath, filePath string) (float64, error) {
 		return 0, fmt.Errorf("获取的时长异常过大: %f", duration)
 	}
 
+	fmt.Printf("✅ 使用FFprobe获取精确时长: %.2f秒\n", duration)
 	return duration, nil
 }
 
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"fmt"
+
+	"tingshengbianzi/backend/models"
+)
+
+// diskSpaceReserve 写入文件后至少保留的磁盘空间，避免占满系统盘
+const diskSpaceReserve = 256 * 1024 * 1024 // 256MB
+
+// EnsureDiskSpace 检查目录所在磁盘是否能再写入required字节，空间不足时返回包装了models.ErrDiskSpaceFull的错误
+// 无法获取磁盘空间时（如不支持的文件系统）不阻止写入
+func EnsureDiskSpace(dir string, required int64) error {
+	available, err := AvailableDiskSpace(dir)
+	if err != nil {
+		fmt.Printf("⚠️ 获取磁盘可用空间失败，跳过检查: %v\n", err)
+		return nil
+	}
+	if required < 0 {
+		required = 0
+	}
+	if uint64(required)+diskSpaceReserve > available {
+		return fmt.Errorf("%w: %s 需要 %s，可用 %s", models.ErrDiskSpaceFull, dir,
+			FormatFileSize(required), FormatFileSize(int64(available)))
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package utils
+
+import "syscall"
+
+// AvailableDiskSpace 获取目录所在磁盘的可用空间（字节）
+func AvailableDiskSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,327 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"tingshengbianzi/backend/models"
+)
+
+// FFprobeOutput ffprobe -show_format -show_streams -print_format json 的输出
+type FFprobeOutput struct {
+	Format  FFprobeFormat   `json:"format"`
+	Streams []FFprobeStream `json:"streams"`
+}
+
+// FFprobeFormat 容器信息
+type FFprobeFormat struct {
+	Filename       string            `json:"filename"`
+	NbStreams      int               `json:"nb_streams"`
+	FormatName     string            `json:"format_name"`
+	FormatLongName string            `json:"format_long_name"`
+	Duration       string            `json:"duration"`
+	Size           string            `json:"size"`
+	BitRate        string            `json:"bit_rate"`
+	Tags           map[string]string `json:"tags"`
+}
+
+// FFprobeStream 流信息
+type FFprobeStream struct {
+	Index         int               `json:"index"`
+	CodecName     string            `json:"codec_name"`
+	CodecLongName string            `json:"codec_long_name"`
+	CodecType     string            `json:"codec_type"`
+	SampleRate    string            `json:"sample_rate"`
+	Channels      int               `json:"channels"`
+	ChannelLayout string            `json:"channel_layout"`
+	Duration      string            `json:"duration"`
+	BitRate       string            `json:"bit_rate"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	RFrameRate    string            `json:"r_frame_rate"`
+	AvgFrameRate  string            `json:"avg_frame_rate"`
+	NbFrames      string            `json:"nb_frames"`
+	Disposition   map[string]int    `json:"disposition"`
+	Tags          map[string]string `json:"tags"`
+}
+
+// probeCacheKey 探测缓存键，文件修改后自动失效
+type probeCacheKey struct {
+	path    string
+	modTime int64
+	size    int64
+}
+
+// probeCacheLimit 探测缓存的最大条目数
+const probeCacheLimit = 256
+
+var (
+	probeCacheMu sync.Mutex
+	probeCache   = make(map[probeCacheKey]*models.AudioFile)
+)
+
+// ProbeAudioFile 使用指定的FFprobe探测音频文件，结果按路径、修改时间和大小缓存
+func ProbeAudioFile(ctx context.Context, ffprobePath, filePath string) (*models.AudioFile, error) {
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	key := probeCacheKey{path: filePath, modTime: fileInfo.ModTime().UnixNano(), size: fileInfo.Size()}
+	probeCacheMu.Lock()
+	cached, ok := probeCache[key]
+	probeCacheMu.Unlock()
+	if ok {
+		return copyAudioFile(cached), nil
+	}
+
+	cmd := exec.CommandContext(ctx, ffprobePath,
+		"-v", "quiet",
+		"-show_format",
+		"-show_streams",
+		"-print_format", "json",
+		filePath,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("执行FFprobe失败: %w", err)
+	}
+
+	probe, err := ParseFFprobeOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	audioInfo := probe.ToAudioFile(filePath)
+	audioInfo.Size = fileInfo.Size()
+
+	probeCacheMu.Lock()
+	if len(probeCache) >= probeCacheLimit {
+		probeCache = make(map[probeCacheKey]*models.AudioFile)
+	}
+	probeCache[key] = audioInfo
+	probeCacheMu.Unlock()
+
+	return copyAudioFile(audioInfo), nil
+}
+
+// ProbeAudioFileWithAvailableFFprobe 依次尝试可用的FFprobe探测音频文件
+func ProbeAudioFileWithAvailableFFprobe(filePath string) (*models.AudioFile, error) {
+	var lastError error
+	for _, ffprobePath := range ffprobeCandidates() {
+		if _, err := exec.LookPath(ffprobePath); err != nil {
+			lastError = fmt.Errorf("FFprobe不存在: %v", err)
+			continue
+		}
+
+		audioInfo, err := ProbeAudioFile(context.Background(), ffprobePath, filePath)
+		if err == nil {
+			return audioInfo, nil
+		}
+		lastError = err
+		fmt.Printf("⚠️ FFprobe路径 %s 不可用: %v\n", ffprobePath, err)
+	}
+
+	return nil, fmt.Errorf("所有FFprobe路径都不可用，最后错误: %v", lastError)
+}
+
+// ffprobeCandidates 可能的FFprobe路径，按优先级排列
+func ffprobeCandidates() []string {
+	candidates := []string{
+		"ffprobe", // 系统PATH中查找
+		"/opt/homebrew/bin/ffprobe",
+		"/usr/local/bin/ffprobe",
+		"/usr/bin/ffprobe",
+	}
+
+	// 如果有FFmpegManager，优先使用其管理的FFprobe
+	if ffmpegManager, err := NewFFmpegManager(); err == nil {
+		if err := ffmpegManager.EnsureFFmpegAvailable(); err == nil {
+			candidates = append([]string{ffmpegManager.GetFFprobePath()}, candidates...)
+		}
+	}
+	return candidates
+}
+
+// ParseFFprobeOutput 解析FFprobe的JSON输出
+func ParseFFprobeOutput(output []byte) (*FFprobeOutput, error) {
+	var probe FFprobeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("解析FFprobe输出失败: %w", err)
+	}
+	if probe.Format.FormatName == "" && len(probe.Streams) == 0 {
+		return nil, fmt.Errorf("FFprobe返回空结果")
+	}
+	return &probe, nil
+}
+
+// AudioStream 获取默认音频流（没有默认标记时为第一个），没有音频流时返回nil
+func (o *FFprobeOutput) AudioStream() *FFprobeStream {
+	streams := o.AudioStreams()
+	for _, stream := range streams {
+		if stream.Disposition["default"] == 1 {
+			return stream
+		}
+	}
+	if len(streams) == 0 {
+		return nil
+	}
+	return streams[0]
+}
+
+// AudioStreams 获取全部音频流，顺序与FFmpeg的 0:a:N 一致
+func (o *FFprobeOutput) AudioStreams() []*FFprobeStream {
+	var streams []*FFprobeStream
+	for i := range o.Streams {
+		if o.Streams[i].CodecType == "audio" {
+			streams = append(streams, &o.Streams[i])
+		}
+	}
+	return streams
+}
+
+// VideoStream 获取第一个视频流，忽略音频文件中的封面图片，没有视频流时返回nil
+func (o *FFprobeOutput) VideoStream() *FFprobeStream {
+	for i := range o.Streams {
+		stream := &o.Streams[i]
+		if stream.CodecType == "video" && stream.Disposition["attached_pic"] == 0 {
+			return stream
+		}
+	}
+	return nil
+}
+
+// ToAudioFile 转换为音频文件信息
+func (o *FFprobeOutput) ToAudioFile(filePath string) *models.AudioFile {
+	audioInfo := &models.AudioFile{
+		Path:        filePath,
+		Name:        filepath.Base(filePath),
+		Format:      strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), "."),
+		Container:   o.Format.FormatName,
+		Duration:    parseFloatField(o.Format.Duration),
+		BitRate:     int(parseFloatField(o.Format.BitRate)),
+		StreamCount: o.Format.NbStreams,
+	}
+	if audioInfo.StreamCount == 0 {
+		audioInfo.StreamCount = len(o.Streams)
+	}
+	if audioInfo.Format == "" {
+		audioInfo.Format = strings.Split(o.Format.FormatName, ",")[0]
+	}
+
+	for i, stream := range o.AudioStreams() {
+		audioInfo.AudioStreams = append(audioInfo.AudioStreams, models.AudioStreamInfo{
+			Index:      i,
+			Codec:      stream.CodecName,
+			Language:   strings.ToLower(stream.Tags["language"]),
+			Title:      stream.Tags["title"],
+			SampleRate: int(parseFloatField(stream.SampleRate)),
+			Channels:   stream.Channels,
+			Default:    stream.Disposition["default"] == 1,
+		})
+		if stream == o.AudioStream() {
+			audioInfo.AudioStream = i
+		}
+	}
+
+	if stream := o.VideoStream(); stream != nil {
+		video := &models.VideoInfo{
+			Codec:         stream.CodecName,
+			Width:         stream.Width,
+			Height:        stream.Height,
+			FrameRateText: stream.RFrameRate,
+			Duration:      parseFloatField(stream.Duration),
+			FrameCount:    int(parseFloatField(stream.NbFrames)),
+		}
+		// r_frame_rate为0/0或异常值时（部分可变帧率文件）使用平均帧率
+		video.FrameRate = ParseFrameRate(stream.RFrameRate)
+		if video.FrameRate <= 0 || video.FrameRate > 240 {
+			video.FrameRateText = stream.AvgFrameRate
+			video.FrameRate = ParseFrameRate(stream.AvgFrameRate)
+		}
+		if video.Duration <= 0 {
+			video.Duration = audioInfo.Duration
+		}
+		audioInfo.Video = video
+	}
+
+	tags := make(map[string]string)
+	if stream := o.AudioStream(); stream != nil {
+		audioInfo.Codec = stream.CodecName
+		audioInfo.SampleRate = int(parseFloatField(stream.SampleRate))
+		audioInfo.Channels = stream.Channels
+		if audioInfo.Duration <= 0 {
+			audioInfo.Duration = parseFloatField(stream.Duration)
+		}
+		if audioInfo.BitRate <= 0 {
+			audioInfo.BitRate = int(parseFloatField(stream.BitRate))
+		}
+		mergeTags(tags, stream.Tags)
+	}
+	// 容器标签优先于流标签
+	mergeTags(tags, o.Format.Tags)
+	if len(tags) > 0 {
+		audioInfo.Tags = tags
+	}
+
+	return audioInfo
+}
+
+// mergeTags 合并标签，键统一为小写（不同格式的标签大小写不一致，如ID3的TITLE与MP4的title）
+func mergeTags(dst, src map[string]string) {
+	for key, value := range src {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		dst[strings.ToLower(key)] = value
+	}
+}
+
+// ParseFrameRate 解析FFprobe的帧率分数（如30000/1001），无效时返回0
+func ParseFrameRate(value string) float64 {
+	numerator, denominator, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return parseFloatField(numerator)
+	}
+	divisor := parseFloatField(denominator)
+	if divisor == 0 {
+		return 0
+	}
+	return parseFloatField(numerator) / divisor
+}
+
+// parseFloatField 解析FFprobe的数值字段，N/A或空值返回0
+func parseFloatField(value string) float64 {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// copyAudioFile 复制音频文件信息，避免调用方修改缓存内容
+func copyAudioFile(audioInfo *models.AudioFile) *models.AudioFile {
+	copied := *audioInfo
+	if audioInfo.Tags != nil {
+		copied.Tags = make(map[string]string, len(audioInfo.Tags))
+		for key, value := range audioInfo.Tags {
+			copied.Tags[key] = value
+		}
+	}
+	if audioInfo.AudioStreams != nil {
+		copied.AudioStreams = append([]models.AudioStreamInfo(nil), audioInfo.AudioStreams...)
+	}
+	if audioInfo.Video != nil {
+		video := *audioInfo.Video
+		copied.Video = &video
+	}
+	return &copied
+}
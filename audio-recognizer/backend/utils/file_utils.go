@@ -5,9 +5,40 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 )
 
-const MaxFileSize = 100 * 1024 * 1024 // 100MB
+// DefaultMaxFileSize 默认的文件大小上限，可通过配置 maxFileSizeMB 修改
+const DefaultMaxFileSize = 2 * 1024 * 1024 * 1024 // 2GB
+
+// MaxBase64FileSize 以Base64传入的文件的大小上限。Base64数据在前端和后端都整体解码在内存中，
+// 更大的文件需通过分块上传(BeginUpload)或文件路径传入
+const MaxBase64FileSize = 100 * 1024 * 1024 // 100MB
+
+// maxFileSize 当前生效的文件大小上限
+var maxFileSize atomic.Int64
+
+// SetMaxFileSize 设置文件大小上限（字节），不大于0时恢复默认值
+func SetMaxFileSize(size int64) {
+	if size <= 0 {
+		size = DefaultMaxFileSize
+	}
+	maxFileSize.Store(size)
+}
+
+// GetMaxFileSize 获取当前生效的文件大小上限（字节）
+func GetMaxFileSize() int64 {
+	if size := maxFileSize.Load(); size > 0 {
+		return size
+	}
+	return DefaultMaxFileSize
+}
+
+// AudioFileExtensions 支持的音频文件扩展名
+var AudioFileExtensions = []string{".mp3", ".wav", ".m4a", ".aac", ".ogg", ".flac"}
+
+// VideoFileExtensions 支持的视频文件扩展名（识别时由FFmpeg提取音轨）
+var VideoFileExtensions = []string{".mp4", ".m4v", ".mkv", ".mov", ".webm", ".avi"}
 
 // AudioFileValidationResult 音频文件验证结果
 type AudioFileValidationResult struct {
@@ -17,6 +48,7 @@ type AudioFileValidationResult struct {
 	ErrorMsg    string
 	SizeStr     string
 	Extension   string
+	IsVideo     bool
 }
 
 // ValidateAudioFile 验证音频文件
@@ -39,33 +71,71 @@ func ValidateAudioFile(filePath string) *AudioFileValidationResult {
 	result.FileInfo = fileInfo
 
 	// 检查文件大小
-	if fileInfo.Size() > MaxFileSize {
-		result.ErrorMsg = fmt.Sprintf("文件过大，最大支持 %s", FormatFileSize(MaxFileSize))
+	if limit := GetMaxFileSize(); fileInfo.Size() > limit {
+		result.ErrorMsg = fmt.Sprintf("文件过大，最大支持 %s", FormatFileSize(limit))
 		return result
 	}
 
 	// 检查文件格式
 	ext := strings.ToLower(filepath.Ext(filePath))
-	audioFormats := map[string]bool{
-		".mp3":  true,
-		".wav":  true,
-		".m4a":  true,
-		".aac":  true,
-		".ogg":  true,
-		".flac": true,
-	}
-
-	if !audioFormats[ext] {
-		result.ErrorMsg = "不支持的音频格式，请选择 MP3、WAV、M4A、AAC、OGG 或 FLAC 格式"
+	if !containsExtension(AudioFileExtensions, ext) && !containsExtension(VideoFileExtensions, ext) {
+		result.ErrorMsg = "不支持的文件格式，请选择 MP3、WAV、M4A、AAC、OGG、FLAC 音频或 MP4、MKV、MOV、WEBM 等视频"
 		return result
 	}
 
 	result.IsValid = true
 	result.SizeStr = FormatFileSize(fileInfo.Size())
 	result.Extension = ext
+	result.IsVideo = IsVideoFile(filePath)
 	return result
 }
 
+// IsVideoFile 根据扩展名判断是否为视频文件
+func IsVideoFile(filePath string) bool {
+	return containsExtension(VideoFileExtensions, strings.ToLower(filepath.Ext(filePath)))
+}
+
+// IsMediaFile 根据扩展名判断是否为支持的音频或视频文件
+func IsMediaFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return containsExtension(AudioFileExtensions, ext) || containsExtension(VideoFileExtensions, ext)
+}
+
+// FindMediaFiles 查找目录中支持的音频和视频文件（按路径排序），recursive为true时包含子目录，跳过隐藏文件和目录
+func FindMediaFiles(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		hidden := path != dir && strings.HasPrefix(entry.Name(), ".")
+		if entry.IsDir() {
+			if path != dir && (hidden || !recursive) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !hidden && IsMediaFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取文件夹失败: %w", err)
+	}
+	return files, nil
+}
+
+// containsExtension 判断扩展名是否在列表中
+func containsExtension(extensions []string, ext string) bool {
+	for _, candidate := range extensions {
+		if candidate == ext {
+			return true
+		}
+	}
+	return false
+}
+
 // GetMimeTypeFromExtension 根据扩展名获取MIME类型
 func GetMimeTypeFromExtension(ext string) string {
 	ext = strings.ToLower(ext)
@@ -80,6 +150,16 @@ func GetMimeTypeFromExtension(ext string) string {
 		return "audio/ogg"
 	case ".flac":
 		return "audio/flac"
+	case ".mp4", ".m4v":
+		return "video/mp4"
+	case ".mkv":
+		return "video/x-matroska"
+	case ".mov":
+		return "video/quicktime"
+	case ".webm":
+		return "video/webm"
+	case ".avi":
+		return "video/x-msvideo"
 	default:
 		return "audio/" + ext[1:]
 	}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SniffHeaderSize 识别文件格式读取的文件头字节数
+const SniffHeaderSize = 4096
+
+// SniffMediaExtension 根据文件头识别音视频格式，返回对应的扩展名（如 ".mp3"），无法识别时返回空字符串
+func SniffMediaExtension(header []byte) string {
+	switch {
+	case len(header) >= 12 && bytes.HasPrefix(header, []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return ".wav"
+	case len(header) >= 12 && bytes.HasPrefix(header, []byte("RIFF")) && bytes.Equal(header[8:12], []byte("AVI ")):
+		return ".avi"
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return ".flac"
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return ".ogg"
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return ".mp3"
+	case len(header) >= 12 && bytes.Equal(header[4:8], []byte("ftyp")):
+		return isoBrandExtension(string(header[8:12]))
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		// Matroska与WebM都是EBML容器，文件头中的DocType区分两者
+		if bytes.Contains(header[:min(len(header), 64)], []byte("webm")) {
+			return ".webm"
+		}
+		return ".mkv"
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xF6 == 0xF0:
+		return ".aac" // ADTS帧头（layer为0）
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		return ".mp3" // MPEG音频帧同步字
+	}
+	return ""
+}
+
+// isoBrandExtension 根据ISO媒体文件（MP4/MOV/M4A）的主品牌确定扩展名
+func isoBrandExtension(brand string) string {
+	switch brand {
+	case "M4A ", "M4B ", "M4P ":
+		return ".m4a"
+	case "qt  ":
+		return ".mov"
+	case "M4V ", "M4VH", "M4VP":
+		return ".m4v"
+	default:
+		return ".mp4"
+	}
+}
+
+// SniffMediaFile 读取文件头识别音视频格式，无法识别时返回错误
+func SniffMediaFile(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, SniffHeaderSize)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("读取文件头失败: %w", err)
+	}
+	ext := SniffMediaExtension(header[:n])
+	if ext == "" {
+		return "", fmt.Errorf("无法识别的文件格式")
+	}
+	return ext, nil
+}
@@ -136,10 +136,12 @@ func FormatSRTTime(seconds float64) string {
 		seconds = 0
 	}
 
-	hours := int(seconds) / 3600
-	minutes := (int(seconds) % 3600) / 60
-	secs := int(seconds) % 60
-	milliseconds := int((seconds - math.Floor(seconds)) * 1000)
+	// 按总毫秒数四舍五入，避免浮点误差把 1.999999 截断为 1.999；小时数可超过24
+	total := int64(math.Round(seconds * 1000))
+	hours := total / 3600000
+	minutes := (total % 3600000) / 60000
+	secs := (total % 60000) / 1000
+	milliseconds := total % 1000
 
 	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, secs, milliseconds)
 }
@@ -150,14 +152,45 @@ func FormatWebVTTTime(seconds float64) string {
 		seconds = 0
 	}
 
-	hours := int(seconds) / 3600
-	minutes := (int(seconds) % 3600) / 60
-	secs := int(seconds) % 60
-	milliseconds := int((seconds - math.Floor(seconds)) * 1000)
+	total := int64(math.Round(seconds * 1000))
+	hours := total / 3600000
+	minutes := (total % 3600000) / 60000
+	secs := (total % 60000) / 1000
+	milliseconds := total % 1000
 
 	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, secs, milliseconds)
 }
 
+// FormatSBVTime 格式化为YouTube SBV时间格式 (H:MM:SS.mmm)
+func FormatSBVTime(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	total := int64(math.Round(seconds * 1000))
+	hours := total / 3600000
+	minutes := (total % 3600000) / 60000
+	secs := (total % 60000) / 1000
+	milliseconds := total % 1000
+
+	return fmt.Sprintf("%d:%02d:%02d.%03d", hours, minutes, secs, milliseconds)
+}
+
+// FormatASSTime 格式化为ASS字幕时间格式 (H:MM:SS.cc)，精确到百分之一秒
+func FormatASSTime(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	total := int64(math.Round(seconds * 100))
+	hours := total / 360000
+	minutes := (total % 360000) / 6000
+	secs := (total % 6000) / 100
+	centiseconds := total % 100
+
+	return fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, secs, centiseconds)
+}
+
 // ContainsTimestamp 检查文本是否包含时间戳
 func ContainsTimestamp(text string) bool {
 	timestampPattern := `\[\d{2}:\d{2}:\d{2}\.\d{3}\]`
@@ -3,6 +3,8 @@
  * 从 App.vue 中提取出来的工具函数，用于减少主文件的复杂度
  */
 
+import { BeginUpload, AppendUpload, CommitUpload, CancelUpload } from '../../wailsjs/go/main/App'
+
 /**
  * 将文件转换为Base64编码
  * @param {File} file - 要转换的文件对象
@@ -24,6 +26,50 @@ export const fileToBase64 = (file) => {
   })
 }
 
+/**
+ * Base64方式传给后端的文件大小上限（与后端 MaxBase64FileSize 一致），更大的文件使用分块上传
+ */
+export const MAX_BASE64_FILE_SIZE = 100 * 1024 * 1024
+
+/**
+ * 分块上传文件到后端临时目录，避免整个文件以Base64经过WebView
+ * @param {File} file - 要上传的文件对象
+ * @param {Function} [onProgress] - 上传进度回调，参数为已上传的字节数
+ * @returns {Promise<string>} 后端临时文件路径，可作为识别请求的filePath
+ */
+export const uploadFileInChunks = async (file, onProgress) => {
+  const begin = await BeginUpload(file.name, file.size)
+  if (!begin || !begin.success) {
+    throw new Error(begin?.error || '开始上传失败')
+  }
+
+  const uploadId = begin.uploadId
+  try {
+    let offset = 0
+    while (offset < file.size) {
+      const chunk = file.slice(offset, offset + begin.chunkSize)
+      const chunkBase64 = await fileToBase64(chunk)
+      const result = await AppendUpload(uploadId, offset, chunkBase64, '')
+      if (!result || !result.success) {
+        throw new Error(result?.error || '上传分块失败')
+      }
+      offset = result.received
+      if (onProgress) {
+        onProgress(offset)
+      }
+    }
+
+    const commit = await CommitUpload(uploadId, '')
+    if (!commit || !commit.success) {
+      throw new Error(commit?.error || '完成上传失败')
+    }
+    return commit.file
+  } catch (error) {
+    await CancelUpload(uploadId)
+    throw error
+  }
+}
+
 /**
  * 格式化文件大小
  * @param {number} bytes - 文件大小（字节）
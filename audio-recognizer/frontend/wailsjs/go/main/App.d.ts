@@ -2,6 +2,14 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function AppendUpload(arg1:string,arg2:number,arg3:string,arg4:string):Promise<Record<string, any>>;
+
+export function BeginUpload(arg1:string,arg2:number):Promise<Record<string, any>>;
+
+export function CancelUpload(arg1:string):Promise<Record<string, any>>;
+
+export function CommitUpload(arg1:string,arg2:string):Promise<Record<string, any>>;
+
 export function ExportResult(arg1:string,arg2:string,arg3:string):Promise<main.RecognitionResponse>;
 
 export function GetAITemplates():Promise<Record<string, any>>;
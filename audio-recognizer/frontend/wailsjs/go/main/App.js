@@ -2,6 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AppendUpload(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['AppendUpload'](arg1, arg2, arg3, arg4);
+}
+
+export function BeginUpload(arg1, arg2) {
+  return window['go']['main']['App']['BeginUpload'](arg1, arg2);
+}
+
+export function CancelUpload(arg1) {
+  return window['go']['main']['App']['CancelUpload'](arg1);
+}
+
+export function CommitUpload(arg1, arg2) {
+  return window['go']['main']['App']['CommitUpload'](arg1, arg2);
+}
+
 export function ExportResult(arg1, arg2, arg3) {
   return window['go']['main']['App']['ExportResult'](arg1, arg2, arg3);
 }
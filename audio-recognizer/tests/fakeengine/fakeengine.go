@@ -0,0 +1,392 @@
+// Package fakeengine 按脚本输出结果的识别引擎，只用于测试，生产代码不引用本包
+package fakeengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tingshengbianzi/backend/audio"
+	"tingshengbianzi/backend/models"
+	"tingshengbianzi/backend/recognition"
+)
+
+// Script 脚本化识别引擎的输出定义，用于无模型环境下的集成测试
+type Script struct {
+	Language           string                            `json:"language"`           // 结果语言，留空时使用请求语言
+	DetectedLanguage   string                            `json:"detectedLanguage"`   // 模拟自动检测的Whisper语言代码
+	Duration           float64                           `json:"duration"`           // 音频时长（秒），留空时取最后一段的结束时间
+	Segments           []models.RecognitionResultSegment `json:"segments"`           // 识别段落
+	TranslatedSegments []models.RecognitionResultSegment `json:"translatedSegments"` // translate/bilingual任务输出的英文段落，留空时使用segments
+	ProgressSteps      []int                             `json:"progressSteps"`      // 依次上报的进度百分比
+	StepDelayMs        int                               `json:"stepDelayMs"`        // 每个进度步骤之间的延迟（毫秒）
+	EmitPartials       bool                              `json:"emitPartials"`       // 是否随进度输出实时段落
+	ErrorCode          string                            `json:"errorCode"`          // 非空时识别失败并返回该错误代码
+	ErrorMessage       string                            `json:"errorMessage"`       // 失败时的错误消息
+}
+
+// Call 脚本化识别引擎收到的一次识别调用
+type Call struct {
+	AudioPath   string
+	Language    string
+	ModelFile   string
+	Task        string
+	Decoding    models.DecodingOptions
+	Diarization string
+	AudioTrack  *int
+	TimeRanges  []models.TimeRange
+}
+
+// Service 按脚本输出结果的识别服务，不依赖模型和外部程序
+type Service struct {
+	mu     sync.Mutex
+	script Script
+	config *models.RecognitionConfig
+	loaded map[string]string
+	calls  []Call
+	closed bool
+}
+
+// NewService 创建脚本化识别服务
+func NewService(script Script, config *models.RecognitionConfig) *Service {
+	return &Service{
+		script: script,
+		config: config,
+		loaded: make(map[string]string),
+	}
+}
+
+// LoadScript 从JSON文件读取识别脚本
+func LoadScript(scriptPath string) (Script, error) {
+	var script Script
+	content, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return script, fmt.Errorf("读取识别脚本失败: %w", err)
+	}
+	if err := json.Unmarshal(content, &script); err != nil {
+		return script, fmt.Errorf("解析识别脚本失败: %w", err)
+	}
+	return script, nil
+}
+
+// Descriptor 生成脚本化引擎的注册描述，测试中通过 recognition.RegisterEngine 注册后即可按名称使用
+func Descriptor(name string, script Script) recognition.EngineDescriptor {
+	var languages []string
+	for _, lang := range recognition.GetLanguageTable() {
+		languages = append(languages, lang.Code)
+	}
+	return recognition.EngineDescriptor{
+		Name:        name,
+		DisplayName: "Fake Engine",
+		Description: "按脚本输出结果的测试引擎",
+		Priority:    -100, // 不参与自动选择
+		Capabilities: recognition.EngineCapabilities{
+			Languages:      languages,
+			WordTimestamps: true,
+			Diarization:    true,
+			Streaming:      true,
+			Translation:    true,
+		},
+		Factory: func(config *models.RecognitionConfig) (recognition.RecognitionService, error) {
+			return NewService(script, config), nil
+		},
+	}
+}
+
+// LoadModel 记录加载的模型
+func (s *Service) LoadModel(language, modelPath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loaded[language] = modelPath
+	return nil
+}
+
+// RecognizeFile 按脚本上报进度并返回结果
+func (s *Service) RecognizeFile(ctx context.Context, audioPath string, language string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	return s.RecognizeFileWithModel(ctx, audioPath, language, "", progressCallback)
+}
+
+// RecognizeFileWithModel 按脚本上报进度并返回结果，记录使用的模型文件
+func (s *Service) RecognizeFileWithModel(ctx context.Context, audioPath string, language string, specificModelFile string, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	return s.RecognizeFileWithOptions(ctx, audioPath, language, models.RecognitionOptions{SpecificModelFile: specificModelFile}, progressCallback)
+}
+
+// RecognizeFileWithOptions 按脚本上报进度并返回结果，记录使用的模型文件和任务类型
+// 脚本段落不经过词汇纠正和说话人聚类（没有音频可分析），启用说话人分离时保留段落的speaker字段
+func (s *Service) RecognizeFileWithOptions(ctx context.Context, audioPath string, language string, options models.RecognitionOptions, progressCallback func(*models.RecognitionProgress)) (*models.RecognitionResult, error) {
+	s.mu.Lock()
+	call, err := resolveCall(s.config, audioPath, language, options)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.calls = append(s.calls, call)
+	script := s.script
+	s.mu.Unlock()
+
+	duration := script.Duration
+	if duration <= 0 && len(script.Segments) > 0 {
+		duration = script.Segments[len(script.Segments)-1].End
+	}
+	timeRanges, err := audio.ResolveTimeRanges(call.TimeRanges, duration)
+	if err != nil {
+		return nil, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的时间范围", err.Error())
+	}
+
+	for i, percentage := range script.ProgressSteps {
+		if script.StepDelayMs > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, newCancelledError(ctx)
+			case <-time.After(time.Duration(script.StepDelayMs) * time.Millisecond):
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, newCancelledError(ctx)
+		}
+
+		progress := &models.RecognitionProgress{
+			CurrentTime: duration * float64(percentage) / 100.0,
+			TotalTime:   duration,
+			Percentage:  percentage,
+			Status:      "正在识别语音...",
+		}
+		if script.EmitPartials && i < len(script.Segments) {
+			partial := script.Segments[i]
+			progress.Partial = &partial
+		}
+		if progressCallback != nil {
+			progressCallback(progress)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, newCancelledError(ctx)
+	}
+	if script.ErrorCode != "" {
+		return nil, models.NewRecognitionError(script.ErrorCode, script.ErrorMessage, audioPath)
+	}
+
+	translated := script.TranslatedSegments
+	if len(translated) == 0 {
+		translated = script.Segments
+	}
+	source := script.Segments
+	if call.Task == models.TaskTranslate {
+		source = translated
+	}
+	segments := scriptSegments(source, timeRanges, call.Diarization != recognition.DiarizationOff)
+	if call.Task == models.TaskBilingual {
+		// 译文按序号对应原文段落
+		for i := range segments {
+			if i < len(translated) {
+				segments[i].Translation = translated[i].Text
+			}
+		}
+	}
+
+	result := buildResult(segments, audioPath, language, duration)
+	whisperLang, _ := recognition.MapLanguageToWhisper(language)
+	result.Metadata["requested_language"] = language
+	result.Metadata["whisper_language"] = whisperLang
+	if whisperLang == recognition.LanguageAuto && script.DetectedLanguage != "" {
+		result.Language = recognition.MapWhisperToLanguage(script.DetectedLanguage)
+		result.Metadata["detected_language"] = script.DetectedLanguage
+	}
+	if script.Language != "" {
+		result.Language = script.Language
+	}
+	result.Metadata["task"] = call.Task
+	result.Metadata["decoding"] = call.Decoding
+	if call.ModelFile != "" {
+		result.Metadata["model_file"] = call.ModelFile
+	}
+	if len(timeRanges) > 0 {
+		result.Metadata["time_ranges"] = timeRanges
+	}
+
+	if progressCallback != nil {
+		progressCallback(&models.RecognitionProgress{
+			CurrentTime: duration,
+			TotalTime:   duration,
+			Percentage:  100,
+			Status:      "语音识别完成",
+		})
+	}
+	return result, nil
+}
+
+// resolveCall 校验识别选项，未指定的选项使用配置中的值
+func resolveCall(config *models.RecognitionConfig, audioPath, language string, options models.RecognitionOptions) (Call, error) {
+	task, err := recognition.NormalizeTask(options.Task)
+	if err != nil {
+		return Call{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的识别任务", err.Error())
+	}
+
+	decoding := config.Decoding
+	if options.Decoding != nil {
+		decoding = *options.Decoding
+	}
+	decoding, err = recognition.ResolveDecodingOptions(decoding)
+	if err != nil {
+		return Call{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的解码参数", err.Error())
+	}
+
+	diarization := config.Diarization
+	if options.Diarization != nil {
+		diarization = *options.Diarization
+	}
+	diarization, err = recognition.ResolveDiarizationConfig(diarization)
+	if err != nil {
+		return Call{}, models.NewRecognitionError(models.ErrorCodeInvalidConfig, "无效的说话人分离参数", err.Error())
+	}
+
+	return Call{
+		AudioPath:   audioPath,
+		Language:    language,
+		ModelFile:   options.SpecificModelFile,
+		Task:        task,
+		Decoding:    decoding,
+		Diarization: diarization.Mode,
+		AudioTrack:  options.AudioTrack,
+		TimeRanges:  options.TimeRanges,
+	}, nil
+}
+
+// scriptSegments 复制脚本段落（含词汇和元数据），后续处理不修改脚本内容；指定时间范围时只保留中点落在范围内的段落
+func scriptSegments(source []models.RecognitionResultSegment, timeRanges []models.TimeRange, keepSpeakers bool) []models.RecognitionResultSegment {
+	segments := make([]models.RecognitionResultSegment, 0, len(source))
+	for _, segment := range source {
+		if !inTimeRanges(timeRanges, (segment.Start+segment.End)/2) {
+			continue
+		}
+		segment.Words = append([]models.Word{}, segment.Words...)
+		metadata := make(map[string]interface{}, len(segment.Metadata))
+		for key, value := range segment.Metadata {
+			metadata[key] = value
+		}
+		segment.Metadata = metadata
+		if !keepSpeakers {
+			segment.Speaker = ""
+			for i := range segment.Words {
+				segment.Words[i].Speaker = ""
+			}
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// inTimeRanges 判断时间点是否在任一时间范围内，没有时间范围时视为整个文件
+func inTimeRanges(timeRanges []models.TimeRange, position float64) bool {
+	if len(timeRanges) == 0 {
+		return true
+	}
+	for _, r := range timeRanges {
+		if position >= r.Start && position < r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// buildResult 由段落组装识别结果
+func buildResult(segments []models.RecognitionResultSegment, audioPath, language string, duration float64) *models.RecognitionResult {
+	result := &models.RecognitionResult{
+		ID:          fmt.Sprintf("fake_%d", time.Now().UnixNano()),
+		Language:    language,
+		Segments:    segments,
+		Words:       []models.Word{},
+		Duration:    duration,
+		ProcessedAt: time.Now(),
+		Metadata: map[string]interface{}{
+			"audio_file":       filepath.Base(audioPath),
+			"audio_format":     "wav",
+			"recognition_type": "fake",
+		},
+	}
+
+	var texts []string
+	var confidence float64
+	for _, segment := range segments {
+		texts = append(texts, segment.Text)
+		result.Words = append(result.Words, segment.Words...)
+		confidence += segment.Confidence
+	}
+	result.Text = strings.Join(texts, " ")
+	result.TimestampedText = result.Text
+	if len(segments) > 0 {
+		result.Confidence = confidence / float64(len(segments))
+	}
+	return result
+}
+
+// newCancelledError 识别被取消的错误
+func newCancelledError(ctx context.Context) error {
+	return models.NewRecognitionError(
+		models.ErrorCodeRecognitionCancelled,
+		"语音识别已取消",
+		ctx.Err().Error(),
+	)
+}
+
+// Calls 获取收到的识别调用记录
+func (s *Service) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, len(s.calls))
+	copy(calls, s.calls)
+	return calls
+}
+
+// IsClosed 检查服务是否已关闭
+func (s *Service) IsClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (s *Service) GetSupportedLanguages() []string {
+	var languages []string
+	for _, lang := range recognition.GetLanguageTable() {
+		languages = append(languages, lang.Code)
+	}
+	return languages
+}
+
+// IsModelLoaded 检查模型是否已加载
+func (s *Service) IsModelLoaded(language string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.loaded[language]
+	return ok
+}
+
+// UnloadModel 卸载语音模型
+func (s *Service) UnloadModel(language string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.loaded, language)
+	return nil
+}
+
+// UpdateConfig 更新配置
+func (s *Service) UpdateConfig(config *models.RecognitionConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = config
+}
+
+// Close 关闭服务
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,348 @@
+// fakewhisper 是whisper-cli的替身程序，用于无模型环境下的集成测试
+//
+// 它接受与whisper-cli相同的命令行参数，按脚本输出进度、实时段落、
+// 语言检测结果，并生成 -ojf/-osrt 对应的JSON和SRT文件。
+// 脚本通过环境变量 FAKE_WHISPER_SCRIPT 或参数 --fake-script 指定（JSON文件），
+// 未指定时输出内置的默认脚本。
+//
+// 构建: go build -o third-party/bin/whisper-cli ./tests/fakewhisper
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fakeScript 输出脚本
+type fakeScript struct {
+	Segments           []fakeSegment `json:"segments"`           // 识别段落
+	TranslatedSegments []fakeSegment `json:"translatedSegments"` // -tr 时输出的英文段落，留空时输出segments
+	DetectedLanguage   string        `json:"detectedLanguage"`   // -l auto 时输出的检测语言
+	Probability        float64       `json:"probability"`        // 检测语言概率
+	ProgressSteps      []int         `json:"progressSteps"`      // -pp 时输出的进度
+	DelayMs            int           `json:"delayMs"`            // 每个进度步骤之间的延迟（毫秒），用于测试取消
+	ExitCode           int           `json:"exitCode"`           // 非0时输出Stderr后以该退出码退出
+	Stderr             string        `json:"stderr"`             // 失败时的错误输出
+	NoJSON             bool          `json:"noJSON"`             // 不生成JSON文件（测试SRT回退）
+	NoSRT              bool          `json:"noSRT"`              // 不生成SRT文件
+	SplitMultibyte     bool          `json:"splitMultibyte"`     // 将多字节字符拆到两个token中（模拟真实whisper-cli输出）
+}
+
+// fakeSegment 脚本中的段落
+type fakeSegment struct {
+	Start           float64 `json:"start"`
+	End             float64 `json:"end"`
+	Text            string  `json:"text"`
+	Speaker         string  `json:"speaker"`         // -di 时输出的声道: "0" | "1"，留空为"?"
+	SpeakerTurnNext bool    `json:"speakerTurnNext"` // -tdrz 时下一段是否换人
+}
+
+// fakeArgs 解析后的命令行参数
+type fakeArgs struct {
+	model         string
+	input         string
+	language      string
+	outputBase    string
+	scriptPath    string
+	outputJSON    bool
+	outputSRT     bool
+	printProgress bool
+	translate     bool
+	diarize       bool
+	tinydiarize   bool
+}
+
+// valueFlags 需要参数值的whisper-cli选项
+var valueFlags = map[string]bool{
+	"-m": true, "--model": true, "-f": true, "--file": true, "-l": true, "--language": true,
+	"-of": true, "--output-file": true, "-t": true, "--threads": true, "-p": true, "--processors": true,
+	"-bs": true, "--beam-size": true, "-bo": true, "--best-of": true, "--prompt": true,
+	"-ml": true, "--max-len": true, "-tp": true, "--temperature": true, "-tpi": true, "--temperature-inc": true,
+	"-et": true, "--entropy-thold": true, "-lpt": true, "--logprob-thold": true, "-nth": true, "--no-speech-thold": true,
+	"-ot": true, "--offset-t": true, "-d": true, "--duration": true, "--fake-script": true,
+}
+
+func main() {
+	args := parseArgs(os.Args[1:])
+
+	script, err := loadScript(args.scriptPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fakewhisper: %v\n", err)
+		os.Exit(2)
+	}
+
+	if args.translate && len(script.TranslatedSegments) > 0 {
+		script.Segments = script.TranslatedSegments
+	}
+
+	fmt.Fprintf(os.Stderr, "whisper_init_from_file_with_params_no_state: loading model from '%s'\n", args.model)
+
+	if script.ExitCode != 0 {
+		fmt.Fprintln(os.Stderr, script.Stderr)
+		os.Exit(script.ExitCode)
+	}
+
+	if args.language == "auto" || args.language == "" {
+		fmt.Fprintf(os.Stderr, "whisper_full_with_state: auto-detected language: %s (p = %f)\n", script.DetectedLanguage, script.Probability)
+	}
+
+	emitProgress(args, script)
+
+	if args.outputJSON && !script.NoJSON && args.outputBase != "" {
+		if err := os.WriteFile(args.outputBase+".json", buildJSON(args, script), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "fakewhisper: 写入JSON失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if args.outputSRT && !script.NoSRT && args.outputBase != "" {
+		if err := os.WriteFile(args.outputBase+".srt", buildSRT(args, script), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "fakewhisper: 写入SRT失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// parseArgs 解析whisper-cli参数，未知选项按布尔选项忽略
+func parseArgs(argv []string) fakeArgs {
+	args := fakeArgs{language: "en", scriptPath: os.Getenv("FAKE_WHISPER_SCRIPT")}
+	for i := 0; i < len(argv); i++ {
+		flag := argv[i]
+		value := ""
+		if valueFlags[flag] && i+1 < len(argv) {
+			value = argv[i+1]
+			i++
+		}
+
+		switch flag {
+		case "-m", "--model":
+			args.model = value
+		case "-f", "--file":
+			args.input = value
+		case "-l", "--language":
+			args.language = value
+		case "-of", "--output-file":
+			args.outputBase = value
+		case "--fake-script":
+			args.scriptPath = value
+		case "-ojf", "--output-json-full", "-oj", "--output-json":
+			args.outputJSON = true
+		case "-osrt", "--output-srt":
+			args.outputSRT = true
+		case "-pp", "--print-progress":
+			args.printProgress = true
+		case "-tr", "--translate":
+			args.translate = true
+		case "-di", "--diarize":
+			args.diarize = true
+		case "-tdrz", "--tinydiarize":
+			args.tinydiarize = true
+		}
+	}
+	return args
+}
+
+// loadScript 读取脚本并补全默认值
+func loadScript(scriptPath string) (*fakeScript, error) {
+	script := &fakeScript{}
+	if scriptPath != "" {
+		content, err := os.ReadFile(scriptPath)
+		if err != nil {
+			return nil, fmt.Errorf("读取脚本失败: %w", err)
+		}
+		if err := json.Unmarshal(content, script); err != nil {
+			return nil, fmt.Errorf("解析脚本失败: %w", err)
+		}
+	}
+
+	if len(script.Segments) == 0 {
+		script.Segments = []fakeSegment{
+			{Start: 0, End: 2.5, Text: "这是一段测试音频。"},
+			{Start: 2.5, End: 5, Text: "Hello world, this is a test."},
+		}
+	}
+	if script.DetectedLanguage == "" {
+		script.DetectedLanguage = "zh"
+	}
+	if script.Probability == 0 {
+		script.Probability = 0.95
+	}
+	if len(script.ProgressSteps) == 0 {
+		script.ProgressSteps = []int{0, 25, 50, 75, 100}
+	}
+	return script, nil
+}
+
+// emitProgress 输出进度（stderr）和实时段落（stdout），段落按进度均匀穿插
+func emitProgress(args fakeArgs, script *fakeScript) {
+	nextSegment := 0
+	for i, percentage := range script.ProgressSteps {
+		if script.DelayMs > 0 {
+			time.Sleep(time.Duration(script.DelayMs) * time.Millisecond)
+		}
+		if args.printProgress {
+			fmt.Fprintf(os.Stderr, "whisper_print_progress_callback: progress = %3d%%\n", percentage)
+		}
+
+		due := len(script.Segments) * (i + 1) / len(script.ProgressSteps)
+		for ; nextSegment < due; nextSegment++ {
+			seg := script.Segments[nextSegment]
+			turn := ""
+			if args.tinydiarize && seg.SpeakerTurnNext {
+				turn = " [SPEAKER_TURN]"
+			}
+			fmt.Printf("[%s --> %s]  %s%s%s\n", formatTimestamp(seg.Start, "."), formatTimestamp(seg.End, "."), speakerPrefix(args, seg), seg.Text, turn)
+		}
+	}
+}
+
+// buildSRT 生成SRT文件内容
+func buildSRT(args fakeArgs, script *fakeScript) []byte {
+	var buf bytes.Buffer
+	for i, seg := range script.Segments {
+		fmt.Fprintf(&buf, "%d\n%s --> %s\n%s %s\n\n", i+1, formatTimestamp(seg.Start, ","), formatTimestamp(seg.End, ","), speakerPrefix(args, seg), seg.Text)
+	}
+	return buf.Bytes()
+}
+
+// speakerPrefix -di 时whisper-cli在控制台和SRT文本前添加的说话人标记
+func speakerPrefix(args fakeArgs, seg fakeSegment) string {
+	if !args.diarize {
+		return ""
+	}
+	return fmt.Sprintf("(speaker %s)", speakerChannel(seg))
+}
+
+// speakerChannel 段落的声道，未指定时为"?"
+func speakerChannel(seg fakeSegment) string {
+	if seg.Speaker == "" {
+		return "?"
+	}
+	return seg.Speaker
+}
+
+// buildJSON 生成与 -ojf 相同结构的JSON，token文本可能包含不完整的UTF-8字节
+func buildJSON(args fakeArgs, script *fakeScript) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "{\n\t\"model\": {\"type\": \"fake\"},\n")
+	fmt.Fprintf(&buf, "\t\"params\": {\"model\": %s, \"language\": %s, \"translate\": %v},\n", jsonString([]byte(args.model)), jsonString([]byte(args.language)), args.translate)
+
+	resultLanguage := args.language
+	if resultLanguage == "auto" || resultLanguage == "" {
+		resultLanguage = script.DetectedLanguage
+	}
+	fmt.Fprintf(&buf, "\t\"result\": {\"language\": %s},\n", jsonString([]byte(resultLanguage)))
+	buf.WriteString("\t\"transcription\": [\n")
+
+	for i, seg := range script.Segments {
+		from, to := millis(seg.Start), millis(seg.End)
+		fmt.Fprintf(&buf, "\t\t{\"offsets\": {\"from\": %d, \"to\": %d}, \"text\": %s, \"tokens\": [", from, to, jsonString([]byte(" "+seg.Text)))
+
+		tokens := splitTokens(seg.Text, script.SplitMultibyte)
+		fmt.Fprintf(&buf, "{\"text\": \"[_BEG_]\", \"offsets\": {\"from\": %d, \"to\": %d}, \"id\": 50365, \"p\": 0.99}", from, from)
+		step := float64(to-from) / float64(max(len(tokens), 1))
+		for j, token := range tokens {
+			tokenFrom := from + int64(float64(j)*step)
+			tokenTo := from + int64(float64(j+1)*step)
+			fmt.Fprintf(&buf, ", {\"text\": %s, \"offsets\": {\"from\": %d, \"to\": %d}, \"id\": %d, \"p\": 0.9}", jsonString(token), tokenFrom, tokenTo, 1000+j)
+		}
+		buf.WriteString("]")
+		if args.diarize {
+			fmt.Fprintf(&buf, ", \"speaker\": %s", jsonString([]byte(speakerChannel(seg))))
+		}
+		if args.tinydiarize {
+			fmt.Fprintf(&buf, ", \"speaker_turn_next\": %v", seg.SpeakerTurnNext)
+		}
+		buf.WriteString("}")
+		if i < len(script.Segments)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\t]\n}\n")
+	return buf.Bytes()
+}
+
+// splitTokens 按whisper的习惯切分token：中日韩文字逐字，其余按空格分词（词首带空格）
+func splitTokens(text string, splitMultibyte bool) [][]byte {
+	var tokens [][]byte
+	var word []byte
+
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, word)
+			word = nil
+		}
+	}
+
+	leadingSpace := true
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flushWord()
+			leadingSpace = true
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flushWord()
+			encoded := utf8.AppendRune(nil, r)
+			if splitMultibyte && len(encoded) > 1 {
+				tokens = append(tokens, encoded[:1], encoded[1:])
+			} else {
+				tokens = append(tokens, encoded)
+			}
+			leadingSpace = false
+		case unicode.IsPunct(r):
+			flushWord()
+			tokens = append(tokens, utf8.AppendRune(nil, r))
+			leadingSpace = false
+		default:
+			if len(word) == 0 && leadingSpace {
+				word = append(word, ' ')
+			}
+			word = utf8.AppendRune(word, r)
+			leadingSpace = false
+		}
+	}
+	flushWord()
+	return tokens
+}
+
+// jsonString 转义为JSON字符串，保留不完整的UTF-8字节（与whisper-cli行为一致）
+func jsonString(raw []byte) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, c := range raw {
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c < 0x20:
+			fmt.Fprintf(&buf, `\u%04x`, c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// formatTimestamp 格式化为 hh:mm:ss<sep>mmm
+func formatTimestamp(seconds float64, sep string) string {
+	total := millis(seconds)
+	h := total / 3600000
+	m := total % 3600000 / 60000
+	s := total % 60000 / 1000
+	ms := total % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms)
+}
+
+// millis 秒转换为毫秒
+func millis(seconds float64) int64 {
+	return int64(seconds*1000 + 0.5)
+}